@@ -239,7 +239,12 @@ func (c *AssociationController) GetNextEvent(ctx echo.Context) error {
 		return ctx.NoContent(http.StatusBadRequest)
 	}
 
-	event, err := c.AssociationService.GetNextEvent(associationID)
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	event, err := c.AssociationService.GetNextEvent(associationID, &user)
 	if err != nil {
 		ctx.Logger().Error(err)
 		return ctx.NoContent(http.StatusInternalServerError)
@@ -259,9 +264,14 @@ func (c *AssociationController) GetAssociationEvents(ctx echo.Context) error {
 		return ctx.NoContent(http.StatusBadRequest)
 	}
 
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
 	pagination := utils.PaginationFromContext(ctx)
 
-	events, err := c.AssociationService.GetAssociationEvents(associationID, pagination)
+	events, err := c.AssociationService.GetAssociationEvents(associationID, &user, pagination)
 	if err != nil {
 		ctx.Logger().Error(err)
 		return ctx.NoContent(http.StatusInternalServerError)
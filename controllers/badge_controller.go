@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/resources"
+	"backend/services"
+	"backend/utils"
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type BadgeController struct {
+	BadgeService *services.BadgeService
+}
+
+func NewBadgeController() *BadgeController {
+	return &BadgeController{
+		BadgeService: services.NewBadgeService(),
+	}
+}
+
+// GetBadges renvoie les définitions de badges
+func (c *BadgeController) GetBadges(ctx echo.Context) error {
+	badges, err := c.BadgeService.GetBadges()
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, badges)
+}
+
+// GetMyBadges renvoie les badges obtenus par l'utilisateur connecté
+func (c *BadgeController) GetMyBadges(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	userBadges, err := c.BadgeService.GetUserBadges(user.ID)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	badges := make([]resources.BadgeResource, len(userBadges))
+	for i, userBadge := range userBadges {
+		badges[i] = resources.NewBadgeResource(userBadge)
+	}
+	return ctx.JSON(http.StatusOK, badges)
+}
+
+// CreateBadge enregistre une définition de badge, attribuée aussitôt aux utilisateurs qui remplissent sa règle
+func (c *BadgeController) CreateBadge(ctx echo.Context) error {
+	var badge models.Badge
+	if err := ctx.Bind(&badge); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+
+	if err := c.BadgeService.CreateBadge(&badge); err != nil {
+		return badgeErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, badge)
+}
+
+// UpdateBadge modifie une définition de badge
+func (c *BadgeController) UpdateBadge(ctx echo.Context) error {
+	badge, err := c.BadgeService.GetBadge(ctx.Param("id"))
+	if err != nil {
+		return badgeErrorResponse(ctx, err)
+	}
+
+	var changes models.Badge
+	if err := ctx.Bind(&changes); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+
+	if err := c.BadgeService.UpdateBadge(badge, &changes); err != nil {
+		return badgeErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, badge)
+}
+
+// DeleteBadge supprime une définition de badge et les badges attribués
+func (c *BadgeController) DeleteBadge(ctx echo.Context) error {
+	badge, err := c.BadgeService.GetBadge(ctx.Param("id"))
+	if err != nil {
+		return badgeErrorResponse(ctx, err)
+	}
+
+	if err := c.BadgeService.DeleteBadge(badge); err != nil {
+		return badgeErrorResponse(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func badgeErrorResponse(ctx echo.Context, err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return ctx.JSON(http.StatusUnprocessableEntity, utils.GetValidationErrors(validationErrs, models.Badge{}))
+	}
+
+	switch {
+	case errors.Is(err, coreErrors.ErrNotFound):
+		return ctx.JSON(http.StatusNotFound, "Badge introuvable")
+	case errors.Is(err, coreErrors.ErrInvalidBadgeCategory):
+		return ctx.JSON(http.StatusUnprocessableEntity, "La catégorie du badge n'existe pas")
+	}
+	ctx.Logger().Error(err)
+	return ctx.NoContent(http.StatusInternalServerError)
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/services"
+	"fmt"
+	"net/http"
+
+	ics "github.com/arran4/golang-ical"
+	"github.com/labstack/echo/v4"
+)
+
+type CalendarController struct {
+	CalendarService    *services.CalendarService
+	AssociationService *services.AssociationService
+}
+
+func NewCalendarController() *CalendarController {
+	return &CalendarController{
+		CalendarService:    services.NewCalendarService(),
+		AssociationService: services.NewAssociationService(),
+	}
+}
+
+// GetUserFeed sert le flux iCalendar d'un utilisateur, authentifié uniquement par le jeton de l'URL
+func (c *CalendarController) GetUserFeed(ctx echo.Context) error {
+	user, err := c.CalendarService.GetUserByCalendarToken(ctx.Param("token"))
+	if err != nil {
+		return ctx.NoContent(http.StatusNotFound)
+	}
+
+	calendar, err := c.CalendarService.BuildUserFeed(user)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return sendCalendar(ctx, calendar)
+}
+
+// GetAssociationFeed sert le flux iCalendar des événements à venir d'une association
+func (c *CalendarController) GetAssociationFeed(ctx echo.Context) error {
+	association, err := c.CalendarService.GetAssociationByCalendarToken(ctx.Param("token"))
+	if err != nil {
+		return ctx.NoContent(http.StatusNotFound)
+	}
+
+	calendar, err := c.CalendarService.BuildAssociationFeed(association)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return sendCalendar(ctx, calendar)
+}
+
+func (c *CalendarController) GetUserCalendar(ctx echo.Context) error {
+	user := ctx.Get("user").(models.User)
+
+	token, err := c.CalendarService.GetUserCalendarToken(&user)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, calendarFeedResponse(ctx, "users", token))
+}
+
+func (c *CalendarController) RegenerateUserCalendarToken(ctx echo.Context) error {
+	user := ctx.Get("user").(models.User)
+
+	token, err := c.CalendarService.RegenerateUserCalendarToken(&user)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, calendarFeedResponse(ctx, "users", token))
+}
+
+func (c *CalendarController) GetAssociationCalendar(ctx echo.Context) error {
+	association, err := c.AssociationService.GetAssociationById(ctx.Param("associationId"))
+	if err != nil {
+		return ctx.NoContent(http.StatusNotFound)
+	}
+
+	token, err := c.CalendarService.GetAssociationCalendarToken(association)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, calendarFeedResponse(ctx, "associations", token))
+}
+
+func (c *CalendarController) RegenerateAssociationCalendarToken(ctx echo.Context) error {
+	association, err := requireAssociationOwner(ctx, ctx.Param("associationId"))
+	if err != nil {
+		return err
+	}
+
+	token, err := c.CalendarService.RegenerateAssociationCalendarToken(association)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, calendarFeedResponse(ctx, "associations", token))
+}
+
+func sendCalendar(ctx echo.Context, calendar *ics.Calendar) error {
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, `inline; filename="events.ics"`)
+	return ctx.Blob(http.StatusOK, "text/calendar; charset=utf-8", []byte(calendar.Serialize()))
+}
+
+func calendarFeedResponse(ctx echo.Context, owner string, token string) map[string]string {
+	return map[string]string{
+		"token": token,
+		"url":   fmt.Sprintf("%s://%s/calendars/feeds/%s/%s/events.ics", ctx.Scheme(), ctx.Request().Host, owner, token),
+	}
+}
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type EventCoHostController struct {
+	EventService  *services.EventService
+	CoHostService *services.EventCoHostService
+}
+
+func NewEventCoHostController() *EventCoHostController {
+	return &EventCoHostController{
+		EventService:  services.NewEventService(),
+		CoHostService: services.NewEventCoHostService(),
+	}
+}
+
+// GetCoHosts renvoie les associations invitées à co-organiser l'événement et leur réponse
+func (c *EventCoHostController) GetCoHosts(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	coHosts, err := c.CoHostService.GetCoHosts(event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, coHosts)
+}
+
+// InviteCoHost invite une association à co-organiser l'événement ; réservé au responsable de l'association organisatrice
+func (c *EventCoHostController) InviteCoHost(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+	if _, err := requireAssociationOwner(ctx, event.AssociationID); err != nil {
+		return err
+	}
+
+	var body struct {
+		AssociationID string `json:"association_id"`
+	}
+	if err := ctx.Bind(&body); err != nil || body.AssociationID == "" {
+		return ctx.JSON(http.StatusBadRequest, "Association manquante")
+	}
+
+	coHost, err := c.CoHostService.InviteCoHost(event, body.AssociationID, user.ID)
+	if err != nil {
+		return coHostErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, coHost)
+}
+
+// RemoveCoHost retire une association des co-organisatrices : l'association organisatrice peut retirer
+// n'importe quelle co-organisatrice, une co-organisatrice peut se retirer elle-même
+func (c *EventCoHostController) RemoveCoHost(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	associationID := ctx.Param("associationId")
+	if !enums.IsAdmin(user.Role) && event.Association.OwnerID != user.ID {
+		if _, err := requireAssociationOwner(ctx, associationID); err != nil {
+			return err
+		}
+	}
+
+	if err := c.CoHostService.RemoveCoHost(event, associationID); err != nil {
+		return coHostErrorResponse(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// AcceptInvitation accepte, au nom de l'association invitée, de co-organiser l'événement
+func (c *EventCoHostController) AcceptInvitation(ctx echo.Context) error {
+	return c.respondToInvitation(ctx, true)
+}
+
+// DeclineInvitation refuse, au nom de l'association invitée, de co-organiser l'événement
+func (c *EventCoHostController) DeclineInvitation(ctx echo.Context) error {
+	return c.respondToInvitation(ctx, false)
+}
+
+func (c *EventCoHostController) respondToInvitation(ctx echo.Context, accept bool) error {
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	associationID := ctx.Param("associationId")
+	if _, err := requireAssociationOwner(ctx, associationID); err != nil {
+		return err
+	}
+
+	coHost, err := c.CoHostService.RespondToInvitation(event, associationID, accept)
+	if err != nil {
+		return coHostErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, coHost)
+}
+
+// GetPendingInvitations renvoie au responsable les invitations à co-organiser reçues par l'association
+func (c *EventCoHostController) GetPendingInvitations(ctx echo.Context) error {
+	associationID := ctx.Param("associationId")
+	if _, err := requireAssociationOwner(ctx, associationID); err != nil {
+		return err
+	}
+
+	invitations, err := c.CoHostService.GetPendingInvitations(associationID)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, invitations)
+}
+
+func coHostErrorResponse(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, coreErrors.ErrInvalidCoHost):
+		return ctx.JSON(http.StatusUnprocessableEntity, "Cette association ne peut pas co-organiser l'événement")
+	case errors.Is(err, coreErrors.ErrAssociationNotFound):
+		return ctx.JSON(http.StatusNotFound, "Association introuvable")
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ctx.JSON(http.StatusNotFound, "Invitation introuvable")
+	}
+	ctx.Logger().Error(err)
+	return ctx.NoContent(http.StatusInternalServerError)
+}
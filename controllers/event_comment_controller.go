@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/utils"
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type EventCommentController struct {
+	EventService      *services.EventService
+	VisibilityService *services.EventVisibilityService
+	CommentService    *services.EventCommentService
+}
+
+func NewEventCommentController() *EventCommentController {
+	return &EventCommentController{
+		EventService:      services.NewEventService(),
+		VisibilityService: services.NewEventVisibilityService(),
+		CommentService:    services.NewEventCommentService(),
+	}
+}
+
+// GetComments renvoie le fil de questions de l'événement, accessible à quiconque peut consulter l'événement
+func (c *EventCommentController) GetComments(ctx echo.Context) error {
+	event, err := visibleEvent(ctx, c.EventService, c.VisibilityService)
+	if err != nil {
+		return err
+	}
+
+	pagination := utils.PaginationFromContext(ctx)
+	comments, err := c.CommentService.GetComments(event, pagination)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, comments)
+}
+
+// AddComment publie une question sur l'événement, ou une réponse si parent_id est renseigné
+func (c *EventCommentController) AddComment(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	event, err := visibleEvent(ctx, c.EventService, c.VisibilityService)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Content  string  `json:"content"`
+		ParentID *string `json:"parent_id"`
+	}
+	if err := ctx.Bind(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+	if body.ParentID != nil && *body.ParentID == "" {
+		body.ParentID = nil
+	}
+
+	comment, err := c.CommentService.AddComment(event, &user, body.Content, body.ParentID)
+	if err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			return ctx.JSON(http.StatusUnprocessableEntity, utils.GetValidationErrors(validationErrs, models.EventComment{}))
+		}
+		return commentErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, comment)
+}
+
+// SetAnswered marque une question comme résolue, ou la rouvre ; réservé aux responsables de l'événement
+func (c *EventCommentController) SetAnswered(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Answered *bool `json:"answered"`
+	}
+	if err := ctx.Bind(&body); err != nil || body.Answered == nil {
+		return ctx.JSON(http.StatusBadRequest, "Le champ answered est obligatoire")
+	}
+
+	comment, err := c.CommentService.GetComment(event, ctx.Param("commentId"))
+	if err != nil {
+		return commentErrorResponse(ctx, err)
+	}
+
+	if err := c.CommentService.SetAnswered(comment, *body.Answered); err != nil {
+		return commentErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, comment)
+}
+
+// DeleteComment supprime un commentaire et ses réponses : les responsables peuvent supprimer tous les commentaires,
+// un membre les siens
+func (c *EventCommentController) DeleteComment(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	comment, err := c.CommentService.GetComment(event, ctx.Param("commentId"))
+	if err != nil {
+		return commentErrorResponse(ctx, err)
+	}
+	if comment.UserID != user.ID {
+		isLeader, err := c.EventService.IsEventLeader(&user, event)
+		if err != nil {
+			ctx.Logger().Error(err)
+			return ctx.NoContent(http.StatusInternalServerError)
+		}
+		if !isLeader {
+			return ctx.JSON(http.StatusForbidden, "Vous ne pouvez supprimer que vos propres commentaires")
+		}
+	}
+
+	if err := c.CommentService.DeleteComment(comment); err != nil {
+		return commentErrorResponse(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func commentErrorResponse(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, coreErrors.ErrNotFound):
+		return ctx.JSON(http.StatusNotFound, "Commentaire introuvable")
+	case errors.Is(err, coreErrors.ErrInvalidCommentReply):
+		return ctx.JSON(http.StatusUnprocessableEntity, "Seuls les commentaires de premier niveau peuvent recevoir une réponse ou être marqués comme résolus")
+	}
+	ctx.Logger().Error(err)
+	return ctx.NoContent(http.StatusInternalServerError)
+}
@@ -2,29 +2,45 @@ package controllers
 
 import (
 	"backend/enums"
+	coreErrors "backend/errors"
 	"backend/models"
 	"backend/resources"
 	"backend/services"
 	"backend/utils"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// Rayon de la recherche d'événements à proximité, en kilomètres
+const (
+	defaultNearbyRadiusKm = 10.0
+	maxNearbyRadiusKm     = 200.0
 )
 
 type EventController struct {
 	EventService       *services.EventService
 	AssociationService *services.AssociationService
+	CheckInService     *services.CheckInService
+	VisibilityService  *services.EventVisibilityService
 }
 
 func NewEventController() *EventController {
 	return &EventController{
 		EventService:       services.NewEventService(),
 		AssociationService: services.NewAssociationService(),
+		CheckInService:     services.NewCheckInService(),
+		VisibilityService:  services.NewEventVisibilityService(),
 	}
 }
 
@@ -59,18 +75,89 @@ func (c *EventController) CreateEvent(ctx echo.Context) error {
 				"details": validationErrors,
 			})
 		}
+		if errors.Is(err, coreErrors.ErrInvalidRecurrenceRule) {
+			return ctx.JSON(http.StatusUnprocessableEntity, map[string]string{"error": "Règle de récurrence invalide"})
+		}
+		if errors.Is(err, coreErrors.ErrInvalidEventSchedule) {
+			return ctx.JSON(http.StatusUnprocessableEntity, map[string]string{"error": "La fin de l'événement doit être postérieure à son début"})
+		}
+		if errors.Is(err, coreErrors.ErrInvalidVenue) {
+			return ctx.JSON(http.StatusUnprocessableEntity, map[string]string{"error": "Le lieu n'appartient pas à l'association"})
+		}
+		if errors.Is(err, coreErrors.ErrInvalidPublishAt) {
+			return ctx.JSON(http.StatusUnprocessableEntity, map[string]string{"error": "La date de publication doit être dans le futur"})
+		}
 		ctx.Logger().Error(err)
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Erreur serveur: Impossible de créer un évènement"})
 	}
 
+	c.setLocationConflicts(ctx, newEvent)
 	return ctx.JSON(http.StatusCreated, newEvent)
 }
 
+// GetEvents recherche parmi les événements visibles par l'utilisateur, avec filtres et tri
 func (c *EventController) GetEvents(ctx echo.Context) error {
+	viewer := viewerFromContext(ctx)
+	filters := services.EventSearch{
+		Query:         ctx.QueryParam("search"),
+		CategoryID:    ctx.QueryParam("category_id"),
+		AssociationID: ctx.QueryParam("association_id"),
+		Location:      ctx.QueryParam("location"),
+		HasFreeSeats:  ctx.QueryParam("has_free_seats") == "true",
+		Attending:     ctx.QueryParam("attending") == "true",
+		Sort:          ctx.QueryParam("sort"),
+	}
+
+	var err error
+	if filters.From, err = timeQueryParam(ctx, "from"); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Date de début invalide")
+	}
+	if filters.To, err = timeQueryParam(ctx, "to"); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Date de fin invalide")
+	}
+	if filters.From != nil && filters.To != nil && filters.To.Before(*filters.From) {
+		return ctx.JSON(http.StatusBadRequest, "La date de fin doit être postérieure à la date de début")
+	}
+	if !services.IsValidEventSort(filters.Sort) {
+		return ctx.JSON(http.StatusBadRequest, "Tri invalide")
+	}
+	if filters.Attending && viewer == nil {
+		return ctx.JSON(http.StatusUnauthorized, "Connectez-vous pour filtrer les événements auxquels vous participez")
+	}
+
+	// Le tri est porté par EventSearch et non appliqué tel quel à la requête
 	pagination := utils.PaginationFromContext(ctx)
-	search := ctx.QueryParam("search")
+	pagination.Sort = nil
+
+	eventPagination, err := c.EventService.SearchEvents(viewer, filters, pagination)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, eventPagination)
+}
+
+// GetNearbyEvents renvoie les événements à venir situés dans un rayon donné (en kilomètres) autour d'un point
+func (c *EventController) GetNearbyEvents(ctx echo.Context) error {
+	latitude, err := strconv.ParseFloat(ctx.QueryParam("lat"), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return ctx.JSON(http.StatusBadRequest, "Latitude invalide")
+	}
+	longitude, err := strconv.ParseFloat(ctx.QueryParam("lng"), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return ctx.JSON(http.StatusBadRequest, "Longitude invalide")
+	}
+
+	radius := defaultNearbyRadiusKm
+	if value := ctx.QueryParam("radius"); value != "" {
+		radius, err = strconv.ParseFloat(value, 64)
+		if err != nil || radius <= 0 || radius > maxNearbyRadiusKm {
+			return ctx.JSON(http.StatusBadRequest, "Rayon de recherche invalide")
+		}
+	}
 
-	eventPagination, err := c.EventService.GetEvents(pagination, &search)
+	eventPagination, err := c.EventService.GetNearbyEvents(viewerFromContext(ctx), latitude, longitude, radius, utils.PaginationFromContext(ctx))
 	if err != nil {
 		ctx.Logger().Error(err)
 		return ctx.NoContent(http.StatusInternalServerError)
@@ -87,30 +174,72 @@ func (c *EventController) GetEventById(ctx echo.Context) error {
 		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
 	}
 
+	// Un événement non visible est traité comme inexistant pour ne pas révéler son existence
+	canView, err := c.VisibilityService.CanViewEvent(viewerFromContext(ctx), event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+	if !canView {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
 	return ctx.JSON(http.StatusOK, event)
 }
 
 func (c *EventController) UpdateEvent(ctx echo.Context) error {
-	eventID := ctx.Param("id")
-
-	existingEvent, err := c.EventService.GetEventById(eventID)
+	series, err := leaderEvent(ctx, c.EventService)
 	if err != nil {
-		return ctx.JSON(http.StatusNotFound, "Événement introuvable")
+		return err
 	}
 
 	var updateData struct {
-		Name          *string `json:"name"`
-		Description   *string `json:"description"`
-		Date          *string `json:"date"`
-		Location      *string `json:"location"`
-		CategoryId    *string `json:"category_id"`
-		AssociationId *string `json:"association_id"`
+		Name           *string `json:"name"`
+		Description    *string `json:"description"`
+		Date           *string `json:"date"`
+		EndDate        *string `json:"end_date"`
+		TimeZone       *string `json:"time_zone"`
+		Location       *string `json:"location"`
+		VenueId        *string `json:"venue_id"`
+		Capacity       *int    `json:"capacity"`
+		Visibility     *string `json:"visibility"`
+		CategoryId     *string `json:"category_id"`
+		AssociationId  *string `json:"association_id"`
+		RecurrenceRule *string `json:"recurrence_rule"`
 	}
 
 	if err := ctx.Bind(&updateData); err != nil {
 		return ctx.JSON(http.StatusBadRequest, "Données d'événement invalides")
 	}
 
+	// Pour une série, scope=occurrence modifie une seule occurrence et scope=following
+	// l'occurrence et toutes les suivantes
+	scope := ctx.QueryParam("scope")
+	occurrenceDate, err := occurrenceDateFromContext(ctx)
+	if err != nil || (scope != "" && scope != scopeAll && occurrenceDate == nil) {
+		return ctx.JSON(http.StatusBadRequest, "Date d'occurrence invalide")
+	}
+
+	existingEvent := series
+	switch scope {
+	case "", scopeAll:
+	case scopeOccurrence:
+		existingEvent, err = c.EventService.GetOrCreateOccurrence(series.ID, *occurrenceDate)
+		if err != nil {
+			return occurrenceErrorResponse(ctx, err)
+		}
+	case scopeFollowing:
+		following := *series
+		following.Date = *occurrenceDate
+		if series.EndDate != nil {
+			endDate := occurrenceDate.Add(series.Duration())
+			following.EndDate = &endDate
+		}
+		existingEvent = &following
+	default:
+		return ctx.JSON(http.StatusBadRequest, "Portée de modification invalide")
+	}
+
 	if updateData.Name != nil {
 		existingEvent.Name = *updateData.Name
 	}
@@ -122,27 +251,113 @@ func (c *EventController) UpdateEvent(ctx echo.Context) error {
 		if err != nil {
 			return ctx.JSON(http.StatusBadRequest, "Format de date invalide")
 		}
+		// Sans nouvelle heure de fin, l'événement garde sa durée
+		if existingEvent.EndDate != nil {
+			endDate := parsedDate.Add(existingEvent.Duration())
+			existingEvent.EndDate = &endDate
+		}
 		existingEvent.Date = parsedDate
 	}
+	// Une heure de fin vide revient à la durée par défaut
+	if updateData.EndDate != nil {
+		if *updateData.EndDate == "" {
+			existingEvent.EndDate = nil
+		} else {
+			parsedEndDate, err := time.Parse(time.RFC3339, *updateData.EndDate)
+			if err != nil {
+				return ctx.JSON(http.StatusBadRequest, "Format de date de fin invalide")
+			}
+			existingEvent.EndDate = &parsedEndDate
+		}
+	}
+	if updateData.TimeZone != nil {
+		if _, err := time.LoadLocation(*updateData.TimeZone); err != nil {
+			return ctx.JSON(http.StatusBadRequest, "Fuseau horaire invalide")
+		}
+		existingEvent.TimeZone = *updateData.TimeZone
+	}
 	if updateData.Location != nil {
 		existingEvent.Location = *updateData.Location
 	}
+	// Un lieu vide détache l'événement du lieu enregistré
+	if updateData.VenueId != nil {
+		existingEvent.VenueID = updateData.VenueId
+	}
+
+	// Une capacité à 0 supprime la limite de places
+	if updateData.Capacity != nil {
+		switch {
+		case *updateData.Capacity < 0:
+			return ctx.JSON(http.StatusBadRequest, "Capacité invalide")
+		case *updateData.Capacity == 0:
+			existingEvent.Capacity = nil
+		default:
+			existingEvent.Capacity = updateData.Capacity
+		}
+	}
+
+	if updateData.Visibility != nil {
+		switch visibility := enums.Visibility(*updateData.Visibility); visibility {
+		case enums.PublicVisibility, enums.MembersVisibility, enums.InviteOnlyVisibility:
+			existingEvent.Visibility = visibility
+		default:
+			return ctx.JSON(http.StatusBadRequest, "Visibilité invalide")
+		}
+	}
 
 	if updateData.CategoryId != nil {
 		existingEvent.CategoryID = *updateData.CategoryId
 	}
 
-	if updateData.AssociationId != nil {
+	if updateData.AssociationId != nil && *updateData.AssociationId != existingEvent.AssociationID {
+		// L'événement ne peut être confié qu'à une association dont l'utilisateur est aussi responsable
+		if _, err := requireAssociationOwner(ctx, *updateData.AssociationId); err != nil {
+			return err
+		}
 		existingEvent.AssociationID = *updateData.AssociationId
 	}
 
+	if updateData.RecurrenceRule != nil {
+		existingEvent.RecurrenceRule = *updateData.RecurrenceRule
+	}
+
+	if scope == scopeFollowing {
+		newSeries, err := c.EventService.UpdateFollowingOccurrences(series, *occurrenceDate, existingEvent)
+		if err != nil {
+			return occurrenceErrorResponse(ctx, err)
+		}
+		c.setLocationConflicts(ctx, newSeries)
+		return ctx.JSON(http.StatusOK, newSeries)
+	}
+
 	if err := c.EventService.UpdateEvent(existingEvent); err != nil {
+		if errors.Is(err, coreErrors.ErrInvalidRecurrenceRule) {
+			return ctx.JSON(http.StatusUnprocessableEntity, "Règle de récurrence invalide")
+		}
+		if errors.Is(err, coreErrors.ErrInvalidEventSchedule) {
+			return ctx.JSON(http.StatusUnprocessableEntity, "La fin de l'événement doit être postérieure à son début")
+		}
+		if errors.Is(err, coreErrors.ErrInvalidVenue) {
+			return ctx.JSON(http.StatusUnprocessableEntity, "Le lieu n'appartient pas à l'association")
+		}
 		return ctx.JSON(http.StatusConflict, err.Error())
 	}
 
+	c.setLocationConflicts(ctx, existingEvent)
 	return ctx.JSON(http.StatusOK, existingEvent)
 }
 
+// setLocationConflicts signale au responsable les autres événements prévus au même lieu sur le même créneau.
+// Le conflit n'empêche pas l'enregistrement : une erreur de recherche est seulement journalisée.
+func (c *EventController) setLocationConflicts(ctx echo.Context, event *models.Event) {
+	conflicts, err := c.EventService.FindLocationConflicts(event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return
+	}
+	event.LocationConflicts = conflicts
+}
+
 func (c *EventController) DeleteEvent(ctx echo.Context) error {
 	id := ctx.Param("id")
 
@@ -175,8 +390,45 @@ func (c *EventController) DeleteEvent(ctx echo.Context) error {
 		return ctx.JSON(http.StatusForbidden, "Interdit : vous n'avez pas les permissions nécessaires pour supprimer cet événement")
 	}
 
-	err = c.EventService.DeleteEvent(id)
+	occurrenceDate, err := occurrenceDateFromContext(ctx)
 	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Date d'occurrence invalide")
+	}
+
+	scope := ctx.QueryParam("scope")
+
+	// Une occurrence matérialisée se supprime par sa série, qui l'ajoute à ses exceptions : supprimer la ligne
+	// seule ferait réapparaître l'occurrence au dépliage de la série
+	if event.IsOccurrence() {
+		series, err := c.EventService.GetEventById(*event.RecurrenceParentID)
+		if err != nil {
+			return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+		}
+		if occurrenceDate == nil {
+			occurrenceDate = event.RecurrenceID
+		}
+		event, id = series, series.ID
+		if scope == "" {
+			scope = scopeOccurrence
+		}
+	}
+
+	switch {
+	case scope == "" || scope == scopeAll:
+		err = c.EventService.DeleteEvent(id)
+	case occurrenceDate == nil:
+		return ctx.JSON(http.StatusBadRequest, "Date d'occurrence invalide")
+	case scope == scopeOccurrence:
+		err = c.EventService.DeleteOccurrence(event, *occurrenceDate)
+	case scope == scopeFollowing:
+		err = c.EventService.DeleteFollowingOccurrences(event, *occurrenceDate)
+	default:
+		return ctx.JSON(http.StatusBadRequest, "Portée de suppression invalide")
+	}
+	if err != nil {
+		if errors.Is(err, coreErrors.ErrInvalidOccurrence) || errors.Is(err, coreErrors.ErrNotRecurringEvent) || errors.Is(err, coreErrors.ErrInvalidRecurrenceRule) {
+			return occurrenceErrorResponse(ctx, err)
+		}
 		return ctx.JSON(http.StatusInternalServerError, "Erreur serveur : impossible de supprimer l'événement")
 	}
 
@@ -190,6 +442,19 @@ func (c *EventController) GetEventParticipations(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, "ID invalide")
 	}
 
+	event, err := c.EventService.GetEventById(id)
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+	canView, err := c.VisibilityService.CanViewEvent(viewerFromContext(ctx), event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+	if !canView {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
 	pagination := utils.PaginationFromContext(ctx)
 
 	statusParam := ctx.QueryParam("status")
@@ -225,6 +490,11 @@ func (c *EventController) GetUserEventParticipation(ctx echo.Context) error {
 		return ctx.NoContent(http.StatusNotFound)
 	}
 
+	eventID, err = c.occurrenceEventID(ctx, event)
+	if err != nil {
+		return occurrenceErrorResponse(ctx, err)
+	}
+
 	participation := c.EventService.GetUserEventParticipation(eventID, user.ID)
 	if participation == nil {
 		return ctx.JSON(http.StatusNotFound, "Participation introuvable")
@@ -252,8 +522,18 @@ func (c *EventController) ChangeAttend(ctx echo.Context) error {
 		return ctx.NoContent(http.StatusNotFound)
 	}
 
+	canView, err := c.VisibilityService.CanViewEvent(&user, event)
+	if err != nil {
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+	if !canView {
+		return ctx.NoContent(http.StatusNotFound)
+	}
+
 	body := struct {
-		IsAttending bool `json:"is_attending"`
+		IsAttending    bool                                `json:"is_attending"`
+		OccurrenceDate *time.Time                          `json:"occurrence_date"`
+		Answers        []services.ParticipationAnswerInput `json:"answers"`
 	}{}
 	err = json.NewDecoder(ctx.Request().Body).Decode(&body)
 	if err != nil {
@@ -261,8 +541,34 @@ func (c *EventController) ChangeAttend(ctx echo.Context) error {
 	}
 	isAttending := body.IsAttending
 
-	participation, err := c.EventService.ChangeUserEventAttend(isAttending, eventID, user.ID)
+	// La participation à une série se fait occurrence par occurrence
+	if event.IsRecurring() {
+		if body.OccurrenceDate == nil {
+			return ctx.JSON(http.StatusBadRequest, "La date de l'occurrence est requise pour un événement récurrent")
+		}
+
+		occurrence, err := c.EventService.GetOrCreateOccurrence(event.ID, *body.OccurrenceDate)
+		if err != nil {
+			return occurrenceErrorResponse(ctx, err)
+		}
+		eventID = occurrence.ID
+	}
+
+	participation, err := c.EventService.ChangeUserEventAttendWithAnswers(isAttending, eventID, user.ID, body.Answers)
 	if err != nil {
+		if errors.Is(err, coreErrors.ErrEventCancelled) {
+			return ctx.JSON(http.StatusConflict, "L'événement est annulé")
+		}
+		if errors.Is(err, coreErrors.ErrEventNotPublished) {
+			return ctx.JSON(http.StatusConflict, "L'événement n'est pas encore publié")
+		}
+		var answersErr *services.AnswersValidationError
+		if errors.As(err, &answersErr) {
+			return ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+				"error":   "Réponses au formulaire d'inscription invalides",
+				"details": answersErr.Details,
+			})
+		}
 		return ctx.NoContent(http.StatusInternalServerError)
 	}
 
@@ -288,7 +594,393 @@ func (c *EventController) IsAttended(ctx echo.Context) error {
 		return ctx.NoContent(http.StatusNotFound)
 	}
 
+	eventID, err = c.occurrenceEventID(ctx, event)
+	if err != nil {
+		return occurrenceErrorResponse(ctx, err)
+	}
+
 	isAttended := c.EventService.IsUserAttendingEvent(eventID, user.ID)
 
 	return ctx.JSON(http.StatusOK, map[string]bool{"is_attended": isAttended})
 }
+
+// CancelEvent annule l'événement, ou une seule occurrence avec scope=occurrence, et prévient ses participants
+func (c *EventController) CancelEvent(ctx echo.Context) error {
+	event, err := c.statusChangeTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if err := ctx.Bind(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+	if event.IsCancelled() {
+		return ctx.JSON(http.StatusConflict, "L'événement est déjà annulé")
+	}
+
+	if err := c.EventService.CancelEvent(event, strings.TrimSpace(body.Reason)); err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, event)
+}
+
+// RescheduleEvent reprogramme l'événement à une nouvelle date, ou le reporte sans date, et prévient ses participants
+func (c *EventController) RescheduleEvent(ctx echo.Context) error {
+	event, err := c.statusChangeTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Date   *time.Time `json:"date"`
+		Reason string     `json:"reason"`
+	}
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+	if body.Date != nil && event.IsRecurring() {
+		return ctx.JSON(http.StatusBadRequest, "Une série récurrente se reprogramme occurrence par occurrence")
+	}
+
+	if err := c.EventService.RescheduleEvent(event, body.Date, strings.TrimSpace(body.Reason)); err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, event)
+}
+
+// PublishEvent publie l'événement et prévient ses membres, ou programme sa publication si publish_at est fourni
+func (c *EventController) PublishEvent(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		PublishAt *time.Time `json:"publish_at"`
+	}
+	// Le corps est facultatif : sans date, l'événement est publié immédiatement
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+
+	now := time.Now()
+	if body.PublishAt == nil {
+		err = c.EventService.PublishEvent(event, now)
+	} else {
+		err = c.EventService.SchedulePublication(event, *body.PublishAt, now)
+	}
+	if err != nil {
+		return publicationErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, event)
+}
+
+// RevertToDraft repasse en brouillon un événement pas encore publié
+func (c *EventController) RevertToDraft(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	if err := c.EventService.RevertToDraft(event); err != nil {
+		return publicationErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, event)
+}
+
+func publicationErrorResponse(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, coreErrors.ErrEventAlreadyPublished):
+		return ctx.JSON(http.StatusConflict, "L'événement est déjà publié")
+	case errors.Is(err, coreErrors.ErrInvalidPublishAt):
+		return ctx.JSON(http.StatusUnprocessableEntity, "La date de publication doit être dans le futur")
+	case errors.Is(err, coreErrors.ErrInvalidOccurrence):
+		return ctx.JSON(http.StatusBadRequest, "La publication d'un événement récurrent porte sur toute la série")
+	}
+	ctx.Logger().Error(err)
+	return ctx.NoContent(http.StatusInternalServerError)
+}
+
+// statusChangeTarget renvoie l'événement à annuler ou reprogrammer : l'occurrence désignée avec scope=occurrence,
+// l'événement lui-même sinon. En cas de refus, la réponse HTTP est renvoyée sous forme d'erreur.
+func (c *EventController) statusChangeTarget(ctx echo.Context) (*models.Event, error) {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ctx.QueryParam("scope") {
+	case "", scopeAll:
+		return event, nil
+	case scopeOccurrence:
+		occurrenceDate, err := occurrenceDateFromContext(ctx)
+		if err != nil || occurrenceDate == nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Date d'occurrence invalide")
+		}
+		occurrence, err := c.EventService.GetOrCreateOccurrence(event.ID, *occurrenceDate)
+		switch {
+		case errors.Is(err, coreErrors.ErrNotRecurringEvent):
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "L'événement n'est pas récurrent")
+		case errors.Is(err, coreErrors.ErrInvalidOccurrence):
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "La date ne correspond à aucune occurrence de l'événement")
+		case err != nil:
+			return nil, err
+		}
+		return occurrence, nil
+	}
+	return nil, echo.NewHTTPError(http.StatusBadRequest, "Portée invalide")
+}
+
+const (
+	scopeAll        = "all"
+	scopeOccurrence = "occurrence"
+	scopeFollowing  = "following"
+)
+
+// GetCheckInQRCode renvoie le QR code PNG que le participant présente à l'entrée de l'événement
+func (c *EventController) GetCheckInQRCode(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	eventID, err := c.occurrenceEventID(ctx, event)
+	if err != nil {
+		return occurrenceErrorResponse(ctx, err)
+	}
+
+	participation := c.EventService.GetUserEventParticipation(eventID, user.ID, "Event")
+	if participation == nil || !participation.IsAttending {
+		return ctx.JSON(http.StatusNotFound, "Vous n'êtes pas inscrit à cet événement")
+	}
+
+	png, err := c.CheckInService.GenerateCheckInQRCode(participation, participation.Event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return ctx.Blob(http.StatusOK, "image/png", png)
+}
+
+// CheckIn enregistre la présence du participant dont le QR code a été scanné par un responsable
+func (c *EventController) CheckIn(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	var body struct {
+		Token string `json:"token"`
+	}
+	if err := ctx.Bind(&body); err != nil || body.Token == "" {
+		return ctx.JSON(http.StatusBadRequest, "Jeton de pointage manquant")
+	}
+
+	participation, err := c.CheckInService.GetCheckInParticipation(body.Token)
+	if err != nil {
+		return checkInErrorResponse(ctx, err)
+	}
+
+	// Le QR code doit correspondre à l'événement scanné, ou à l'une de ses occurrences
+	eventID := ctx.Param("id")
+	if participation.EventID != eventID && (participation.Event.RecurrenceParentID == nil || *participation.Event.RecurrenceParentID != eventID) {
+		return ctx.JSON(http.StatusBadRequest, "Ce QR code ne correspond pas à cet événement")
+	}
+
+	isLeader, err := c.EventService.IsEventLeader(&user, participation.Event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+	if !isLeader {
+		return ctx.JSON(http.StatusForbidden, "Vous n'êtes pas autorisé à pointer les participants de cet événement")
+	}
+
+	if err := c.CheckInService.CheckIn(participation, &user, time.Now()); err != nil {
+		if errors.Is(err, coreErrors.ErrAlreadyCheckedIn) {
+			return ctx.JSON(http.StatusConflict, participation)
+		}
+		return checkInErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, participation)
+}
+
+// attendanceResultResponse est le compte rendu du traitement d'une participation d'un lot
+type attendanceResultResponse struct {
+	ParticipationID string `json:"participation_id"`
+	UserID          string `json:"user_id,omitempty"`
+	Success         bool   `json:"success"`
+	Error           string `json:"error,omitempty"`
+}
+
+// BulkAttendance confirme, refuse ou pointe présentes ou absentes plusieurs participations de l'événement en
+// une seule transaction, et renvoie le résultat de chacune
+func (c *EventController) BulkAttendance(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+	user := ctx.Get("user").(models.User)
+
+	var input services.BulkAttendanceInput
+	if err := ctx.Bind(&input); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+
+	results, err := c.CheckInService.BulkAttendance(event, input, &user, time.Now())
+	if err != nil {
+		if errors.Is(err, coreErrors.ErrInvalidBulkAttendance) {
+			return ctx.JSON(http.StatusBadRequest, fmt.Sprintf("Indiquez une action valide et soit une liste d'au plus %d participations, soit all_pending", services.MaxBulkAttendance))
+		}
+		return checkInErrorResponse(ctx, err)
+	}
+
+	response := make([]attendanceResultResponse, len(results))
+	succeeded := 0
+	for i, result := range results {
+		response[i] = attendanceResultResponse{
+			ParticipationID: result.ParticipationID,
+			UserID:          result.UserID,
+			Success:         result.Err == nil,
+		}
+		switch {
+		case result.Err == nil:
+			succeeded++
+		case errors.Is(result.Err, coreErrors.ErrParticipationAlreadyHandled):
+			response[i].Error = "Participation déjà traitée"
+		case errors.Is(result.Err, coreErrors.ErrAlreadyCheckedIn):
+			response[i].Error = "Participant déjà pointé"
+		default:
+			response[i].Error = "Le participant n'est pas inscrit à cet événement"
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]interface{}{
+		"action":    input.Action,
+		"processed": len(results),
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+		"results":   response,
+	})
+}
+
+func checkInErrorResponse(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, coreErrors.ErrInvalidCheckInToken):
+		return ctx.JSON(http.StatusBadRequest, "QR code invalide ou expiré")
+	case errors.Is(err, coreErrors.ErrNotRegistered):
+		return ctx.JSON(http.StatusNotFound, "Le participant n'est pas inscrit à cet événement")
+	case errors.Is(err, coreErrors.ErrCheckInNotOpen):
+		return ctx.JSON(http.StatusUnprocessableEntity, "Le pointage n'est pas encore ouvert pour cet événement")
+	case errors.Is(err, coreErrors.ErrEventCancelled):
+		return ctx.JSON(http.StatusConflict, "L'événement est annulé")
+	}
+	ctx.Logger().Error(err)
+	return ctx.NoContent(http.StatusInternalServerError)
+}
+
+// viewerFromContext renvoie l'utilisateur authentifié, ou nil pour un visiteur anonyme
+func viewerFromContext(ctx echo.Context) *models.User {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return nil
+	}
+	return &user
+}
+
+// visibleEvent charge l'événement de la requête s'il est visible par l'utilisateur, une erreur 404 sinon.
+// Les erreurs renvoyées sont des *echo.HTTPError portant le message destiné au client.
+func visibleEvent(ctx echo.Context, eventService *services.EventService, visibilityService *services.EventVisibilityService) (*models.Event, error) {
+	event, err := eventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	canView, err := visibilityService.CanViewEvent(viewerFromContext(ctx), event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	if !canView {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	return event, nil
+}
+
+// occurrenceDateFromContext lit le paramètre occurrence_date (RFC 3339) désignant une occurrence d'une série
+func occurrenceDateFromContext(ctx echo.Context) (*time.Time, error) {
+	return timeQueryParam(ctx, "occurrence_date")
+}
+
+// timeQueryParam lit un paramètre de requête facultatif au format RFC 3339
+func timeQueryParam(ctx echo.Context, name string) (*time.Time, error) {
+	value := ctx.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// occurrenceEventID renvoie l'ID de l'occurrence matérialisée désignée par occurrence_date,
+// ou l'ID de l'événement lui-même s'il n'est pas récurrent
+func (c *EventController) occurrenceEventID(ctx echo.Context, event *models.Event) (string, error) {
+	occurrenceDate, err := occurrenceDateFromContext(ctx)
+	if err != nil {
+		return "", coreErrors.ErrInvalidOccurrence
+	}
+	if !event.IsRecurring() || occurrenceDate == nil {
+		return event.ID, nil
+	}
+
+	occurrence, err := c.EventService.FindOccurrence(event.ID, *occurrenceDate)
+	if err != nil {
+		return "", err
+	}
+	if occurrence == nil {
+		// Occurrence jamais matérialisée : personne n'y participe encore
+		return "", nil
+	}
+	return occurrence.ID, nil
+}
+
+func occurrenceErrorResponse(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, coreErrors.ErrNotRecurringEvent):
+		return ctx.JSON(http.StatusBadRequest, "L'événement n'est pas récurrent")
+	case errors.Is(err, coreErrors.ErrInvalidOccurrence):
+		return ctx.JSON(http.StatusBadRequest, "La date ne correspond à aucune occurrence de l'événement")
+	case errors.Is(err, coreErrors.ErrInvalidRecurrenceRule):
+		return ctx.JSON(http.StatusUnprocessableEntity, "Règle de récurrence invalide")
+	case errors.Is(err, coreErrors.ErrInvalidEventSchedule):
+		return ctx.JSON(http.StatusUnprocessableEntity, "La fin de l'événement doit être postérieure à son début")
+	case errors.Is(err, coreErrors.ErrInvalidVenue):
+		return ctx.JSON(http.StatusUnprocessableEntity, "Le lieu n'appartient pas à l'association")
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+	ctx.Logger().Error(err)
+	return ctx.NoContent(http.StatusInternalServerError)
+}
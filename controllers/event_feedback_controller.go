@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/utils"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type EventFeedbackController struct {
+	EventService         *services.EventService
+	EventFeedbackService *services.EventFeedbackService
+}
+
+func NewEventFeedbackController() *EventFeedbackController {
+	return &EventFeedbackController{
+		EventService:         services.NewEventService(),
+		EventFeedbackService: services.NewEventFeedbackService(),
+	}
+}
+
+// SubmitFeedback enregistre la note et le commentaire du participant sur l'événement terminé
+func (c *EventFeedbackController) SubmitFeedback(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	event, err := c.feedbackEvent(ctx)
+	if err != nil {
+		return err
+	}
+
+	var feedback models.EventFeedback
+	if err := ctx.Bind(&feedback); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Avis invalide")
+	}
+
+	if err := c.EventFeedbackService.SubmitFeedback(event, user.ID, &feedback, time.Now()); err != nil {
+		var validationErrs validator.ValidationErrors
+		switch {
+		case errors.As(err, &validationErrs):
+			return ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+				"error":   "Validation error",
+				"details": utils.GetValidationErrors(validationErrs, feedback),
+			})
+		case errors.Is(err, coreErrors.ErrFeedbackClosed):
+			return ctx.JSON(http.StatusUnprocessableEntity, "Les avis ne sont pas ouverts pour cet événement")
+		case errors.Is(err, coreErrors.ErrNotRegistered):
+			return ctx.JSON(http.StatusForbidden, "Seuls les participants confirmés ou présents peuvent donner leur avis")
+		case errors.Is(err, coreErrors.ErrEventCancelled):
+			return ctx.JSON(http.StatusConflict, "L'événement est annulé")
+		}
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, feedback)
+}
+
+// GetUserFeedback renvoie l'avis déjà donné par l'utilisateur connecté
+func (c *EventFeedbackController) GetUserFeedback(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	event, err := c.feedbackEvent(ctx)
+	if err != nil {
+		return err
+	}
+
+	feedback, err := c.EventFeedbackService.GetUserFeedback(event.ID, user.ID)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+	if feedback == nil {
+		return ctx.JSON(http.StatusNotFound, "Aucun avis pour cet événement")
+	}
+
+	return ctx.JSON(http.StatusOK, feedback)
+}
+
+// GetEventFeedback renvoie au responsable les résultats de l'événement et les avis des participants
+func (c *EventFeedbackController) GetEventFeedback(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	if event.IsRecurring() && ctx.QueryParam("occurrence_date") != "" {
+		if event, err = c.feedbackEvent(ctx); err != nil {
+			return err
+		}
+	}
+
+	report, err := c.EventFeedbackService.GetEventFeedback(event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, report)
+}
+
+// GetAssociationFeedback renvoie au responsable le bilan des avis laissés sur les événements de l'association
+func (c *EventFeedbackController) GetAssociationFeedback(ctx echo.Context) error {
+	association, err := requireAssociationOwner(ctx, ctx.Param("associationId"))
+	if err != nil {
+		return err
+	}
+
+	report, err := c.EventFeedbackService.GetAssociationFeedback(association.ID)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, report)
+}
+
+// feedbackEvent renvoie l'événement noté : pour une série, l'occurrence désignée par occurrence_date.
+// Les erreurs renvoyées sont des *echo.HTTPError portant le message destiné au client.
+func (c *EventFeedbackController) feedbackEvent(ctx echo.Context) (*models.Event, error) {
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	occurrenceDate, err := occurrenceDateFromContext(ctx)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Date d'occurrence invalide")
+	}
+	if !event.IsRecurring() || occurrenceDate == nil {
+		return event, nil
+	}
+
+	// Une occurrence jamais matérialisée n'a pas de participants, et donc pas d'avis
+	occurrence, err := c.EventService.FindOccurrence(event.ID, *occurrenceDate)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	if occurrence == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Occurrence non trouvée")
+	}
+	return occurrence, nil
+}
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type EventFormController struct {
+	EventService      *services.EventService
+	EventFormService  *services.EventFormService
+	VisibilityService *services.EventVisibilityService
+	ExportService     *services.ParticipantExportService
+}
+
+func NewEventFormController() *EventFormController {
+	return &EventFormController{
+		EventService:      services.NewEventService(),
+		EventFormService:  services.NewEventFormService(),
+		VisibilityService: services.NewEventVisibilityService(),
+		ExportService:     services.NewParticipantExportService(),
+	}
+}
+
+// GetEventQuestions renvoie le formulaire d'inscription de l'événement
+func (c *EventFormController) GetEventQuestions(ctx echo.Context) error {
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	canView, err := c.VisibilityService.CanViewEvent(viewerFromContext(ctx), event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+	if !canView {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	questions, err := c.EventFormService.GetQuestions(event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, questions)
+}
+
+// UpdateEventQuestions remplace le formulaire d'inscription de l'événement
+func (c *EventFormController) UpdateEventQuestions(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	var questions []models.EventQuestion
+	if err := json.NewDecoder(ctx.Request().Body).Decode(&questions); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Formulaire invalide")
+	}
+
+	updated, err := c.EventFormService.SetQuestions(event, questions)
+	if err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			return ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+				"error":   "Validation error",
+				"details": utils.GetValidationErrors(validationErrs, models.EventQuestion{}),
+			})
+		}
+		if errors.Is(err, coreErrors.ErrInvalidQuestion) {
+			return ctx.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		}
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, updated)
+}
+
+// ExportEventAnswers exporte au format CSV les réponses des inscrits, au moyen de l'export des participants
+func (c *EventFormController) ExportEventAnswers(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	options := services.ParticipantExportOptions{Format: services.ExportFormatCSV, Columns: services.AnswersExportColumns}
+	writeExportHeaders(ctx, options, fmt.Sprintf("reponses-%s", event.ID))
+	if err := c.ExportService.ExportEventParticipants(event, options, ctx.Response()); err != nil {
+		ctx.Logger().Error(err)
+	}
+	return nil
+}
+
+// requireAssociationOwner renvoie l'association si l'utilisateur en est le responsable ou est administrateur.
+// En cas de refus, la réponse HTTP est renvoyée sous forme d'erreur.
+func requireAssociationOwner(ctx echo.Context, associationID string) (*models.Association, error) {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Non autorisé")
+	}
+
+	association, err := services.NewAssociationService().GetAssociationById(associationID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Association introuvable")
+	}
+	if !enums.IsAdmin(user.Role) && association.OwnerID != user.ID {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "Interdit : vous n'êtes pas responsable de cette association")
+	}
+	return association, nil
+}
+
+// leaderEvent renvoie l'événement si l'utilisateur est administrateur ou responsable de l'une des associations
+// qui l'organisent. En cas de refus, la réponse HTTP est renvoyée sous forme d'erreur.
+func leaderEvent(ctx echo.Context, eventService *services.EventService) (*models.Event, error) {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "Non autorisé")
+	}
+
+	event, err := eventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	isLeader, err := eventService.IsEventLeader(&user, event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	if !isLeader {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "Interdit : vous n'êtes pas responsable de cette association")
+	}
+
+	return event, nil
+}
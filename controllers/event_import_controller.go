@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	coreErrors "backend/errors"
+	"backend/services"
+	"errors"
+	"net/http"
+
+	ics "github.com/arran4/golang-ical"
+	"github.com/labstack/echo/v4"
+)
+
+type EventImportController struct {
+	EventImportService *services.EventImportService
+	CategoryService    *services.CategoryService
+}
+
+func NewEventImportController() *EventImportController {
+	return &EventImportController{
+		EventImportService: services.NewEventImportService(),
+		CategoryService:    services.NewCategoryService(),
+	}
+}
+
+type eventImportRequest struct {
+	URL        string `json:"url" form:"url"`
+	CategoryID string `json:"category_id" form:"category_id"`
+}
+
+// PreviewImport renvoie les événements qu'un import créerait ou mettrait à jour, sans les enregistrer
+func (c *EventImportController) PreviewImport(ctx echo.Context) error {
+	calendar, request, err := c.calendarFromRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	report, err := c.EventImportService.PreviewImport(calendar, ctx.Param("associationId"), request.CategoryID)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Erreur serveur: Impossible de prévisualiser l'import"})
+	}
+
+	return ctx.JSON(http.StatusOK, report)
+}
+
+func (c *EventImportController) ImportEvents(ctx echo.Context) error {
+	calendar, request, err := c.calendarFromRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	report, err := c.EventImportService.ImportCalendar(calendar, ctx.Param("associationId"), request.CategoryID)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Erreur serveur: Impossible d'importer les événements"})
+	}
+
+	return ctx.JSON(http.StatusOK, report)
+}
+
+// calendarFromRequest lit le calendrier envoyé en fichier (champ "file") ou à télécharger depuis "url", une fois
+// vérifié que l'utilisateur est responsable de l'association. Les erreurs renvoyées sont des *echo.HTTPError portant le message destiné au client.
+func (c *EventImportController) calendarFromRequest(ctx echo.Context) (*ics.Calendar, *eventImportRequest, error) {
+	if _, err := requireAssociationOwner(ctx, ctx.Param("associationId")); err != nil {
+		return nil, nil, err
+	}
+
+	var request eventImportRequest
+	if err := ctx.Bind(&request); err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, map[string]string{"error": "Mauvaise requête: Impossible de décoder le corps de la requête"})
+	}
+
+	if request.CategoryID == "" {
+		return nil, nil, echo.NewHTTPError(http.StatusUnprocessableEntity, map[string]string{"error": "La catégorie par défaut est obligatoire"})
+	}
+	if _, err := c.CategoryService.GetCategoryById(request.CategoryID); err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusUnprocessableEntity, map[string]string{"error": "Catégorie introuvable"})
+	}
+
+	var calendar *ics.Calendar
+	var err error
+	if file, fileErr := ctx.FormFile("file"); fileErr == nil {
+		src, openErr := file.Open()
+		if openErr != nil {
+			return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, map[string]string{"error": "Erreur lors de l'ouverture du fichier"})
+		}
+		defer src.Close()
+		calendar, err = c.EventImportService.ParseCalendar(src)
+	} else if request.URL != "" {
+		calendar, err = c.EventImportService.FetchCalendar(request.URL)
+	} else {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, map[string]string{"error": "Un fichier .ics ou une URL de calendrier est requis"})
+	}
+
+	switch {
+	case err == nil:
+		return calendar, &request, nil
+	case errors.Is(err, coreErrors.ErrCalendarTooLarge):
+		return nil, nil, echo.NewHTTPError(http.StatusRequestEntityTooLarge, map[string]string{"error": "Le calendrier est trop volumineux"})
+	case errors.Is(err, coreErrors.ErrInvalidCalendarURL):
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, map[string]string{"error": "URL de calendrier invalide"})
+	case errors.Is(err, coreErrors.ErrCalendarFetchFailed):
+		ctx.Logger().Error(err)
+		return nil, nil, echo.NewHTTPError(http.StatusBadGateway, map[string]string{"error": "Impossible de récupérer le calendrier distant"})
+	case errors.Is(err, coreErrors.ErrInvalidCalendar):
+		return nil, nil, echo.NewHTTPError(http.StatusUnprocessableEntity, map[string]string{"error": "Fichier iCalendar invalide"})
+	default:
+		ctx.Logger().Error(err)
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, map[string]string{"error": "Erreur lors de la lecture du calendrier"})
+	}
+}
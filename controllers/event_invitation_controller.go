@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/services"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type EventInvitationController struct {
+	EventService      *services.EventService
+	VisibilityService *services.EventVisibilityService
+}
+
+func NewEventInvitationController() *EventInvitationController {
+	return &EventInvitationController{
+		EventService:      services.NewEventService(),
+		VisibilityService: services.NewEventVisibilityService(),
+	}
+}
+
+// GetEventInvitations renvoie la liste des invités de l'événement
+func (c *EventInvitationController) GetEventInvitations(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	invitations, err := c.VisibilityService.GetInvitations(event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, invitations)
+}
+
+// InviteUsers ajoute des utilisateurs à la liste des invités de l'événement
+func (c *EventInvitationController) InviteUsers(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+	user := ctx.Get("user").(models.User)
+
+	var body struct {
+		UserIDs []string `json:"user_ids"`
+	}
+	if err := ctx.Bind(&body); err != nil || len(body.UserIDs) == 0 {
+		return ctx.JSON(http.StatusBadRequest, "Liste d'utilisateurs invalide")
+	}
+
+	invitations, err := c.VisibilityService.InviteUsers(event, &user, body.UserIDs)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, invitations)
+}
+
+// RevokeInvitation retire un utilisateur de la liste des invités de l'événement
+func (c *EventInvitationController) RevokeInvitation(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	if err := c.VisibilityService.RevokeInvitation(event, ctx.Param("userId")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ctx.JSON(http.StatusNotFound, "Invitation introuvable")
+		}
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EventPhotoController struct {
+	EventService      *services.EventService
+	VisibilityService *services.EventVisibilityService
+	PhotoService      *services.EventPhotoService
+}
+
+func NewEventPhotoController() *EventPhotoController {
+	return &EventPhotoController{
+		EventService:      services.NewEventService(),
+		VisibilityService: services.NewEventVisibilityService(),
+		PhotoService:      services.NewEventPhotoService(),
+	}
+}
+
+// GetEventPhotos renvoie la galerie de l'événement ; les photos masquées ne sont visibles que du responsable et de leur auteur
+func (c *EventPhotoController) GetEventPhotos(ctx echo.Context) error {
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	viewer := viewerFromContext(ctx)
+	canView, err := c.VisibilityService.CanViewEvent(viewer, event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+	if !canView {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	viewerID := ""
+	if viewer != nil {
+		viewerID = viewer.ID
+	}
+
+	isLeader, err := c.EventService.IsEventLeader(viewer, event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	photos, err := c.PhotoService.GetEventPhotos(event.ID, viewerID, isLeader)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+	for i := range photos {
+		withPhotoFileURLs(&photos[i])
+	}
+
+	return ctx.JSON(http.StatusOK, photos)
+}
+
+// GetPhotoFile envoie le fichier d'une photo, ou sa miniature avec thumbnail=true. Les fichiers des photos
+// masquées ne sont pas servis sous /public : seuls le responsable et l'auteur peuvent les obtenir ici.
+func (c *EventPhotoController) GetPhotoFile(ctx echo.Context) error {
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	viewer := viewerFromContext(ctx)
+	canView, err := c.VisibilityService.CanViewEvent(viewer, event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+	if !canView {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	photo, err := c.PhotoService.GetPhoto(event.ID, ctx.Param("photoId"))
+	if err != nil {
+		return photoErrorResponse(ctx, err)
+	}
+	if photo.Hidden && (viewer == nil || viewer.ID != photo.UserID) {
+		isLeader, err := c.EventService.IsEventLeader(viewer, event)
+		if err != nil {
+			ctx.Logger().Error(err)
+			return ctx.NoContent(http.StatusInternalServerError)
+		}
+		if !isLeader {
+			return ctx.JSON(http.StatusNotFound, "Photo introuvable")
+		}
+	}
+
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "private, no-store")
+	return ctx.File(c.PhotoService.PhotoFile(photo, ctx.QueryParam("thumbnail") == "true"))
+}
+
+// UploadPhoto ajoute la photo envoyée (champ "photo") à la galerie de l'événement
+func (c *EventPhotoController) UploadPhoto(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	file, err := ctx.FormFile("photo")
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Photo manquante")
+	}
+
+	isLeader, err := c.EventService.IsEventLeader(&user, event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	photo, err := c.PhotoService.UploadPhoto(event, user.ID, file, isLeader)
+	if err != nil {
+		switch {
+		case errors.Is(err, coreErrors.ErrNotRegistered):
+			return ctx.JSON(http.StatusForbidden, "Seuls les participants de l'événement peuvent partager des photos")
+		case errors.Is(err, coreErrors.ErrGalleryNotOpen):
+			return ctx.JSON(http.StatusUnprocessableEntity, "La galerie ouvre au début de l'événement")
+		case errors.Is(err, coreErrors.ErrEventCancelled):
+			return ctx.JSON(http.StatusConflict, "L'événement est annulé")
+		case errors.Is(err, coreErrors.ErrInvalidImage):
+			return ctx.JSON(http.StatusUnprocessableEntity, "Le fichier n'est pas une image JPEG, PNG ou GIF valide")
+		case errors.Is(err, coreErrors.ErrImageTooLarge):
+			return ctx.JSON(http.StatusRequestEntityTooLarge, "La photo dépasse la taille autorisée")
+		case errors.Is(err, coreErrors.ErrPhotoQuotaExceeded):
+			return ctx.JSON(http.StatusForbidden, "Quota de photos atteint")
+		}
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusCreated, photo)
+}
+
+// SetPhotoHidden masque ou rétablit une photo de la galerie
+func (c *EventPhotoController) SetPhotoHidden(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Hidden *bool `json:"hidden"`
+	}
+	if err := ctx.Bind(&body); err != nil || body.Hidden == nil {
+		return ctx.JSON(http.StatusBadRequest, "Le champ hidden est obligatoire")
+	}
+
+	photo, err := c.PhotoService.GetPhoto(event.ID, ctx.Param("photoId"))
+	if err != nil {
+		return photoErrorResponse(ctx, err)
+	}
+
+	if err := c.PhotoService.SetPhotoHidden(photo, *body.Hidden); err != nil {
+		return photoErrorResponse(ctx, err)
+	}
+	withPhotoFileURLs(photo)
+
+	return ctx.JSON(http.StatusOK, photo)
+}
+
+// DeletePhoto supprime une photo de la galerie : le responsable peut supprimer toutes les photos, un membre les siennes
+func (c *EventPhotoController) DeletePhoto(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	photo, err := c.PhotoService.GetPhoto(event.ID, ctx.Param("photoId"))
+	if err != nil {
+		return photoErrorResponse(ctx, err)
+	}
+	if photo.UserID != user.ID {
+		isLeader, err := c.EventService.IsEventLeader(&user, event)
+		if err != nil {
+			ctx.Logger().Error(err)
+			return ctx.NoContent(http.StatusInternalServerError)
+		}
+		if !isLeader {
+			return ctx.JSON(http.StatusForbidden, "Vous ne pouvez supprimer que vos propres photos")
+		}
+	}
+
+	if err := c.PhotoService.DeletePhoto(photo); err != nil {
+		return photoErrorResponse(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// SetCoverPhoto choisit la photo de couverture de l'événement ; un photo_id vide la retire
+func (c *EventPhotoController) SetCoverPhoto(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		PhotoID string `json:"photo_id"`
+	}
+	if err := ctx.Bind(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+
+	if err := c.PhotoService.SetCoverPhoto(event, body.PhotoID); err != nil {
+		return photoErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, event)
+}
+
+// withPhotoFileURLs remplace les URL publiques d'une photo masquée, dont les fichiers ne sont plus servis sous
+// /public, par celles de GetPhotoFile
+func withPhotoFileURLs(photo *models.EventPhoto) {
+	if !photo.Hidden {
+		return
+	}
+	photo.URL = fmt.Sprintf("events/%s/photos/%s/file", photo.EventID, photo.ID)
+	photo.ThumbnailURL = photo.URL + "?thumbnail=true"
+}
+
+func photoErrorResponse(ctx echo.Context, err error) error {
+	if errors.Is(err, coreErrors.ErrNotFound) {
+		return ctx.JSON(http.StatusNotFound, "Photo introuvable")
+	}
+	ctx.Logger().Error(err)
+	return ctx.NoContent(http.StatusInternalServerError)
+}
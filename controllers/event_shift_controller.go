@@ -0,0 +1,222 @@
+package controllers
+
+import (
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/utils"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type EventShiftController struct {
+	EventService      *services.EventService
+	VisibilityService *services.EventVisibilityService
+	ShiftService      *services.EventShiftService
+}
+
+func NewEventShiftController() *EventShiftController {
+	return &EventShiftController{
+		EventService:      services.NewEventService(),
+		VisibilityService: services.NewEventVisibilityService(),
+		ShiftService:      services.NewEventShiftService(),
+	}
+}
+
+// GetShifts renvoie les créneaux de bénévolat de l'événement et leur nombre d'inscrits
+func (c *EventShiftController) GetShifts(ctx echo.Context) error {
+	event, err := visibleEvent(ctx, c.EventService, c.VisibilityService)
+	if err != nil {
+		return err
+	}
+
+	shifts, err := c.ShiftService.GetShifts(event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, shifts)
+}
+
+// GetRoster renvoie aux responsables les créneaux de l'événement avec leurs bénévoles
+func (c *EventShiftController) GetRoster(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	shifts, err := c.ShiftService.GetRoster(event)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, shifts)
+}
+
+// CreateShift ajoute un créneau de bénévolat à l'événement
+func (c *EventShiftController) CreateShift(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	var shift models.EventShift
+	if err := ctx.Bind(&shift); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+
+	if err := c.ShiftService.CreateShift(event, &shift); err != nil {
+		return shiftErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, shift)
+}
+
+// UpdateShift modifie un créneau de bénévolat
+func (c *EventShiftController) UpdateShift(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	shift, err := c.ShiftService.GetShift(event, ctx.Param("shiftId"))
+	if err != nil {
+		return shiftErrorResponse(ctx, err)
+	}
+
+	var input models.EventShift
+	if err := ctx.Bind(&input); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+
+	if err := c.ShiftService.UpdateShift(shift, &input); err != nil {
+		return shiftErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, shift)
+}
+
+// DeleteShift supprime un créneau de bénévolat et ses inscriptions
+func (c *EventShiftController) DeleteShift(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	shift, err := c.ShiftService.GetShift(event, ctx.Param("shiftId"))
+	if err != nil {
+		return shiftErrorResponse(ctx, err)
+	}
+
+	if err := c.ShiftService.DeleteShift(shift); err != nil {
+		return shiftErrorResponse(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// SignUp inscrit l'utilisateur connecté au créneau
+func (c *EventShiftController) SignUp(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	event, err := visibleEvent(ctx, c.EventService, c.VisibilityService)
+	if err != nil {
+		return err
+	}
+	if event.IsCancelled() {
+		return ctx.JSON(http.StatusConflict, "L'événement est annulé")
+	}
+
+	shift, err := c.ShiftService.GetShift(event, ctx.Param("shiftId"))
+	if err != nil {
+		return shiftErrorResponse(ctx, err)
+	}
+
+	signup, err := c.ShiftService.SignUp(shift, &user)
+	if err != nil {
+		return shiftErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, signup)
+}
+
+// Withdraw désinscrit l'utilisateur connecté du créneau
+func (c *EventShiftController) Withdraw(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+
+	shift, err := c.ShiftService.GetShift(event, ctx.Param("shiftId"))
+	if err != nil {
+		return shiftErrorResponse(ctx, err)
+	}
+
+	if err := c.ShiftService.Withdraw(shift, user.ID); err != nil {
+		return shiftErrorResponse(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// CompleteSignup valide le créneau effectué par un bénévole, qui reçoit les points de l'événement
+func (c *EventShiftController) CompleteSignup(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	shift, err := c.ShiftService.GetShift(event, ctx.Param("shiftId"))
+	if err != nil {
+		return shiftErrorResponse(ctx, err)
+	}
+
+	user := ctx.Get("user").(models.User)
+	signup, err := c.ShiftService.CompleteSignup(event, shift, ctx.Param("userId"), &user, time.Now())
+	if err != nil {
+		return shiftErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, signup)
+}
+
+// shiftErrorResponse traduit les erreurs du service en réponse HTTP
+func shiftErrorResponse(ctx echo.Context, err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return ctx.JSON(http.StatusUnprocessableEntity, utils.GetValidationErrors(validationErrs, models.EventShift{}))
+	}
+
+	switch {
+	case errors.Is(err, coreErrors.ErrNotFound):
+		return ctx.JSON(http.StatusNotFound, "Créneau introuvable")
+	case errors.Is(err, coreErrors.ErrNotRegistered):
+		return ctx.JSON(http.StatusNotFound, "Inscription au créneau introuvable")
+	case errors.Is(err, coreErrors.ErrShiftRoleRequired):
+		return ctx.JSON(http.StatusForbidden, "Ce créneau est réservé à un autre rôle")
+	case errors.Is(err, coreErrors.ErrShiftFull):
+		return ctx.JSON(http.StatusConflict, "Le créneau est complet")
+	case errors.Is(err, coreErrors.ErrShiftOverlap):
+		return ctx.JSON(http.StatusConflict, "Ce créneau chevauche un autre créneau d'un bénévole")
+	case errors.Is(err, coreErrors.ErrAlreadySignedUp):
+		return ctx.JSON(http.StatusConflict, "Déjà inscrit à ce créneau")
+	case errors.Is(err, coreErrors.ErrShiftAlreadyCompleted):
+		return ctx.JSON(http.StatusConflict, "Le créneau a déjà été validé")
+	}
+	ctx.Logger().Error(err)
+	return ctx.NoContent(http.StatusInternalServerError)
+}
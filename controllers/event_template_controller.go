@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/utils"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type EventTemplateController struct {
+	EventService    *services.EventService
+	TemplateService *services.EventTemplateService
+}
+
+func NewEventTemplateController() *EventTemplateController {
+	return &EventTemplateController{
+		EventService:    services.NewEventService(),
+		TemplateService: services.NewEventTemplateService(),
+	}
+}
+
+// eventFromTemplateBody est le corps des requêtes créant un événement à partir d'un modèle ou d'un autre événement
+type eventFromTemplateBody struct {
+	Date *time.Time `json:"date"`
+	Name string     `json:"name"`
+}
+
+// GetTemplates renvoie les modèles d'événements de l'association passée en paramètre association_id
+func (c *EventTemplateController) GetTemplates(ctx echo.Context) error {
+	associationID := ctx.QueryParam("association_id")
+	if associationID == "" {
+		return ctx.JSON(http.StatusBadRequest, "Association manquante")
+	}
+	if _, err := requireAssociationOwner(ctx, associationID); err != nil {
+		return err
+	}
+
+	templates, err := c.TemplateService.GetTemplates(associationID)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, templates)
+}
+
+// SaveAsTemplate enregistre l'événement comme modèle de l'association organisatrice
+func (c *EventTemplateController) SaveAsTemplate(ctx echo.Context) error {
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+	if _, err := requireAssociationOwner(ctx, event.AssociationID); err != nil {
+		return err
+	}
+
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := ctx.Bind(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+
+	template, err := c.TemplateService.SaveAsTemplate(event, body.Name)
+	if err != nil {
+		return templateErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, template)
+}
+
+// DeleteTemplate supprime un modèle d'événement
+func (c *EventTemplateController) DeleteTemplate(ctx echo.Context) error {
+	template, err := c.ownedTemplate(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.TemplateService.DeleteTemplate(template); err != nil {
+		return templateErrorResponse(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// CreateEventFromTemplate crée un brouillon d'événement à la date demandée à partir d'un modèle
+func (c *EventTemplateController) CreateEventFromTemplate(ctx echo.Context) error {
+	template, err := c.ownedTemplate(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body eventFromTemplateBody
+	if err := ctx.Bind(&body); err != nil || body.Date == nil {
+		return ctx.JSON(http.StatusBadRequest, "La date de l'événement est obligatoire")
+	}
+
+	event, err := c.TemplateService.CreateEventFromTemplate(template, *body.Date, body.Name)
+	if err != nil {
+		return templateErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, event)
+}
+
+// DuplicateEvent crée un brouillon copiant l'événement, son formulaire et ses créneaux à la date demandée
+func (c *EventTemplateController) DuplicateEvent(ctx echo.Context) error {
+	event, err := c.EventService.GetEventById(ctx.Param("id"))
+	if err != nil {
+		return ctx.JSON(http.StatusNotFound, "Événement non trouvé")
+	}
+	if _, err := requireAssociationOwner(ctx, event.AssociationID); err != nil {
+		return err
+	}
+
+	var body eventFromTemplateBody
+	if err := ctx.Bind(&body); err != nil || body.Date == nil {
+		return ctx.JSON(http.StatusBadRequest, "La date de l'événement est obligatoire")
+	}
+
+	duplicate, err := c.TemplateService.DuplicateEvent(event, *body.Date, body.Name)
+	if err != nil {
+		return templateErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, duplicate)
+}
+
+// ownedTemplate charge le modèle de la requête si l'utilisateur est responsable de son association
+func (c *EventTemplateController) ownedTemplate(ctx echo.Context) (*models.EventTemplate, error) {
+	template, err := c.TemplateService.GetTemplate(ctx.Param("templateId"))
+	if err != nil {
+		if errors.Is(err, coreErrors.ErrNotFound) {
+			return nil, echo.NewHTTPError(http.StatusNotFound, "Modèle introuvable")
+		}
+		ctx.Logger().Error(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	if _, err := requireAssociationOwner(ctx, template.AssociationID); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func templateErrorResponse(ctx echo.Context, err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return ctx.JSON(http.StatusUnprocessableEntity, utils.GetValidationErrors(validationErrs, models.EventTemplate{}))
+	}
+
+	switch {
+	case errors.Is(err, coreErrors.ErrInvalidEventSchedule):
+		return ctx.JSON(http.StatusUnprocessableEntity, "La fin de l'événement doit être postérieure à son début")
+	case errors.Is(err, coreErrors.ErrInvalidVenue):
+		return ctx.JSON(http.StatusUnprocessableEntity, "Le lieu n'appartient pas à l'association")
+	case errors.Is(err, coreErrors.ErrInvalidQuestion):
+		return ctx.JSON(http.StatusUnprocessableEntity, "Le formulaire d'inscription du modèle est invalide")
+	}
+	ctx.Logger().Error(err)
+	return ctx.NoContent(http.StatusInternalServerError)
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/services"
+	"backend/utils"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type LeaderboardController struct {
+	LeaderboardService *services.LeaderboardService
+}
+
+func NewLeaderboardController() *LeaderboardController {
+	return &LeaderboardController{
+		LeaderboardService: services.NewLeaderboardService(),
+	}
+}
+
+// GetLeaderboard renvoie le classement de la période en cours, général ou restreint à une association ou
+// à une catégorie
+func (c *LeaderboardController) GetLeaderboard(ctx echo.Context) error {
+	period := enums.LeaderboardPeriod(ctx.QueryParam("period"))
+	if period == "" {
+		period = enums.AllTimeLeaderboard
+	}
+	scope := services.LeaderboardScope{
+		AssociationID: ctx.QueryParam("association_id"),
+		CategoryID:    ctx.QueryParam("category_id"),
+	}
+
+	leaderboard, err := c.LeaderboardService.GetLeaderboard(period, scope, time.Now(), utils.PaginationFromContext(ctx))
+	if err != nil {
+		if errors.Is(err, coreErrors.ErrInvalidLeaderboard) {
+			return ctx.JSON(http.StatusBadRequest, "Période invalide, ou association et catégorie demandées ensemble")
+		}
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, leaderboard)
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"backend/services"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ParticipantExportController struct {
+	EventService  *services.EventService
+	ExportService *services.ParticipantExportService
+}
+
+func NewParticipantExportController() *ParticipantExportController {
+	return &ParticipantExportController{
+		EventService:  services.NewEventService(),
+		ExportService: services.NewParticipantExportService(),
+	}
+}
+
+// ExportEventParticipants exporte la liste des inscrits de l'événement au format CSV ou XLSX
+func (c *ParticipantExportController) ExportEventParticipants(ctx echo.Context) error {
+	event, err := leaderEvent(ctx, c.EventService)
+	if err != nil {
+		return err
+	}
+
+	options, err := services.ParseExportOptions(ctx.QueryParam("format"), ctx.QueryParam("columns"), services.EventExportColumns)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	writeExportHeaders(ctx, options, fmt.Sprintf("participants-%s", event.ID))
+	if err := c.ExportService.ExportEventParticipants(event, options, ctx.Response()); err != nil {
+		ctx.Logger().Error(err)
+	}
+	return nil
+}
+
+// ExportAssociationParticipants exporte la liste des inscrits des événements de l'association entre from et to
+func (c *ParticipantExportController) ExportAssociationParticipants(ctx echo.Context) error {
+	association, err := requireAssociationOwner(ctx, ctx.Param("associationId"))
+	if err != nil {
+		return err
+	}
+
+	from, err := timeQueryParam(ctx, "from")
+	if err != nil || from == nil {
+		return ctx.JSON(http.StatusBadRequest, "Date de début invalide")
+	}
+	to, err := timeQueryParam(ctx, "to")
+	if err != nil || to == nil || to.Before(*from) {
+		return ctx.JSON(http.StatusBadRequest, "Date de fin invalide")
+	}
+
+	options, err := services.ParseExportOptions(ctx.QueryParam("format"), ctx.QueryParam("columns"), services.AssociationExportColumns)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	writeExportHeaders(ctx, options, fmt.Sprintf("participants-%s-%s", association.ID, from.Format(time.DateOnly)))
+	if err := c.ExportService.ExportAssociationParticipants(association.ID, *from, *to, options, ctx.Response()); err != nil {
+		ctx.Logger().Error(err)
+	}
+	return nil
+}
+
+// writeExportHeaders envoie les en-têtes du fichier : le contenu est ensuite écrit au fil de l'eau
+func writeExportHeaders(ctx echo.Context, options services.ParticipantExportOptions, filename string) {
+	ctx.Response().Header().Set(echo.HeaderContentType, options.ContentType())
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, filename, options.Format))
+	ctx.Response().WriteHeader(http.StatusOK)
+}
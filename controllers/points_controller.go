@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/utils"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PointsController struct {
+	PointsService *services.PointsService
+}
+
+func NewPointsController() *PointsController {
+	return &PointsController{
+		PointsService: services.NewPointsService(),
+	}
+}
+
+// GetMyHistory renvoie les mouvements de points de l'utilisateur connecté, du plus récent au plus ancien
+func (c *PointsController) GetMyHistory(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	history, err := c.PointsService.GetHistory(user.ID, utils.PaginationFromContext(ctx))
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, history)
+}
+
+// GetUserHistory renvoie les mouvements de points d'un utilisateur
+func (c *PointsController) GetUserHistory(ctx echo.Context) error {
+	history, err := c.PointsService.GetHistory(ctx.Param("id"), utils.PaginationFromContext(ctx))
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, history)
+}
+
+// AdjustPoints ajoute ou retire manuellement des points à un utilisateur
+func (c *PointsController) AdjustPoints(ctx echo.Context) error {
+	user := ctx.Get("user").(models.User)
+
+	var body struct {
+		Amount int    `json:"amount"`
+		Reason string `json:"reason"`
+	}
+	if err := ctx.Bind(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+
+	entry, err := c.PointsService.Adjust(ctx.Param("id"), body.Amount, body.Reason, &user)
+	if err != nil {
+		return pointsErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, entry)
+}
+
+// ReverseEntry annule un mouvement de points
+func (c *PointsController) ReverseEntry(ctx echo.Context) error {
+	user := ctx.Get("user").(models.User)
+
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if err := ctx.Bind(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+
+	reversal, err := c.PointsService.Reverse(ctx.Param("entryId"), body.Reason, &user)
+	if err != nil {
+		return pointsErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, reversal)
+}
+
+func pointsErrorResponse(ctx echo.Context, err error) error {
+	switch {
+	case errors.Is(err, coreErrors.ErrNotFound):
+		return ctx.JSON(http.StatusNotFound, "Introuvable")
+	case errors.Is(err, coreErrors.ErrInvalidPointsAdjustment):
+		return ctx.JSON(http.StatusUnprocessableEntity, "L'ajustement doit avoir un montant non nul et un motif")
+	case errors.Is(err, coreErrors.ErrPointsAlreadyReversed):
+		return ctx.JSON(http.StatusConflict, "Ce mouvement a déjà été annulé ou est lui-même une annulation")
+	case errors.Is(err, coreErrors.ErrPointsNotReversible):
+		return ctx.JSON(http.StatusConflict, "Les points dépensés en récompenses sont rendus en refusant ou en annulant la demande")
+	}
+	ctx.Logger().Error(err)
+	return ctx.NoContent(http.StatusInternalServerError)
+}
@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/services"
+	"backend/utils"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RecommendationController struct {
+	RecommendationService *services.RecommendationService
+}
+
+func NewRecommendationController() *RecommendationController {
+	return &RecommendationController{
+		RecommendationService: services.NewRecommendationService(),
+	}
+}
+
+// GetRecommendations renvoie les événements à venir recommandés à l'utilisateur, du plus au moins pertinent
+func (c *RecommendationController) GetRecommendations(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	recommendations, err := c.RecommendationService.GetRecommendations(&user, time.Now(), utils.PaginationFromContext(ctx))
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, recommendations)
+}
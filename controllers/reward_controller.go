@@ -0,0 +1,284 @@
+package controllers
+
+import (
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/utils"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type RewardController struct {
+	RewardService *services.RewardService
+}
+
+func NewRewardController() *RewardController {
+	return &RewardController{
+		RewardService: services.NewRewardService(),
+	}
+}
+
+// GetRewards renvoie le catalogue des récompenses, éventuellement restreint à l'association passée en
+// paramètre association_id. Les responsables peuvent y ajouter les récompenses retirées avec include_inactive.
+func (c *RewardController) GetRewards(ctx echo.Context) error {
+	filter := services.RewardFilter{
+		AssociationID:   ctx.QueryParam("association_id"),
+		IncludeInactive: ctx.QueryParam("include_inactive") == "true",
+	}
+	if filter.IncludeInactive {
+		var associationID *string
+		if filter.AssociationID != "" {
+			associationID = &filter.AssociationID
+		}
+		if err := requireRewardManager(ctx, associationID); err != nil {
+			return err
+		}
+	}
+
+	rewards, err := c.RewardService.GetRewards(filter, utils.PaginationFromContext(ctx))
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, rewards)
+}
+
+// CreateReward ajoute une récompense au catalogue de l'association, ou au catalogue général pour les administrateurs
+func (c *RewardController) CreateReward(ctx echo.Context) error {
+	var reward models.Reward
+	if err := ctx.Bind(&reward); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+	if reward.AssociationID != nil && *reward.AssociationID == "" {
+		reward.AssociationID = nil
+	}
+	if err := requireRewardManager(ctx, reward.AssociationID); err != nil {
+		return err
+	}
+
+	if err := c.RewardService.CreateReward(&reward); err != nil {
+		return rewardErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, reward)
+}
+
+// UpdateReward modifie une récompense ; désactiver une récompense la retire du catalogue
+func (c *RewardController) UpdateReward(ctx echo.Context) error {
+	reward, err := c.managedReward(ctx)
+	if err != nil {
+		return err
+	}
+
+	var changes services.RewardChanges
+	if err := ctx.Bind(&changes); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+
+	updated, err := c.RewardService.UpdateReward(reward.ID, changes)
+	if err != nil {
+		return rewardErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, updated)
+}
+
+// DeleteReward supprime une récompense, ou la retire seulement du catalogue si elle a déjà été demandée
+func (c *RewardController) DeleteReward(ctx echo.Context) error {
+	reward, err := c.managedReward(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := c.RewardService.DeleteReward(reward); err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Redeem échange des points de l'utilisateur connecté contre la récompense
+func (c *RewardController) Redeem(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	redemption, err := c.RewardService.Redeem(ctx.Param("id"), &user)
+	if err != nil {
+		return rewardErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, redemption)
+}
+
+// GetMyRedemptions renvoie les demandes de récompenses de l'utilisateur connecté, avec leurs codes de retrait
+func (c *RewardController) GetMyRedemptions(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	redemptions, err := c.RewardService.GetUserRedemptions(user.ID, utils.PaginationFromContext(ctx))
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, redemptions)
+}
+
+// GetRewardRedemptions renvoie les demandes d'une récompense, éventuellement restreintes au statut passé en
+// paramètre status
+func (c *RewardController) GetRewardRedemptions(ctx echo.Context) error {
+	reward, err := c.managedReward(ctx)
+	if err != nil {
+		return err
+	}
+
+	status := enums.RedemptionStatus(ctx.QueryParam("status"))
+	redemptions, err := c.RewardService.GetRewardRedemptions(reward, status, utils.PaginationFromContext(ctx))
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, redemptions)
+}
+
+// FulfilRedemption remet la récompense correspondant au code de retrait présenté par l'utilisateur
+func (c *RewardController) FulfilRedemption(ctx echo.Context) error {
+	user := ctx.Get("user").(models.User)
+
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := ctx.Bind(&body); err != nil || body.Code == "" {
+		return ctx.JSON(http.StatusBadRequest, "Le code de retrait est obligatoire")
+	}
+
+	redemption, err := c.RewardService.GetRedemptionByCode(body.Code)
+	if err != nil {
+		return rewardErrorResponse(ctx, err)
+	}
+	if err := requireRewardManager(ctx, redemption.Reward.AssociationID); err != nil {
+		return err
+	}
+
+	if err := c.RewardService.Fulfil(redemption, &user, time.Now()); err != nil {
+		return rewardErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, redemption)
+}
+
+// RejectRedemption refuse une demande de récompense et rembourse l'utilisateur
+func (c *RewardController) RejectRedemption(ctx echo.Context) error {
+	user := ctx.Get("user").(models.User)
+
+	redemption, err := c.RewardService.GetRedemption(ctx.Param("redemptionId"))
+	if err != nil {
+		return rewardErrorResponse(ctx, err)
+	}
+	if err := requireRewardManager(ctx, redemption.Reward.AssociationID); err != nil {
+		return err
+	}
+
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if err := ctx.Bind(&body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
+	}
+
+	if err := c.RewardService.Reject(redemption, body.Reason, &user, time.Now()); err != nil {
+		return rewardErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, redemption)
+}
+
+// CancelRedemption annule une demande en attente de l'utilisateur connecté et lui rend ses points
+func (c *RewardController) CancelRedemption(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
+	}
+
+	redemption, err := c.RewardService.GetRedemption(ctx.Param("redemptionId"))
+	if err != nil || redemption.UserID != user.ID {
+		return ctx.JSON(http.StatusNotFound, "Demande introuvable")
+	}
+
+	if err := c.RewardService.Cancel(redemption, &user, time.Now()); err != nil {
+		return rewardErrorResponse(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, redemption)
+}
+
+// managedReward charge la récompense de la requête si l'utilisateur peut la gérer
+func (c *RewardController) managedReward(ctx echo.Context) (*models.Reward, error) {
+	reward, err := c.RewardService.GetReward(ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, coreErrors.ErrNotFound) {
+			return nil, echo.NewHTTPError(http.StatusNotFound, "Récompense introuvable")
+		}
+		ctx.Logger().Error(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError)
+	}
+	if err := requireRewardManager(ctx, reward.AssociationID); err != nil {
+		return nil, err
+	}
+	return reward, nil
+}
+
+// requireRewardManager vérifie que l'utilisateur peut gérer les récompenses de l'association ; les récompenses
+// sans association sont réservées aux administrateurs. En cas de refus, la réponse HTTP est renvoyée sous forme d'erreur.
+func requireRewardManager(ctx echo.Context, associationID *string) error {
+	if associationID != nil {
+		_, err := requireAssociationOwner(ctx, *associationID)
+		return err
+	}
+
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "Non autorisé")
+	}
+	if !enums.IsAdmin(user.Role) {
+		return echo.NewHTTPError(http.StatusForbidden, "Interdit : réservé aux administrateurs")
+	}
+	return nil
+}
+
+func rewardErrorResponse(ctx echo.Context, err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return ctx.JSON(http.StatusUnprocessableEntity, utils.GetValidationErrors(validationErrs, models.Reward{}))
+	}
+
+	switch {
+	case errors.Is(err, coreErrors.ErrNotFound):
+		return ctx.JSON(http.StatusNotFound, "Récompense introuvable")
+	case errors.Is(err, coreErrors.ErrInvalidPickupCode):
+		return ctx.JSON(http.StatusNotFound, "Code de retrait invalide")
+	case errors.Is(err, coreErrors.ErrRewardUnavailable):
+		return ctx.JSON(http.StatusConflict, "Cette récompense n'est plus disponible")
+	case errors.Is(err, coreErrors.ErrRewardOutOfStock):
+		return ctx.JSON(http.StatusConflict, "Cette récompense est épuisée")
+	case errors.Is(err, coreErrors.ErrInsufficientPoints):
+		return ctx.JSON(http.StatusConflict, "Vous n'avez pas assez de points")
+	case errors.Is(err, coreErrors.ErrRedemptionAlreadyHandled):
+		return ctx.JSON(http.StatusConflict, "Cette demande a déjà été traitée")
+	}
+	ctx.Logger().Error(err)
+	return ctx.NoContent(http.StatusInternalServerError)
+}
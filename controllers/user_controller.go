@@ -17,16 +17,19 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/oklog/ulid/v2"
-	"gorm.io/gorm"
 )
 
 type UserController struct {
-	UserService *services.UserService
+	UserService    *services.UserService
+	EventService   *services.EventService
+	CheckInService *services.CheckInService
 }
 
 func NewUserController() *UserController {
 	return &UserController{
-		UserService: services.NewUserService(),
+		UserService:    services.NewUserService(),
+		EventService:   services.NewEventService(),
+		CheckInService: services.NewCheckInService(),
 	}
 }
 
@@ -414,7 +417,7 @@ func (c *UserController) GetAssociationsEvents(ctx echo.Context) error {
 
 	pagination := utils.PaginationFromContext(ctx)
 
-	events, err := c.UserService.GetAssociationsEvents(user.ID, pagination)
+	events, err := c.UserService.GetAssociationsEvents(&user, pagination)
 	if err != nil {
 		return ctx.NoContent(http.StatusInternalServerError)
 	}
@@ -438,8 +441,6 @@ func (c *UserController) ConfirmParticipation(ctx echo.Context) error {
 		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Invalid user data"})
 	}
 
-	associationLeaderID := user.ID // ID de l'utilisateur actuel (leader de l'association)
-
 	// Récupérer la participation avec les relations nécessaires
 	var participation models.Participation
 	if err := database.CurrentDatabase.Preload("Event.Association").
@@ -450,31 +451,45 @@ func (c *UserController) ConfirmParticipation(ctx echo.Context) error {
 		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Participation not found"})
 	}
 
-	// Vérifier que l'utilisateur est bien le leader de l'association de l'événement
-	if participation.Event.Association.OwnerID != associationLeaderID {
+	// Vérifier que l'utilisateur est bien le leader de l'une des associations organisatrices
+	isLeader, err := c.EventService.IsEventLeader(&user, participation.Event)
+	if err != nil {
+		ctx.Logger().Errorf("Failed to check event leadership: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to check permissions"})
+	}
+	if !isLeader {
 		return ctx.JSON(http.StatusForbidden, map[string]string{"error": "You are not authorized to confirm this participation"})
 	}
 
-	// Vérifier si la participation est déjà confirmée ou déclinée
-	if participation.Status != "pending" {
-		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Participation already handled"})
+	// Confirmer la participation et attribuer les points dans une même transaction
+	if err := c.CheckInService.ConfirmParticipation(&participation, &user); err != nil {
+		if errors.Is(err, coreErrors.ErrParticipationAlreadyHandled) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Participation already handled"})
+		}
+		ctx.Logger().Errorf("Failed to confirm participation: %v", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to confirm participation"})
 	}
 
-	// Mettre à jour uniquement les champs spécifiques de la participation
-	if err := database.CurrentDatabase.Model(&models.Participation{}).Where("id = ?", participationID).Updates(map[string]interface{}{
-		"status":       "confirmed",
-		"is_attending": true,
-	}).Error; err != nil {
-		ctx.Logger().Errorf("Failed to update participation: %v", err)
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update participation"})
+	// Retourner une réponse réussie
+	return ctx.JSON(http.StatusOK, map[string]string{"message": "Participation confirmed successfully"})
+}
+
+// UpdatePrivacySettings modifie les paramètres de confidentialité de l'utilisateur connecté
+func (c *UserController) UpdatePrivacySettings(ctx echo.Context) error {
+	user, ok := ctx.Get("user").(models.User)
+	if !ok || user.ID == "" {
+		return ctx.NoContent(http.StatusUnauthorized)
 	}
 
-	// Incrémenter les points pour l'utilisateur lié à la participation
-	if err := database.CurrentDatabase.Model(&models.User{}).Where("id = ?", participation.UserID).Update("points_open", gorm.Expr("points_open + ?", participation.Event.Category.Note)).Error; err != nil {
-		ctx.Logger().Errorf("Failed to update user points: %v", err)
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update user points"})
+	var settings services.PrivacySettings
+	if err := ctx.Bind(&settings); err != nil {
+		return ctx.JSON(http.StatusBadRequest, "Données invalides")
 	}
 
-	// Retourner une réponse réussie
-	return ctx.JSON(http.StatusOK, map[string]string{"message": "Participation confirmed successfully"})
+	if err := c.UserService.UpdatePrivacySettings(user.ID, settings); err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, settings)
 }
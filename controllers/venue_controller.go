@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/utils"
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type VenueController struct {
+	VenueService *services.VenueService
+}
+
+func NewVenueController() *VenueController {
+	return &VenueController{
+		VenueService: services.NewVenueService(),
+	}
+}
+
+// GetAssociationVenues renvoie les lieux enregistrés par l'association
+func (c *VenueController) GetAssociationVenues(ctx echo.Context) error {
+	venues, err := c.VenueService.GetAssociationVenues(ctx.Param("associationId"))
+	if err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, venues)
+}
+
+func (c *VenueController) CreateVenue(ctx echo.Context) error {
+	if _, err := requireAssociationOwner(ctx, ctx.Param("associationId")); err != nil {
+		return err
+	}
+
+	var venue models.Venue
+	if err := ctx.Bind(&venue); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Mauvaise requête: Impossible de décoder le corps de la requête"})
+	}
+	venue.AssociationID = ctx.Param("associationId")
+
+	if err := c.VenueService.CreateVenue(&venue); err != nil {
+		return venueErrorResponse(ctx, err, venue)
+	}
+
+	return ctx.JSON(http.StatusCreated, venue)
+}
+
+func (c *VenueController) UpdateVenue(ctx echo.Context) error {
+	if _, err := requireAssociationOwner(ctx, ctx.Param("associationId")); err != nil {
+		return err
+	}
+
+	venue, err := c.VenueService.GetVenue(ctx.Param("associationId"), ctx.Param("venueId"))
+	if err != nil {
+		return venueErrorResponse(ctx, err, nil)
+	}
+
+	if err := ctx.Bind(venue); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Mauvaise requête: Impossible de décoder le corps de la requête"})
+	}
+	venue.ID = ctx.Param("venueId")
+	venue.AssociationID = ctx.Param("associationId")
+
+	if err := c.VenueService.UpdateVenue(venue); err != nil {
+		return venueErrorResponse(ctx, err, *venue)
+	}
+
+	return ctx.JSON(http.StatusOK, venue)
+}
+
+func (c *VenueController) DeleteVenue(ctx echo.Context) error {
+	if _, err := requireAssociationOwner(ctx, ctx.Param("associationId")); err != nil {
+		return err
+	}
+
+	venue, err := c.VenueService.GetVenue(ctx.Param("associationId"), ctx.Param("venueId"))
+	if err != nil {
+		return venueErrorResponse(ctx, err, nil)
+	}
+
+	if err := c.VenueService.DeleteVenue(venue); err != nil {
+		ctx.Logger().Error(err)
+		return ctx.NoContent(http.StatusInternalServerError)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func venueErrorResponse(ctx echo.Context, err error, venue interface{}) error {
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		return ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":   "Validation error",
+			"details": utils.GetValidationErrors(validationErrs, venue),
+		})
+	case errors.Is(err, coreErrors.ErrNotFound):
+		return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Lieu introuvable"})
+	}
+	ctx.Logger().Error(err)
+	return ctx.NoContent(http.StatusInternalServerError)
+}
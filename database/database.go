@@ -36,6 +36,23 @@ var Models = []interface{}{
 	&models.Category{},
 	&models.Event{},
 	&models.Participation{},
+	&models.EventRecurrenceException{},
+	&models.EventQuestion{},
+	&models.ParticipationAnswer{},
+	&models.EventInvitation{},
+	&models.Venue{},
+	&models.EventFeedback{},
+	&models.EventPhoto{},
+	&models.EventCoHost{},
+	&models.EventComment{},
+	&models.EventShift{},
+	&models.ShiftSignup{},
+	&models.EventTemplate{},
+	&models.PointsEntry{},
+	&models.Badge{},
+	&models.UserBadge{},
+	&models.Reward{},
+	&models.RewardRedemption{},
 }
 
 // InitDB initialise la base de données et effectue la migration
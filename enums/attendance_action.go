@@ -0,0 +1,24 @@
+package enums
+
+// AttendanceAction est l'action appliquée en lot aux participations d'un événement par ses responsables
+type AttendanceAction string
+
+const (
+	// ConfirmAttendance confirme les participations en attente et attribue les points
+	ConfirmAttendance AttendanceAction = "confirm"
+	// DeclineAttendance refuse les participations en attente, libérant leurs places
+	DeclineAttendance AttendanceAction = "decline"
+	// MarkPresent pointe les participants comme à l'entrée de l'événement
+	MarkPresent AttendanceAction = "present"
+	// MarkAbsent marque absents les participants qui ne se sont pas présentés
+	MarkAbsent AttendanceAction = "absent"
+)
+
+// IsValidAttendanceAction indique si l'action fait partie des actions de pointage en lot
+func IsValidAttendanceAction(action AttendanceAction) bool {
+	switch action {
+	case ConfirmAttendance, DeclineAttendance, MarkPresent, MarkAbsent:
+		return true
+	}
+	return false
+}
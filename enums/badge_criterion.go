@@ -0,0 +1,15 @@
+package enums
+
+// BadgeCriterion est l'activité comptée pour attribuer un badge
+type BadgeCriterion string
+
+const (
+	// AttendedEventsCriterion : participations confirmées ou pointées, éventuellement dans une catégorie
+	AttendedEventsCriterion BadgeCriterion = "attended_events"
+	// JoinedAssociationsCriterion : adhésions acceptées
+	JoinedAssociationsCriterion BadgeCriterion = "joined_associations"
+	// OrganisedEventsCriterion : événements publiés par les associations dont l'utilisateur est responsable
+	OrganisedEventsCriterion BadgeCriterion = "organised_events"
+	// CompletedShiftsCriterion : créneaux de bénévolat effectués
+	CompletedShiftsCriterion BadgeCriterion = "completed_shifts"
+)
@@ -0,0 +1,11 @@
+package enums
+
+// EventStatus est l'état de programmation d'un événement
+type EventStatus string
+
+const (
+	EventScheduled EventStatus = "scheduled"
+	EventCancelled EventStatus = "cancelled"
+	// EventPostponed : l'événement est reporté à une date encore inconnue
+	EventPostponed EventStatus = "postponed"
+)
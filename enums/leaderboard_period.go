@@ -0,0 +1,14 @@
+package enums
+
+// LeaderboardPeriod est la période sur laquelle les points d'un classement sont cumulés
+type LeaderboardPeriod string
+
+const (
+	WeekLeaderboard  LeaderboardPeriod = "week"
+	MonthLeaderboard LeaderboardPeriod = "month"
+	// SemesterLeaderboard : semestre universitaire, de septembre à janvier puis de février à août
+	SemesterLeaderboard LeaderboardPeriod = "semester"
+	AllTimeLeaderboard  LeaderboardPeriod = "all"
+)
+
+var LeaderboardPeriods = []LeaderboardPeriod{WeekLeaderboard, MonthLeaderboard, SemesterLeaderboard, AllTimeLeaderboard}
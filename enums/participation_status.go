@@ -0,0 +1,9 @@
+package enums
+
+// Statuts d'une participation à un événement
+const (
+	ParticipationPending    = "pending"
+	ParticipationConfirmed  = "confirmed"
+	ParticipationDeclined   = "declined"
+	ParticipationWaitlisted = "waitlisted"
+)
@@ -0,0 +1,15 @@
+package enums
+
+// PointsSource est l'origine d'un mouvement de points
+type PointsSource string
+
+const (
+	// ParticipationPoints : participation confirmée par un responsable ou par pointage
+	ParticipationPoints PointsSource = "participation"
+	// ShiftPoints : créneau de bénévolat effectué
+	ShiftPoints PointsSource = "shift"
+	// ManualPoints : ajustement saisi par un administrateur
+	ManualPoints PointsSource = "manual"
+	// RedemptionPoints : points dépensés pour une récompense, ou remboursés
+	RedemptionPoints PointsSource = "redemption"
+)
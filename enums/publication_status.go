@@ -0,0 +1,13 @@
+package enums
+
+// PublicationStatus détermine si un événement est diffusé aux membres
+type PublicationStatus string
+
+const (
+	// DraftPublication : brouillon, visible des seuls responsables
+	DraftPublication PublicationStatus = "draft"
+	// ScheduledPublication : publication programmée à une date donnée
+	ScheduledPublication PublicationStatus = "scheduled"
+	// PublishedPublication : diffusé selon la visibilité de l'événement
+	PublishedPublication PublicationStatus = "published"
+)
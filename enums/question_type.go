@@ -0,0 +1,16 @@
+package enums
+
+// QuestionType est le type de réponse attendu pour une question d'inscription
+type QuestionType string
+
+const (
+	TextQuestion           QuestionType = "text"
+	NumberQuestion         QuestionType = "number"
+	SingleChoiceQuestion   QuestionType = "single_choice"
+	MultipleChoiceQuestion QuestionType = "multiple_choice"
+)
+
+// IsChoiceQuestion indique si la réponse doit être choisie parmi les options de la question
+func IsChoiceQuestion(questionType QuestionType) bool {
+	return questionType == SingleChoiceQuestion || questionType == MultipleChoiceQuestion
+}
@@ -0,0 +1,15 @@
+package enums
+
+// RedemptionStatus est l'état d'une demande de récompense
+type RedemptionStatus string
+
+const (
+	// PendingRedemption : points débités, récompense à retirer auprès des responsables
+	PendingRedemption RedemptionStatus = "pending"
+	// FulfilledRedemption : récompense remise sur présentation du code de retrait
+	FulfilledRedemption RedemptionStatus = "fulfilled"
+	// RejectedRedemption : refusée par un responsable, les points sont remboursés
+	RejectedRedemption RedemptionStatus = "rejected"
+	// CancelledRedemption : annulée par l'utilisateur, les points sont remboursés
+	CancelledRedemption RedemptionStatus = "cancelled"
+)
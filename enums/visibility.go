@@ -0,0 +1,13 @@
+package enums
+
+// Visibility détermine qui peut consulter un événement
+type Visibility string
+
+const (
+	// PublicVisibility : visible par tous, y compris sans être connecté
+	PublicVisibility Visibility = "public"
+	// MembersVisibility : réservé aux membres acceptés de l'association
+	MembersVisibility Visibility = "members"
+	// InviteOnlyVisibility : réservé aux invités de l'événement
+	InviteOnlyVisibility Visibility = "invite_only"
+)
@@ -23,3 +23,48 @@ var ErrUserNotFound = errors.New("user not found")
 var ErrEmailAlreadyExists = errors.New("email already exists")
 var ErrInvalidPassword = errors.New("invalid password provided")
 var ErrMembershipNotFound = errors.New("membership not found")
+var ErrInvalidRecurrenceRule = errors.New("invalid recurrence rule")
+var ErrNotRecurringEvent = errors.New("event is not recurring")
+var ErrInvalidOccurrence = errors.New("date is not an occurrence of the event")
+var ErrInvalidCalendar = errors.New("invalid iCalendar data")
+var ErrCalendarTooLarge = errors.New("iCalendar data too large")
+var ErrInvalidCalendarURL = errors.New("invalid calendar URL")
+var ErrCalendarFetchFailed = errors.New("unable to fetch calendar")
+var ErrInvalidCheckInToken = errors.New("invalid check-in token")
+var ErrAlreadyCheckedIn = errors.New("participant already checked in")
+var ErrCheckInNotOpen = errors.New("check-in is not open for this event")
+var ErrNotRegistered = errors.New("user is not registered to the event")
+var ErrInvalidQuestion = errors.New("invalid registration question")
+var ErrInvalidAnswers = errors.New("invalid registration answers")
+var ErrEventCancelled = errors.New("event is cancelled")
+var ErrInvalidEventSchedule = errors.New("event end must be after its start")
+var ErrInvalidVenue = errors.New("venue does not belong to the association")
+var ErrFeedbackClosed = errors.New("feedback is not open for this event")
+var ErrInvalidImage = errors.New("file is not a supported image")
+var ErrImageTooLarge = errors.New("image exceeds the size limit")
+var ErrPhotoQuotaExceeded = errors.New("photo quota exceeded")
+var ErrGalleryNotOpen = errors.New("gallery opens when the event starts")
+var ErrInvalidCoHost = errors.New("association cannot co-host this event")
+var ErrInvalidPublishAt = errors.New("publication date must be in the future")
+var ErrEventAlreadyPublished = errors.New("event is already published")
+var ErrEventNotPublished = errors.New("event is not published")
+var ErrInvalidCommentReply = errors.New("replies can only answer a top-level comment")
+var ErrInvalidExportFormat = errors.New("export format must be csv or xlsx")
+var ErrInvalidExportColumn = errors.New("unknown export column")
+var ErrShiftFull = errors.New("shift is full")
+var ErrShiftOverlap = errors.New("user already volunteers on an overlapping shift")
+var ErrShiftRoleRequired = errors.New("user does not have the role required by the shift")
+var ErrAlreadySignedUp = errors.New("user already signed up for the shift")
+var ErrShiftAlreadyCompleted = errors.New("shift already completed")
+var ErrPointsAlreadyReversed = errors.New("points entry already reversed")
+var ErrParticipationAlreadyHandled = errors.New("participation already handled")
+var ErrInvalidPointsAdjustment = errors.New("points adjustment needs a non-zero amount and a reason")
+var ErrInvalidBadgeCategory = errors.New("badge category does not exist")
+var ErrInvalidLeaderboard = errors.New("leaderboard needs a valid period and at most one of association or category")
+var ErrPointsNotReversible = errors.New("points entry is managed by its redemption")
+var ErrInsufficientPoints = errors.New("not enough points")
+var ErrRewardUnavailable = errors.New("reward is not available")
+var ErrRewardOutOfStock = errors.New("reward is out of stock")
+var ErrRedemptionAlreadyHandled = errors.New("redemption already handled")
+var ErrInvalidPickupCode = errors.New("invalid pickup code")
+var ErrInvalidBulkAttendance = errors.New("bulk attendance needs a valid action and either participation IDs or all pending")
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"backend/services"
+	"fmt"
+	"time"
+)
+
+// MarkAbsenteesJob marque absents les inscrits non pointés une fois l'événement terminé
+func MarkAbsenteesJob() Job {
+	checkInService := services.NewCheckInService()
+
+	return Job{
+		Name:     "mark-absentees",
+		Interval: 15 * time.Minute,
+		Run: func(now time.Time) error {
+			count, err := checkInService.MarkAbsentees(now)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				fmt.Printf("%d participation(s) marquée(s) absente(s)\n", count)
+			}
+			return nil
+		},
+	}
+}
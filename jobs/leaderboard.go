@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"backend/services"
+	"time"
+)
+
+// RebuildLeaderboardsJob reconstruit les classements Redis à partir du registre de points, corrigeant les
+// mises à jour perdues et passant aux nouvelles périodes
+func RebuildLeaderboardsJob() Job {
+	leaderboardService := services.NewLeaderboardService()
+
+	return Job{
+		Name:     "rebuild-leaderboards",
+		Interval: 24 * time.Hour,
+		Run: func(now time.Time) error {
+			_, err := leaderboardService.Rebuild(now)
+			return err
+		},
+	}
+}
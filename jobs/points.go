@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"backend/services"
+	"fmt"
+	"time"
+)
+
+// ReconcilePointsJob aligne le solde de points des utilisateurs sur leur registre de points
+func ReconcilePointsJob() Job {
+	pointsService := services.NewPointsService()
+
+	return Job{
+		Name:     "reconcile-points",
+		Interval: 24 * time.Hour,
+		Run: func(now time.Time) error {
+			count, err := pointsService.ReconcileBalances()
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				fmt.Printf("%d solde(s) de points corrigé(s)\n", count)
+			}
+			return nil
+		},
+	}
+}
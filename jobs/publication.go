@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"backend/services"
+	"fmt"
+	"time"
+)
+
+// PublishScheduledEventsJob publie les événements dont la date de publication programmée est atteinte
+func PublishScheduledEventsJob() Job {
+	eventService := services.NewEventService()
+
+	return Job{
+		Name:     "publish-scheduled-events",
+		Interval: time.Minute,
+		Run: func(now time.Time) error {
+			count, err := eventService.PublishDueEvents(now)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				fmt.Printf("%d événement(s) publié(s)\n", count)
+			}
+			return nil
+		},
+	}
+}
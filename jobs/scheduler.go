@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Job est une tâche de fond exécutée à intervalle régulier
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(now time.Time) error
+}
+
+// Start lance chaque tâche dans sa propre goroutine jusqu'à l'annulation du contexte.
+// Une erreur est journalisée sans arrêter la tâche, qui sera relancée au prochain intervalle.
+func Start(ctx context.Context, jobs ...Job) {
+	for _, job := range jobs {
+		go run(ctx, job)
+	}
+}
+
+func run(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := job.Run(now); err != nil {
+				fmt.Printf("Erreur lors de l'exécution de la tâche %s: %v\n", job.Name, err)
+			}
+		}
+	}
+}
@@ -3,9 +3,13 @@ package main
 import (
 	"backend/config"
 	"backend/database"
+	"backend/jobs"
 	"backend/routers"
+	"backend/services"
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
@@ -23,6 +27,16 @@ var appRouters = []routers.Router{
 	&routers.ChatbotRouter{},
 	&routers.MessageRouter{},
 	&routers.WebSocketRouter{},
+	&routers.CalendarRouter{},
+	&routers.BadgeRouter{},
+	&routers.RewardRouter{},
+}
+
+var appJobs = []jobs.Job{
+	jobs.MarkAbsenteesJob(),
+	jobs.PublishScheduledEventsJob(),
+	jobs.ReconcilePointsJob(),
+	jobs.RebuildLeaderboardsJob(),
 }
 
 func main() {
@@ -63,8 +77,19 @@ func main() {
 		e.Logger.Fatal(err)
 		return
 	}
+	// Les soldes antérieurs au registre de points doivent y être repris avant toute nouvelle écriture
+	if _, err := services.NewPointsService().ReconcileBalances(); err != nil {
+		e.Logger.Fatal(err)
+		return
+	}
+	if _, err := services.NewLeaderboardService().Rebuild(time.Now()); err != nil {
+		e.Logger.Error(err)
+	}
+
 	routers.LoadRoutes(e, appRouters...)
 
+	jobs.Start(context.Background(), appJobs...)
+
 	// Serve static files for Flutter web
 	// e.Static("/app", utils.GetEnv("FLUTTER_BUILD_PATH", "flutter_build")+"/web")
 
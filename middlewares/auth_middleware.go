@@ -62,3 +62,16 @@ func AuthenticationMiddleware(roles ...enums.Role) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// OptionalAuthenticationMiddleware authentifie l'utilisateur lorsqu'un jeton est fourni et laisse
+// passer les visiteurs anonymes sans jeton. Un jeton invalide est refusé comme pour AuthenticationMiddleware.
+func OptionalAuthenticationMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	authenticated := AuthenticationMiddleware()(next)
+
+	return func(c echo.Context) error {
+		if c.Request().Header.Get("Authorization") == "" {
+			return next(c)
+		}
+		return authenticated(c)
+	}
+}
@@ -17,6 +17,8 @@ type Association struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 	ImageURL    string    `json:"image_url" faker:"url"`
 
+	CalendarToken string `json:"-" gorm:"index" faker:"-"`
+
 	// Foreign keys
 	OwnerID string `json:"owner_id" validate:"required" faker:"-"`
 
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"backend/enums"
+	"backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Badge est une règle déclarative : il est attribué à l'utilisateur dès que le nombre d'activités du critère
+// atteint le seuil, par exemple 5 événements suivis dans une catégorie
+type Badge struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"not null" validate:"required,max=100" faker:"word"`
+	Description string    `json:"description" validate:"max=500" faker:"sentence"`
+	ImageURL    string    `json:"image_url" faker:"url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Criterion enums.BadgeCriterion `json:"criterion" gorm:"not null;index" validate:"required,oneof=attended_events joined_associations organised_events completed_shifts" faker:"-"`
+	Threshold int                  `json:"threshold" gorm:"not null" validate:"min=1" faker:"-"`
+	// Restreint le critère attended_events aux événements de la catégorie
+	CategoryID *string `json:"category_id,omitempty" validate:"excluded_unless=Criterion attended_events" faker:"-"`
+
+	// Relationships
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty" validate:"-" faker:"-"`
+}
+
+func (b *Badge) BeforeCreate(tx *gorm.DB) (err error) {
+	b.ID = utils.GenerateULID()
+	b.CreatedAt = time.Now()
+	b.UpdatedAt = time.Now()
+	return nil
+}
+
+func (b *Badge) BeforeUpdate(tx *gorm.DB) (err error) {
+	b.UpdatedAt = time.Now()
+	return nil
+}
+
+// UserBadge est un badge obtenu par un utilisateur ; il reste acquis même si l'activité est annulée ensuite
+type UserBadge struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	AwardedAt time.Time `json:"awarded_at"`
+
+	// Foreign keys
+	UserID  string `json:"user_id" gorm:"not null;uniqueIndex:idx_user_badge"`
+	BadgeID string `json:"badge_id" gorm:"not null;uniqueIndex:idx_user_badge;index"`
+
+	// Relationships
+	Badge *Badge `gorm:"foreignKey:BadgeID" json:"badge,omitempty"`
+}
+
+func (b *UserBadge) BeforeCreate(tx *gorm.DB) (err error) {
+	b.ID = utils.GenerateULID()
+	if b.AwardedAt.IsZero() {
+		b.AwardedAt = time.Now()
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"backend/enums"
 	"backend/utils"
 	"time"
 
@@ -8,28 +9,151 @@ import (
 )
 
 type Event struct {
-	ID          string    `json:"id" gorm:"primaryKey"`
-	Name        string    `json:"name" gorm:"not null" faker:"word"`
-	Description string    `json:"description" faker:"sentence"`
-	Date        time.Time `json:"date"`
-	Location    string    `json:"location" faker:"word"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string           `json:"id" gorm:"primaryKey"`
+	Name        string           `json:"name" gorm:"not null" faker:"word"`
+	Description string           `json:"description" faker:"sentence"`
+	Date        time.Time        `json:"date"`
+	EndDate     *time.Time       `json:"end_date,omitempty" faker:"-"`
+	TimeZone    string           `json:"time_zone,omitempty" validate:"omitempty,timezone" faker:"-"`
+	Location    string           `json:"location" faker:"word"`
+	Capacity    *int             `json:"capacity,omitempty" validate:"omitempty,min=1" faker:"-"`
+	Visibility  enums.Visibility `json:"visibility" gorm:"not null;default:members;index" validate:"omitempty,oneof=public members invite_only" faker:"-"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+
+	// Un événement annulé ou reporté est conservé pour que ses participants en soient informés
+	Status       enums.EventStatus `json:"status" gorm:"not null;default:scheduled;index" validate:"omitempty,oneof=scheduled cancelled postponed" faker:"-"`
+	StatusReason string            `json:"status_reason,omitempty" faker:"-"`
+
+	// Un brouillon n'est visible que des responsables ; une publication programmée a lieu à PublishAt
+	PublicationStatus enums.PublicationStatus `json:"publication_status" gorm:"not null;default:published;index" validate:"omitempty,oneof=draft scheduled published" faker:"-"`
+	PublishAt         *time.Time              `json:"publish_at,omitempty" faker:"-"`
+	PublishedAt       *time.Time              `json:"published_at,omitempty" faker:"-"`
+
+	// Récurrence (RFC 5545) : une série porte la règle, ses occurrences modifiées ou
+	// matérialisées pointent vers elle avec la date d'origine de l'occurrence
+	RecurrenceRule     string     `json:"recurrence_rule,omitempty" faker:"-"`
+	RecurrenceParentID *string    `json:"recurrence_parent_id,omitempty" gorm:"uniqueIndex:idx_event_occurrence" faker:"-"`
+	RecurrenceID       *time.Time `json:"recurrence_id,omitempty" gorm:"uniqueIndex:idx_event_occurrence" faker:"-"`
+
+	// UID de l'événement dans le calendrier d'origine, pour rendre les imports iCalendar idempotents
+	SourceUID string `json:"source_uid,omitempty" gorm:"index" faker:"-"`
 
 	// Foreign keys
-	CategoryID    string `json:"category_id" faker:"-"`
-	AssociationID string `json:"association_id" validate:"required" faker:"-"`
+	CategoryID    string  `json:"category_id" faker:"-"`
+	AssociationID string  `json:"association_id" validate:"required" faker:"-"`
+	VenueID       *string `json:"venue_id,omitempty" gorm:"index" faker:"-"`
+	CoverPhotoID  *string `json:"cover_photo_id,omitempty" faker:"-"`
 
 	// Relationships
 	Category       Category        `gorm:"foreignKey:CategoryID" json:"category" validate:"-" faker:"-"`
 	Association    Association     `gorm:"foreignKey:AssociationID" json:"association" validate:"-" faker:"-"`
+	Venue          *Venue          `gorm:"foreignKey:VenueID" json:"venue,omitempty" validate:"-" faker:"-"`
+	CoverPhoto     *EventPhoto     `gorm:"foreignKey:CoverPhotoID;constraint:OnDelete:SET NULL" json:"cover_photo,omitempty" validate:"-" faker:"-"`
 	Participations []Participation `gorm:"foreignKey:EventID" json:"participations,omitempty" faker:"-"`
 	User           []User          `gorm:"many2many:participations;joinForeignKey:EventID;joinReferences:UserID" json:"users" faker:"-"`
+
+	// Dates d'occurrences supprimées de la série (EXDATE)
+	RecurrenceExceptions []EventRecurrenceException `gorm:"foreignKey:EventID" json:"recurrence_exceptions,omitempty" faker:"-"`
+
+	// Événements au même lieu sur le même créneau, renvoyés à titre d'avertissement au responsable
+	LocationConflicts []Event `gorm:"-" json:"location_conflicts,omitempty" faker:"-"`
+
+	// Formulaire d'inscription, porté par la série pour un événement récurrent
+	Questions []EventQuestion `gorm:"foreignKey:EventID" json:"questions,omitempty" faker:"-"`
+
+	// Invités d'un événement sur invitation, portés par la série pour un événement récurrent
+	Invitations []EventInvitation `gorm:"foreignKey:EventID" json:"-" faker:"-"`
+
+	// Associations co-organisatrices, portées par la série pour un événement récurrent
+	CoHosts []EventCoHost `gorm:"foreignKey:EventID" json:"co_hosts,omitempty" faker:"-"`
 }
 
 func (e *Event) BeforeCreate(tx *gorm.DB) (err error) {
 	e.ID = utils.GenerateULID()
 	e.CreatedAt = time.Now()
 	e.UpdatedAt = time.Now()
+	if e.Visibility == "" {
+		e.Visibility = enums.MembersVisibility
+	}
+	if e.Status == "" {
+		e.Status = enums.EventScheduled
+	}
+	if e.PublicationStatus == "" {
+		e.PublicationStatus = enums.PublishedPublication
+	}
 	return nil
 }
+
+// EndsAt renvoie la fin de l'événement, DefaultEventDuration après son début s'il n'a pas d'heure de fin
+func (e *Event) EndsAt() time.Time {
+	if e.EndDate != nil {
+		return *e.EndDate
+	}
+	return e.Date.Add(DefaultEventDuration)
+}
+
+// Duration renvoie la durée de l'événement
+func (e *Event) Duration() time.Duration {
+	return e.EndsAt().Sub(e.Date)
+}
+
+// Overlaps indique si l'événement chevauche la période [start, end)
+func (e *Event) Overlaps(start, end time.Time) bool {
+	return e.Date.Before(end) && e.EndsAt().After(start)
+}
+
+// TimeLocation renvoie le fuseau horaire IANA de l'événement, ou fallback s'il n'en a pas
+func (e *Event) TimeLocation(fallback *time.Location) *time.Location {
+	if e.TimeZone == "" {
+		return fallback
+	}
+	location, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		return fallback
+	}
+	return location
+}
+
+// IsFull indique si toutes les places sont prises, seatsTaken étant le nombre de participants inscrits
+func (e *Event) IsFull(seatsTaken int64) bool {
+	return e.Capacity != nil && seatsTaken >= int64(*e.Capacity)
+}
+
+// IsPublished indique si l'événement est diffusé aux membres
+func (e *Event) IsPublished() bool {
+	return e.PublicationStatus == enums.PublishedPublication
+}
+
+// IsCancelled indique si l'événement a été annulé
+func (e *Event) IsCancelled() bool {
+	return e.Status == enums.EventCancelled
+}
+
+// IsRecurring indique si l'événement est une série récurrente
+func (e *Event) IsRecurring() bool {
+	return e.RecurrenceRule != "" && e.RecurrenceParentID == nil
+}
+
+// IsOccurrence indique si l'événement représente une occurrence d'une série
+func (e *Event) IsOccurrence() bool {
+	return e.RecurrenceParentID != nil && e.RecurrenceID != nil
+}
+
+// FormEventID renvoie l'identifiant de l'événement portant le formulaire d'inscription :
+// la série pour une occurrence, l'événement lui-même sinon
+func (e *Event) FormEventID() string {
+	if e.RecurrenceParentID != nil {
+		return *e.RecurrenceParentID
+	}
+	return e.ID
+}
+
+// ExceptionDates renvoie les dates d'occurrences exclues de la série
+func (e *Event) ExceptionDates() []time.Time {
+	dates := make([]time.Time, len(e.RecurrenceExceptions))
+	for i, exception := range e.RecurrenceExceptions {
+		dates[i] = exception.OccurrenceDate
+	}
+	return dates
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"backend/enums"
+	"backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventCoHost invite une association à co-organiser un événement. Une fois l'invitation acceptée,
+// l'événement apparaît dans ses listes et ses responsables gèrent les participations au même titre
+// que l'association organisatrice. Pour un événement récurrent, elle porte sur la série.
+type EventCoHost struct {
+	ID          string       `json:"id" gorm:"primaryKey"`
+	Status      enums.Status `json:"status" gorm:"not null;default:pending" validate:"oneof=pending accepted rejected" faker:"-"`
+	CreatedAt   time.Time    `json:"created_at"`
+	RespondedAt *time.Time   `json:"responded_at,omitempty" faker:"-"`
+
+	// Foreign keys
+	EventID       string `json:"event_id" gorm:"uniqueIndex:idx_event_co_host" faker:"-"`
+	AssociationID string `json:"association_id" gorm:"uniqueIndex:idx_event_co_host;index" faker:"-"`
+	InvitedByID   string `json:"invited_by_id" faker:"-"`
+
+	// Relationships
+	Association *Association `gorm:"foreignKey:AssociationID" json:"association,omitempty" faker:"-"`
+	Event       *Event       `gorm:"foreignKey:EventID" json:"event,omitempty" faker:"-"`
+}
+
+func (h *EventCoHost) BeforeCreate(tx *gorm.DB) (err error) {
+	h.ID = utils.GenerateULID()
+	h.CreatedAt = time.Now()
+	if h.Status == "" {
+		h.Status = enums.Pending
+	}
+	return nil
+}
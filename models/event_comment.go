@@ -0,0 +1,41 @@
+package models
+
+import (
+	"backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventComment est un message du fil de discussion d'un événement, porté par la série pour un événement
+// récurrent. Les réponses ne portent que sur un commentaire de premier niveau.
+type EventComment struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	Content   string    `json:"content" gorm:"not null" validate:"required,max=2000" faker:"sentence"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Positionné par un responsable lorsqu'une question a reçu sa réponse
+	Answered bool `json:"answered" gorm:"not null;default:false" faker:"-"`
+
+	// Foreign keys
+	EventID  string  `json:"event_id" gorm:"not null;index" faker:"-"`
+	UserID   string  `json:"user_id" gorm:"not null" faker:"-"`
+	ParentID *string `json:"parent_id,omitempty" gorm:"index" faker:"-"`
+
+	// Relationships
+	User    *User          `gorm:"foreignKey:UserID" json:"user,omitempty" faker:"-"`
+	Replies []EventComment `gorm:"foreignKey:ParentID" json:"replies,omitempty" faker:"-"`
+}
+
+func (c *EventComment) BeforeCreate(tx *gorm.DB) (err error) {
+	c.ID = utils.GenerateULID()
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+func (c *EventComment) BeforeUpdate(tx *gorm.DB) (err error) {
+	c.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventFeedback est l'avis laissé par un participant après l'événement. Un participant
+// ne laisse qu'un avis par événement, qu'il peut modifier tant que le recueil est ouvert.
+type EventFeedback struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	Rating    int       `json:"rating" gorm:"not null" validate:"min=1,max=5"`
+	Comment   string    `json:"comment" validate:"max=2000"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Foreign keys
+	EventID string `json:"event_id" gorm:"not null;uniqueIndex:idx_event_feedback" faker:"-"`
+	UserID  string `json:"user_id" gorm:"not null;uniqueIndex:idx_event_feedback" faker:"-"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty" faker:"-"`
+}
+
+func (f *EventFeedback) BeforeCreate(tx *gorm.DB) (err error) {
+	f.ID = utils.GenerateULID()
+	f.CreatedAt = time.Now()
+	f.UpdatedAt = time.Now()
+	return nil
+}
+
+func (f *EventFeedback) BeforeUpdate(tx *gorm.DB) (err error) {
+	f.UpdatedAt = time.Now()
+	return nil
+}
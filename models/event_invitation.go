@@ -0,0 +1,29 @@
+package models
+
+import (
+	"backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventInvitation ouvre un événement sur invitation à un utilisateur. Pour un événement
+// récurrent, l'invitation porte sur la série et vaut pour toutes ses occurrences.
+type EventInvitation struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Foreign keys
+	EventID     string `json:"event_id" gorm:"uniqueIndex:idx_event_invitation" faker:"-"`
+	UserID      string `json:"user_id" gorm:"uniqueIndex:idx_event_invitation" faker:"-"`
+	InvitedByID string `json:"invited_by_id" faker:"-"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty" faker:"-"`
+}
+
+func (i *EventInvitation) BeforeCreate(tx *gorm.DB) (err error) {
+	i.ID = utils.GenerateULID()
+	i.CreatedAt = time.Now()
+	return nil
+}
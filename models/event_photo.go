@@ -0,0 +1,37 @@
+package models
+
+import (
+	"backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventPhoto est une photo partagée dans la galerie d'un événement. Une photo masquée par
+// le responsable n'est plus visible que par lui et par l'auteur de l'envoi.
+type EventPhoto struct {
+	ID           string    `json:"id" gorm:"primaryKey"`
+	URL          string    `json:"url" gorm:"not null"`
+	ThumbnailURL string    `json:"thumbnail_url" gorm:"not null"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Hidden       bool      `json:"hidden" gorm:"not null;default:false"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Foreign keys
+	EventID string `json:"event_id" gorm:"not null;index" faker:"-"`
+	UserID  string `json:"user_id" gorm:"not null;index" faker:"-"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty" faker:"-"`
+}
+
+func (p *EventPhoto) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == "" {
+		p.ID = utils.GenerateULID()
+	}
+	p.CreatedAt = time.Now()
+	return nil
+}
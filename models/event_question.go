@@ -0,0 +1,41 @@
+package models
+
+import (
+	"backend/enums"
+	"backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventQuestion est une question posée lors de l'inscription à un événement
+type EventQuestion struct {
+	ID        string             `json:"id" gorm:"primaryKey"`
+	Label     string             `json:"label" gorm:"not null" validate:"required,max=255"`
+	Type      enums.QuestionType `json:"type" gorm:"not null" validate:"required,oneof=text number single_choice multiple_choice"`
+	Options   StringList         `json:"options,omitempty" gorm:"type:jsonb"`
+	Required  bool               `json:"required" gorm:"default:false"`
+	Position  int                `json:"position"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+
+	// Foreign keys
+	EventID string `json:"event_id" gorm:"index" faker:"-"`
+}
+
+func (q *EventQuestion) BeforeCreate(tx *gorm.DB) (err error) {
+	q.ID = utils.GenerateULID()
+	q.CreatedAt = time.Now()
+	q.UpdatedAt = time.Now()
+	return nil
+}
+
+// HasOption indique si la valeur fait partie des choix proposés
+func (q *EventQuestion) HasOption(value string) bool {
+	for _, option := range q.Options {
+		if option == value {
+			return true
+		}
+	}
+	return false
+}
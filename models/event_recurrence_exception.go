@@ -0,0 +1,23 @@
+package models
+
+import (
+	"backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EventRecurrenceException struct {
+	ID             string    `json:"id" gorm:"primaryKey"`
+	OccurrenceDate time.Time `json:"occurrence_date" gorm:"uniqueIndex:idx_event_exception_date" validate:"required"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Foreign keys
+	EventID string `json:"event_id" gorm:"uniqueIndex:idx_event_exception_date" faker:"-"`
+}
+
+func (e *EventRecurrenceException) BeforeCreate(tx *gorm.DB) (err error) {
+	e.ID = utils.GenerateULID()
+	e.CreatedAt = time.Now()
+	return nil
+}
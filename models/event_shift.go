@@ -0,0 +1,66 @@
+package models
+
+import (
+	"backend/enums"
+	"backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventShift est un créneau de bénévolat d'un événement (bar, installation...). RequiredRole, s'il est
+// renseigné, restreint les inscriptions aux utilisateurs ayant au moins ce rôle.
+type EventShift struct {
+	ID           string     `json:"id" gorm:"primaryKey"`
+	Name         string     `json:"name" gorm:"not null" validate:"required,max=100" faker:"word"`
+	Description  string     `json:"description" validate:"max=1000" faker:"sentence"`
+	StartsAt     time.Time  `json:"starts_at" gorm:"not null" validate:"required"`
+	EndsAt       time.Time  `json:"ends_at" gorm:"not null" validate:"required,gtfield=StartsAt"`
+	Capacity     int        `json:"capacity" gorm:"not null" validate:"required,min=1" faker:"boundary_start=1, boundary_end=20"`
+	RequiredRole enums.Role `json:"required_role,omitempty" validate:"omitempty,oneof=admin association_leader user" faker:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Nombre de bénévoles inscrits, calculé à la lecture
+	SignedUp int64 `json:"signed_up" gorm:"-" faker:"-"`
+
+	// Foreign keys
+	EventID string `json:"event_id" gorm:"not null;index" faker:"-"`
+
+	// Relationships
+	Signups []ShiftSignup `gorm:"foreignKey:ShiftID" json:"signups,omitempty" faker:"-"`
+}
+
+func (s *EventShift) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = utils.GenerateULID()
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *EventShift) BeforeUpdate(tx *gorm.DB) (err error) {
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// ShiftSignup est l'inscription d'un bénévole à un créneau. CompletedAt est renseigné par un responsable
+// une fois le créneau effectué, ce qui rapporte les points de la catégorie de l'événement.
+type ShiftSignup struct {
+	ID          string     `json:"id" gorm:"primaryKey"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" faker:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// Foreign keys
+	ShiftID string `json:"shift_id" gorm:"not null;uniqueIndex:idx_shift_signup_user" faker:"-"`
+	UserID  string `json:"user_id" gorm:"not null;uniqueIndex:idx_shift_signup_user" faker:"-"`
+
+	// Relationships
+	User  *User       `gorm:"foreignKey:UserID" json:"user,omitempty" faker:"-"`
+	Shift *EventShift `gorm:"foreignKey:ShiftID" json:"shift,omitempty" faker:"-"`
+}
+
+func (s *ShiftSignup) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = utils.GenerateULID()
+	s.CreatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"backend/enums"
+	"backend/utils"
+	"database/sql/driver"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EventTemplate est un modèle d'événement enregistré par une association pour les formats qu'elle
+// organise régulièrement. Il ne porte pas de date : la durée et les créneaux sont relatifs au début.
+type EventTemplate struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null" validate:"required,max=100" faker:"word"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Champs repris sur l'événement créé
+	EventName   string           `json:"event_name" gorm:"not null" validate:"required" faker:"word"`
+	Description string           `json:"description" faker:"sentence"`
+	Location    string           `json:"location" faker:"word"`
+	TimeZone    string           `json:"time_zone,omitempty" faker:"-"`
+	Capacity    *int             `json:"capacity,omitempty" faker:"-"`
+	Visibility  enums.Visibility `json:"visibility" faker:"-"`
+	// Durée de l'événement en minutes, nulle pour un événement sans fin
+	Duration int `json:"duration" faker:"-"`
+
+	Questions TemplateQuestionList `json:"questions" gorm:"type:jsonb" faker:"-"`
+	Shifts    TemplateShiftList    `json:"shifts" gorm:"type:jsonb" faker:"-"`
+
+	// Foreign keys
+	CategoryID    string  `json:"category_id" faker:"-"`
+	AssociationID string  `json:"association_id" gorm:"not null;index" validate:"required" faker:"-"`
+	VenueID       *string `json:"venue_id,omitempty" faker:"-"`
+
+	// Relationships
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty" validate:"-" faker:"-"`
+}
+
+func (t *EventTemplate) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = utils.GenerateULID()
+	t.CreatedAt = time.Now()
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+func (t *EventTemplate) BeforeUpdate(tx *gorm.DB) (err error) {
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// TemplateQuestion est une question du formulaire d'inscription d'un modèle
+type TemplateQuestion struct {
+	Label    string             `json:"label"`
+	Type     enums.QuestionType `json:"type"`
+	Options  StringList         `json:"options,omitempty"`
+	Required bool               `json:"required"`
+}
+
+// TemplateShift est un créneau de bénévolat d'un modèle ; Offset est son début en minutes par rapport
+// au début de l'événement, négatif pour un créneau d'installation
+type TemplateShift struct {
+	Name         string     `json:"name"`
+	Description  string     `json:"description,omitempty"`
+	Offset       int        `json:"offset"`
+	Duration     int        `json:"duration"`
+	Capacity     int        `json:"capacity"`
+	RequiredRole enums.Role `json:"required_role,omitempty"`
+}
+
+// TemplateQuestionList est stockée en JSON dans une seule colonne
+type TemplateQuestionList []TemplateQuestion
+
+func (l TemplateQuestionList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	return jsonValue(l)
+}
+
+func (l *TemplateQuestionList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+	return jsonScan(value, l)
+}
+
+// TemplateShiftList est stockée en JSON dans une seule colonne
+type TemplateShiftList []TemplateShift
+
+func (l TemplateShiftList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	return jsonValue(l)
+}
+
+func (l *TemplateShiftList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+	return jsonScan(value, l)
+}
@@ -1,6 +1,11 @@
 package models
 
+import "time"
+
 const (
 	DateFormat = "2006-01-02"
 	TimeFormat = "15:04:05"
 )
+
+// DefaultEventDuration est la durée prise en compte pour un événement sans heure de fin
+const DefaultEventDuration = 2 * time.Hour
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"backend/enums"
 	"backend/utils"
 	"time"
 
@@ -10,7 +11,18 @@ import (
 type Participation struct {
 	ID          string `json:"id" gorm:"primaryKey" validate:"required"`
 	IsAttending bool   `json:"is_attending" gorm:"default:false" validate:"-" faker:"bool"`
-	Status      string `json:"status" gorm:"default:'pending'" validate:"oneof=pending confirmed declined"`
+	Status      string `json:"status" gorm:"default:'pending'" validate:"oneof=pending confirmed declined waitlisted"`
+
+	// Liste d'attente : l'ordre d'inscription détermine l'ordre de promotion
+	WaitlistedAt     *time.Time `json:"waitlisted_at,omitempty" faker:"-"`
+	WaitlistPosition int        `json:"waitlist_position,omitempty" gorm:"-" faker:"-"`
+
+	// Pointage le jour de l'événement : présent au scan du QR code, absent une fois l'événement terminé
+	CheckedInAt *time.Time   `json:"checked_in_at,omitempty" faker:"-"`
+	Attendance  enums.Status `json:"attendance,omitempty" validate:"omitempty,oneof=present absent" faker:"-"`
+
+	// Événements auxquels l'utilisateur participe déjà sur le même créneau, renvoyés à titre d'avertissement
+	ScheduleConflicts []Event `json:"schedule_conflicts,omitempty" gorm:"-" faker:"-"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -20,8 +32,9 @@ type Participation struct {
 	EventID string `json:"event_id" gorm:"primaryKey" faker:"-"`
 
 	// Relationships
-	User  *User  `gorm:"foreignKey:UserID" json:"user" faker:"-"`
-	Event *Event `gorm:"foreignKey:EventID" json:"event,omitempty" faker:"-"`
+	User    *User                 `gorm:"foreignKey:UserID" json:"user" faker:"-"`
+	Event   *Event                `gorm:"foreignKey:EventID" json:"event,omitempty" faker:"-"`
+	Answers []ParticipationAnswer `gorm:"foreignKey:ParticipationID;references:ID;constraint:-" json:"answers,omitempty" faker:"-"`
 }
 
 func (p *Participation) BeforeCreate(tx *gorm.DB) (err error) {
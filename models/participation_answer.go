@@ -0,0 +1,30 @@
+package models
+
+import (
+	"backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ParticipationAnswer est la réponse d'un participant à une question d'inscription.
+// Value porte les réponses texte, nombre et choix unique ; Choices les choix multiples.
+type ParticipationAnswer struct {
+	ID        string     `json:"id" gorm:"primaryKey"`
+	Value     string     `json:"value,omitempty"`
+	Choices   StringList `json:"choices,omitempty" gorm:"type:jsonb"`
+	CreatedAt time.Time  `json:"created_at"`
+
+	// Foreign keys
+	ParticipationID string `json:"participation_id" gorm:"uniqueIndex:idx_participation_question" faker:"-"`
+	QuestionID      string `json:"question_id" gorm:"uniqueIndex:idx_participation_question" faker:"-"`
+
+	// Relationships
+	Question *EventQuestion `gorm:"foreignKey:QuestionID" json:"question,omitempty" faker:"-"`
+}
+
+func (a *ParticipationAnswer) BeforeCreate(tx *gorm.DB) (err error) {
+	a.ID = utils.GenerateULID()
+	a.CreatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"backend/enums"
+	"backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PointsEntry est un mouvement du registre de points d'un utilisateur. Le registre n'est jamais modifié :
+// une annulation est une nouvelle écriture de montant opposé qui référence l'écriture annulée.
+type PointsEntry struct {
+	ID        string             `json:"id" gorm:"primaryKey"`
+	Amount    int                `json:"amount" gorm:"not null"`
+	Source    enums.PointsSource `json:"source" gorm:"not null"`
+	Reason    string             `json:"reason,omitempty" validate:"max=255"`
+	CreatedAt time.Time          `json:"created_at" gorm:"index"`
+
+	// Identifiant de la participation ou de l'inscription au créneau à l'origine des points
+	Reference *string `json:"reference,omitempty" gorm:"index"`
+
+	// Foreign keys
+	UserID string `json:"user_id" gorm:"not null;index"`
+	// Auteur du mouvement, absent lorsque les points sont attribués automatiquement
+	ActorID *string `json:"actor_id,omitempty"`
+	// Une écriture ne peut être annulée qu'une fois
+	ReversalOfID *string `json:"reversal_of_id,omitempty" gorm:"uniqueIndex"`
+	// Association organisatrice et catégorie de l'événement, pour les classements
+	AssociationID *string `json:"association_id,omitempty"`
+	CategoryID    *string `json:"category_id,omitempty"`
+
+	// Relationships
+	Actor *User `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+}
+
+func (e *PointsEntry) BeforeCreate(tx *gorm.DB) (err error) {
+	e.ID = utils.GenerateULID()
+	e.CreatedAt = time.Now()
+	return nil
+}
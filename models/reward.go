@@ -0,0 +1,76 @@
+package models
+
+import (
+	"backend/enums"
+	"backend/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Reward est une récompense du catalogue, proposée par une association ou, sans association, par les
+// administrateurs
+type Reward struct {
+	ID          string `json:"id" gorm:"primaryKey"`
+	Name        string `json:"name" gorm:"not null" validate:"required,max=100" faker:"word"`
+	Description string `json:"description" validate:"max=1000" faker:"sentence"`
+	ImageURL    string `json:"image_url" faker:"url"`
+	Cost        int    `json:"cost" gorm:"not null" validate:"min=1" faker:"-"`
+	// Nombre d'exemplaires restants, illimité si absent
+	Stock     *int      `json:"stock,omitempty" validate:"omitempty,min=0" faker:"-"`
+	IsActive  bool      `json:"is_active" gorm:"not null;default:true" faker:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Foreign keys
+	AssociationID *string `json:"association_id,omitempty" gorm:"index" faker:"-"`
+
+	// Relationships
+	Association *Association `gorm:"foreignKey:AssociationID" json:"association,omitempty" validate:"-" faker:"-"`
+}
+
+func (r *Reward) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = utils.GenerateULID()
+	r.CreatedAt = time.Now()
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *Reward) BeforeUpdate(tx *gorm.DB) (err error) {
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// RewardRedemption est la demande d'une récompense par un utilisateur. Le coût est celui payé au moment
+// de la demande ; l'écriture de débit du registre de points la référence.
+type RewardRedemption struct {
+	ID         string                 `json:"id" gorm:"primaryKey"`
+	Status     enums.RedemptionStatus `json:"status" gorm:"not null;default:pending;index"`
+	Cost       int                    `json:"cost" gorm:"not null"`
+	PickupCode string                 `json:"pickup_code,omitempty" gorm:"not null;uniqueIndex"`
+	Reason     string                 `json:"reason,omitempty"`
+	HandledAt  *time.Time             `json:"handled_at,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+
+	// Foreign keys
+	RewardID    string  `json:"reward_id" gorm:"not null;index"`
+	UserID      string  `json:"user_id" gorm:"not null;index"`
+	HandledByID *string `json:"handled_by_id,omitempty"`
+
+	// Relationships
+	Reward *Reward `gorm:"foreignKey:RewardID" json:"reward,omitempty"`
+	User   *User   `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (r *RewardRedemption) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = utils.GenerateULID()
+	r.CreatedAt = time.Now()
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *RewardRedemption) BeforeUpdate(tx *gorm.DB) (err error) {
+	r.UpdatedAt = time.Now()
+	return nil
+}
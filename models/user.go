@@ -28,12 +28,16 @@ type User struct {
 	EmailVerifiedAt *time.Time `json:"email_verified_at" gorm:"index"`
 	PointsOpen      int        `json:"points_open" gorm:"default:0"`
 	FirebaseToken   string     `json:"firebase_token" validate:"omitempty"`
+	CalendarToken   string     `json:"-" gorm:"index" faker:"-"`
+	// Paramètre de confidentialité : l'utilisateur n'apparaît pas dans les classements publics
+	HideFromLeaderboards bool `json:"hide_from_leaderboards" gorm:"not null;default:false" faker:"-"`
 
 	AssociationsOwned []Association   `json:"associations_owned" gorm:"foreignKey:OwnerID" faker:"-"`
 	Memberships       []Membership    `json:"memberships" gorm:"foreignKey:UserID" faker:"-"`
 	Associations      []Association   `gorm:"many2many:memberships;joinForeignKey:UserID;joinReferences:AssociationID" json:"associations" faker:"-"`
 	Messages          []Message       `json:"messages" gorm:"foreignKey:SenderID" faker:"-"`
 	Participation     []Participation `json:"participation" gorm:"foreignKey:UserID" faker:"-"`
+	Badges            []UserBadge     `json:"badges,omitempty" gorm:"foreignKey:UserID" faker:"-"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
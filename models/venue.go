@@ -0,0 +1,45 @@
+package models
+
+import (
+	"backend/utils"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Venue est un lieu géolocalisé, partagé entre les événements d'une association
+type Venue struct {
+	ID         string    `json:"id" gorm:"primaryKey"`
+	Name       string    `json:"name" gorm:"not null" validate:"required,max=255" faker:"word"`
+	Address    string    `json:"address" validate:"max=255" faker:"sentence"`
+	PostalCode string    `json:"postal_code" validate:"max=20" faker:"-"`
+	City       string    `json:"city" validate:"max=255" faker:"word"`
+	Latitude   float64   `json:"latitude" validate:"min=-90,max=90" faker:"lat"`
+	Longitude  float64   `json:"longitude" validate:"min=-180,max=180" faker:"long"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Foreign keys
+	AssociationID string `json:"association_id" gorm:"index" faker:"-"`
+}
+
+func (v *Venue) BeforeCreate(tx *gorm.DB) (err error) {
+	v.ID = utils.GenerateULID()
+	v.CreatedAt = time.Now()
+	v.UpdatedAt = time.Now()
+	return nil
+}
+
+// FullAddress renvoie le nom du lieu suivi de son adresse postale
+func (v *Venue) FullAddress() string {
+	parts := []string{v.Name}
+	if v.Address != "" {
+		parts = append(parts, v.Address)
+	}
+	if city := strings.TrimSpace(fmt.Sprintf("%s %s", v.PostalCode, v.City)); city != "" {
+		parts = append(parts, city)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,27 @@
+package resources
+
+import (
+	"backend/models"
+	"time"
+)
+
+type BadgeResource struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ImageURL    string `json:"image_url"`
+	AwardedAt   string `json:"awarded_at"`
+}
+
+func NewBadgeResource(userBadge models.UserBadge) BadgeResource {
+	resource := BadgeResource{
+		ID:        userBadge.BadgeID,
+		AwardedAt: userBadge.AwardedAt.Format(time.RFC3339),
+	}
+	if userBadge.Badge != nil {
+		resource.Name = userBadge.Badge.Name
+		resource.Description = userBadge.Badge.Description
+		resource.ImageURL = userBadge.Badge.ImageURL
+	}
+	return resource
+}
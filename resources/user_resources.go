@@ -22,6 +22,7 @@ type UserResource struct {
 	UpdatedAt         string                `json:"updated_at"`
 	Associations      []AssociationResource `json:"associations"`
 	Memberships       []MembershipResource  `json:"memberships"`
+	Badges            []BadgeResource       `json:"badges"`
 	VerificationToken string                `json:"verification_token,omitempty"`
 	EmailVerifiedAt   *time.Time            `json:"email_verified_at"`
 	ImageURL          string                `json:"image_url"`
@@ -39,6 +40,11 @@ func NewUserResource(user models.User) UserResource {
 		memberships[i] = NewMembershipResource(membership)
 	}
 
+	badges := make([]BadgeResource, len(user.Badges))
+	for i, badge := range user.Badges {
+		badges[i] = NewBadgeResource(badge)
+	}
+
 	return UserResource{
 		ID:              user.ID,
 		Name:            user.Name,
@@ -49,6 +55,7 @@ func NewUserResource(user models.User) UserResource {
 		UpdatedAt:       user.UpdatedAt.Format(time.RFC3339),
 		Associations:    associations,
 		Memberships:     memberships,
+		Badges:          badges,
 		ImageURL:        user.ImageURL,
 		EmailVerifiedAt: user.EmailVerifiedAt,
 		FirebaseToken:   user.FirebaseToken,
@@ -12,6 +12,11 @@ type AssociationRouter struct{}
 
 func (r *AssociationRouter) SetupRoutes(e *echo.Echo) {
 	associationController := controllers.NewAssociationController()
+	eventImportController := controllers.NewEventImportController()
+	venueController := controllers.NewVenueController()
+	eventFeedbackController := controllers.NewEventFeedbackController()
+	eventCoHostController := controllers.NewEventCoHostController()
+	participantExportController := controllers.NewParticipantExportController()
 
 	group := e.Group("/associations")
 
@@ -22,6 +27,15 @@ func (r *AssociationRouter) SetupRoutes(e *echo.Echo) {
 	group.POST("/:id/upload-image", associationController.UploadProfileImage, middlewares.AuthenticationMiddleware())
 	group.GET("/:associationId/next-event", associationController.GetNextEvent, middlewares.AuthenticationMiddleware(), middlewares.AssociationMembershipMiddleware)
 	group.GET("/:associationId/events", associationController.GetAssociationEvents, middlewares.AuthenticationMiddleware(), middlewares.AssociationMembershipMiddleware)
+	group.POST("/:associationId/events/import/preview", eventImportController.PreviewImport, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole), middlewares.AssociationMembershipMiddleware)
+	group.POST("/:associationId/events/import", eventImportController.ImportEvents, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole), middlewares.AssociationMembershipMiddleware)
+	group.GET("/:associationId/venues", venueController.GetAssociationVenues, middlewares.AuthenticationMiddleware(), middlewares.AssociationMembershipMiddleware)
+	group.POST("/:associationId/venues", venueController.CreateVenue, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole), middlewares.AssociationMembershipMiddleware)
+	group.PUT("/:associationId/venues/:venueId", venueController.UpdateVenue, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole), middlewares.AssociationMembershipMiddleware)
+	group.DELETE("/:associationId/venues/:venueId", venueController.DeleteVenue, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole), middlewares.AssociationMembershipMiddleware)
+	group.GET("/:associationId/feedback", eventFeedbackController.GetAssociationFeedback, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	group.GET("/:associationId/co-host-invitations", eventCoHostController.GetPendingInvitations, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	group.GET("/:associationId/participants/export", participantExportController.ExportAssociationParticipants, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
 	group.POST("/join/:code", associationController.JoinAssociation, middlewares.AuthenticationMiddleware())
 	group.PUT("/:associationId", associationController.UpdateAssociation, middlewares.AuthenticationMiddleware(enums.AdminRole, enums.AssociationLeaderRole), middlewares.AssociationMembershipMiddleware)
 	group.GET("/:associationId/check-membership", associationController.CheckMembership, middlewares.AuthenticationMiddleware())
@@ -0,0 +1,23 @@
+package routers
+
+import (
+	"backend/controllers"
+	"backend/enums"
+	"backend/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+type BadgeRouter struct{}
+
+func (r *BadgeRouter) SetupRoutes(e *echo.Echo) {
+	badgeController := controllers.NewBadgeController()
+
+	group := e.Group("/badges")
+	group.GET("", badgeController.GetBadges, middlewares.AuthenticationMiddleware())
+	group.POST("", badgeController.CreateBadge, middlewares.AuthenticationMiddleware(enums.AdminRole))
+	group.PUT("/:id", badgeController.UpdateBadge, middlewares.AuthenticationMiddleware(enums.AdminRole))
+	group.DELETE("/:id", badgeController.DeleteBadge, middlewares.AuthenticationMiddleware(enums.AdminRole))
+
+	e.GET("/me/badges", badgeController.GetMyBadges, middlewares.AuthenticationMiddleware())
+}
@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"backend/controllers"
+	"backend/enums"
+	"backend/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CalendarRouter struct{}
+
+func (r *CalendarRouter) SetupRoutes(e *echo.Echo) {
+	calendarController := controllers.NewCalendarController()
+
+	group := e.Group("/calendars")
+
+	// Flux publics : le jeton de l'URL fait office d'authentification pour les applications de calendrier
+	group.GET("/feeds/users/:token/events.ics", calendarController.GetUserFeed)
+	group.GET("/feeds/associations/:token/events.ics", calendarController.GetAssociationFeed)
+
+	group.GET("/me", calendarController.GetUserCalendar, middlewares.AuthenticationMiddleware())
+	group.POST("/me/token", calendarController.RegenerateUserCalendarToken, middlewares.AuthenticationMiddleware())
+	group.GET("/associations/:associationId", calendarController.GetAssociationCalendar, middlewares.AuthenticationMiddleware(), middlewares.AssociationMembershipMiddleware)
+	group.POST("/associations/:associationId/token", calendarController.RegenerateAssociationCalendarToken, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole), middlewares.AssociationMembershipMiddleware)
+}
@@ -12,16 +12,74 @@ type EventRouter struct{}
 
 func (r EventRouter) SetupRoutes(e *echo.Echo) {
 	eventController := controllers.NewEventController()
+	eventFormController := controllers.NewEventFormController()
+	eventInvitationController := controllers.NewEventInvitationController()
+	eventFeedbackController := controllers.NewEventFeedbackController()
+	eventPhotoController := controllers.NewEventPhotoController()
+	eventCoHostController := controllers.NewEventCoHostController()
+	eventCommentController := controllers.NewEventCommentController()
+	participantExportController := controllers.NewParticipantExportController()
+	eventShiftController := controllers.NewEventShiftController()
+	eventTemplateController := controllers.NewEventTemplateController()
 	api := e.Group("/events")
 
 	api.GET("/:id/participation", eventController.GetUserEventParticipation, middlewares.AuthenticationMiddleware(enums.AdminRole, enums.AssociationLeaderRole, enums.UserRole))
 
 	api.GET("/:id/participations", eventController.GetEventParticipations, middlewares.AuthenticationMiddleware(enums.AdminRole, enums.AssociationLeaderRole, enums.UserRole))
 	api.POST("", eventController.CreateEvent, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
-	api.GET("", eventController.GetEvents, middlewares.AuthenticationMiddleware(enums.AdminRole))
-	api.GET("/:id", eventController.GetEventById, middlewares.AuthenticationMiddleware())
+	api.GET("", eventController.GetEvents, middlewares.OptionalAuthenticationMiddleware)
+	api.GET("/nearby", eventController.GetNearbyEvents, middlewares.OptionalAuthenticationMiddleware)
+	api.GET("/:id", eventController.GetEventById, middlewares.OptionalAuthenticationMiddleware)
 	api.PUT("/:id", eventController.UpdateEvent, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
 	api.DELETE("/:id", eventController.DeleteEvent, middlewares.AuthenticationMiddleware(enums.AdminRole, enums.AssociationLeaderRole))
+	api.POST("/:id/cancel", eventController.CancelEvent, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/reschedule", eventController.RescheduleEvent, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/publish", eventController.PublishEvent, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/draft", eventController.RevertToDraft, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
 	api.POST("/:id/user-event-participation", eventController.ChangeAttend, middlewares.AuthenticationMiddleware())
+	api.GET("/:id/check-in/qr-code", eventController.GetCheckInQRCode, middlewares.AuthenticationMiddleware())
+	api.POST("/:id/check-in", eventController.CheckIn, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/attendance", eventController.BulkAttendance, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.GET("/:id/questions", eventFormController.GetEventQuestions, middlewares.AuthenticationMiddleware())
+	api.PUT("/:id/questions", eventFormController.UpdateEventQuestions, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.GET("/:id/answers/export", eventFormController.ExportEventAnswers, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.GET("/:id/participants/export", participantExportController.ExportEventParticipants, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.GET("/:id/invitations", eventInvitationController.GetEventInvitations, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/invitations", eventInvitationController.InviteUsers, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.DELETE("/:id/invitations/:userId", eventInvitationController.RevokeInvitation, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/feedback", eventFeedbackController.SubmitFeedback, middlewares.AuthenticationMiddleware())
+	api.GET("/:id/feedback/me", eventFeedbackController.GetUserFeedback, middlewares.AuthenticationMiddleware())
+	api.GET("/:id/feedback", eventFeedbackController.GetEventFeedback, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.GET("/:id/photos", eventPhotoController.GetEventPhotos, middlewares.OptionalAuthenticationMiddleware)
+	api.GET("/:id/photos/:photoId/file", eventPhotoController.GetPhotoFile, middlewares.OptionalAuthenticationMiddleware)
+	api.POST("/:id/photos", eventPhotoController.UploadPhoto, middlewares.AuthenticationMiddleware())
+	api.PUT("/:id/photos/:photoId/hidden", eventPhotoController.SetPhotoHidden, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.DELETE("/:id/photos/:photoId", eventPhotoController.DeletePhoto, middlewares.AuthenticationMiddleware())
+	api.PUT("/:id/cover-photo", eventPhotoController.SetCoverPhoto, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.GET("/:id/co-hosts", eventCoHostController.GetCoHosts, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/co-hosts", eventCoHostController.InviteCoHost, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.DELETE("/:id/co-hosts/:associationId", eventCoHostController.RemoveCoHost, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/co-hosts/:associationId/accept", eventCoHostController.AcceptInvitation, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/co-hosts/:associationId/decline", eventCoHostController.DeclineInvitation, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+
+	api.GET("/:id/comments", eventCommentController.GetComments, middlewares.OptionalAuthenticationMiddleware)
+	api.POST("/:id/comments", eventCommentController.AddComment, middlewares.AuthenticationMiddleware())
+	api.PUT("/:id/comments/:commentId/answered", eventCommentController.SetAnswered, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.DELETE("/:id/comments/:commentId", eventCommentController.DeleteComment, middlewares.AuthenticationMiddleware())
+	api.GET("/:id/shifts", eventShiftController.GetShifts, middlewares.OptionalAuthenticationMiddleware)
+	api.GET("/:id/shifts/roster", eventShiftController.GetRoster, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/shifts", eventShiftController.CreateShift, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.PUT("/:id/shifts/:shiftId", eventShiftController.UpdateShift, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.DELETE("/:id/shifts/:shiftId", eventShiftController.DeleteShift, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/shifts/:shiftId/signup", eventShiftController.SignUp, middlewares.AuthenticationMiddleware())
+	api.DELETE("/:id/shifts/:shiftId/signup", eventShiftController.Withdraw, middlewares.AuthenticationMiddleware())
+	api.POST("/:id/shifts/:shiftId/signups/:userId/complete", eventShiftController.CompleteSignup, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+
+	api.GET("/templates", eventTemplateController.GetTemplates, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/templates/:templateId/events", eventTemplateController.CreateEventFromTemplate, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.DELETE("/templates/:templateId", eventTemplateController.DeleteTemplate, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/template", eventTemplateController.SaveAsTemplate, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	api.POST("/:id/duplicate", eventTemplateController.DuplicateEvent, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+
 	api.GET("/:id/is-attended", eventController.IsAttended, middlewares.AuthenticationMiddleware())
 }
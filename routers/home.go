@@ -10,10 +10,18 @@ type HelloRouter struct{}
 
 func (r *HelloRouter) SetupRoutes(e *echo.Echo) {
 	homeController := controllers.NewHomeController()
+	recommendationController := controllers.NewRecommendationController()
+	pointsController := controllers.NewPointsController()
+	leaderboardController := controllers.NewLeaderboardController()
+	userController := controllers.NewUserController()
 
 	e.GET("/", homeController.Hello)
 	e.GET("/admin/ping", homeController.HelloAdmin)
 	e.GET("/statistics", homeController.GetStatistics, middlewares.AuthenticationMiddleware())
 	e.GET("/top-associations", homeController.GetTopAssociations, middlewares.AuthenticationMiddleware())
 	e.GET("/me", homeController.GetMe, middlewares.AuthenticationMiddleware())
+	e.GET("/me/recommendations", recommendationController.GetRecommendations, middlewares.AuthenticationMiddleware())
+	e.GET("/me/points/history", pointsController.GetMyHistory, middlewares.AuthenticationMiddleware())
+	e.PUT("/me/privacy", userController.UpdatePrivacySettings, middlewares.AuthenticationMiddleware())
+	e.GET("/leaderboards", leaderboardController.GetLeaderboard, middlewares.AuthenticationMiddleware())
 }
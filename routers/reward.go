@@ -0,0 +1,28 @@
+package routers
+
+import (
+	"backend/controllers"
+	"backend/enums"
+	"backend/middlewares"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RewardRouter struct{}
+
+func (r *RewardRouter) SetupRoutes(e *echo.Echo) {
+	rewardController := controllers.NewRewardController()
+
+	group := e.Group("/rewards")
+	group.GET("", rewardController.GetRewards, middlewares.AuthenticationMiddleware())
+	group.POST("", rewardController.CreateReward, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	group.PUT("/:id", rewardController.UpdateReward, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	group.DELETE("/:id", rewardController.DeleteReward, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	group.POST("/:id/redeem", rewardController.Redeem, middlewares.AuthenticationMiddleware())
+	group.GET("/:id/redemptions", rewardController.GetRewardRedemptions, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	group.POST("/redemptions/fulfil", rewardController.FulfilRedemption, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	group.POST("/redemptions/:redemptionId/reject", rewardController.RejectRedemption, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole, enums.AdminRole))
+	group.POST("/redemptions/:redemptionId/cancel", rewardController.CancelRedemption, middlewares.AuthenticationMiddleware())
+
+	e.GET("/me/redemptions", rewardController.GetMyRedemptions, middlewares.AuthenticationMiddleware())
+}
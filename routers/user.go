@@ -13,6 +13,7 @@ type UserRouter struct{}
 
 func (r *UserRouter) SetupRoutes(e *echo.Echo) {
 	userController := controllers.NewUserController()
+	pointsController := controllers.NewPointsController()
 
 	group := e.Group("/users")
 	group.POST("", userController.CreateUser, middlewares.AuthenticationMiddleware(enums.AdminRole))
@@ -29,4 +30,8 @@ func (r *UserRouter) SetupRoutes(e *echo.Echo) {
 
 	group.POST("/participations/:id/confirm", userController.ConfirmParticipation, middlewares.AuthenticationMiddleware(enums.AssociationLeaderRole))
 
+	group.GET("/:id/points/history", pointsController.GetUserHistory, middlewares.AuthenticationMiddleware(enums.AdminRole))
+	group.POST("/:id/points", pointsController.AdjustPoints, middlewares.AuthenticationMiddleware(enums.AdminRole))
+	group.POST("/points/:entryId/reverse", pointsController.ReverseEntry, middlewares.AuthenticationMiddleware(enums.AdminRole))
+
 }
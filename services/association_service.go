@@ -14,7 +14,8 @@ import (
 )
 
 type AssociationService struct {
-	db *gorm.DB
+	db           *gorm.DB
+	badgeService *BadgeService
 }
 
 func NewAssociationService() *AssociationService {
@@ -22,7 +23,8 @@ func NewAssociationService() *AssociationService {
 		return nil
 	}
 	return &AssociationService{
-		db: database.CurrentDatabase,
+		db:           database.CurrentDatabase,
+		badgeService: NewBadgeService(),
 	}
 }
 
@@ -118,40 +120,47 @@ func (s *AssociationService) GetAllAssociations(pagination utils.Pagination, fil
 	return &pagination, nil
 }
 
-func (s *AssociationService) GetNextEvent(groupID string) (*models.Event, error) {
-	var event models.Event
+func (s *AssociationService) GetNextEvent(groupID string, viewer *models.User) (*models.Event, error) {
+	events, err := s.getUpcomingEvents(groupID, viewer)
+	if err != nil {
+		return nil, err
+	}
 
-	err := database.CurrentDatabase.
-		Preload("Participations").
-		Where("association_id = ?", groupID).
-		Where("date >= ?", time.Now().Format(models.DateFormat)).
-		Order("date").
-		First(&event).Error
+	if len(events) == 0 {
+		return nil, nil
+	}
+	return &events[0], nil
+}
 
+func (s *AssociationService) GetAssociationEvents(groupID string, viewer *models.User, pagination utils.Pagination) (*utils.Pagination, error) {
+	events, err := s.getUpcomingEvents(groupID, viewer)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
 		return nil, err
 	}
-	return &event, nil
+
+	pagination.Rows = utils.PaginateSlice(events, &pagination)
+
+	return &pagination, nil
 }
 
-func (s *AssociationService) GetAssociationEvents(groupID string, pagination utils.Pagination) (*utils.Pagination, error) {
+// getUpcomingEvents renvoie les événements à venir ou en cours de l'association visibles par viewer, séries récurrentes dépliées
+func (s *AssociationService) getUpcomingEvents(groupID string, viewer *models.User) ([]models.Event, error) {
 	var events []models.Event
+	from := utils.StartOfDay(time.Now())
 
-	query := database.CurrentDatabase.
+	err := database.CurrentDatabase.
 		Preload("Participations").
-		Where("association_id = ?", groupID).
-		Where("date >= ?", time.Now().Format(models.DateFormat)).
-		Order("date")
-
-	query.Scopes(utils.Paginate(events, &pagination, query)).
-		Find(&events)
-
-	pagination.Rows = events
+		Preload("RecurrenceExceptions").
+		Scopes(VisibleEventsScope(viewer), HostedByScope(groupID)).
+		Where("recurrence_parent_id IS NULL").
+		Where("("+eventEndsAtSQL+" >= ? OR recurrence_rule <> '')", from).
+		Order("date").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
 
-	return &pagination, nil
+	return NewEventService().ExpandOccurrences(events, from, from.Add(RecurrenceHorizon))
 }
 
 func (s *AssociationService) JoinAssociationByCode(userID string, code string) (*models.Association, error) {
@@ -180,6 +189,7 @@ func (s *AssociationService) JoinAssociationByCode(userID string, code string) (
 	if err != nil {
 		return nil, err
 	}
+	s.badgeService.Refresh(userID, enums.JoinedAssociationsCriterion)
 
 	return &association, nil
 }
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BadgeService struct {
+	notificationService *NotificationService
+}
+
+func NewBadgeService() *BadgeService {
+	return &BadgeService{
+		notificationService: NewNotificationService(),
+	}
+}
+
+// GetBadges renvoie les définitions de badges par ordre alphabétique
+func (s *BadgeService) GetBadges() ([]models.Badge, error) {
+	var badges []models.Badge
+	err := database.CurrentDatabase.Preload("Category").Order("name").Find(&badges).Error
+	return badges, err
+}
+
+// GetBadge renvoie une définition de badge
+func (s *BadgeService) GetBadge(id string) (*models.Badge, error) {
+	var badge models.Badge
+	err := database.CurrentDatabase.Preload("Category").First(&badge, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, coreErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &badge, nil
+}
+
+// GetUserBadges renvoie les badges obtenus par l'utilisateur, du plus récent au plus ancien
+func (s *BadgeService) GetUserBadges(userID string) ([]models.UserBadge, error) {
+	var badges []models.UserBadge
+	err := database.CurrentDatabase.Preload("Badge").Where("user_id = ?", userID).Order("awarded_at DESC").Find(&badges).Error
+	return badges, err
+}
+
+// CreateBadge enregistre une définition de badge et l'attribue aux utilisateurs qui remplissent déjà sa règle
+func (s *BadgeService) CreateBadge(badge *models.Badge) error {
+	if err := validateBadge(badge); err != nil {
+		return err
+	}
+	if err := database.CurrentDatabase.Create(badge).Error; err != nil {
+		return err
+	}
+	return s.awardQualifyingUsers(badge)
+}
+
+// UpdateBadge modifie la définition du badge. Les badges déjà attribués restent acquis ; les utilisateurs
+// qui remplissent la nouvelle règle l'obtiennent.
+func (s *BadgeService) UpdateBadge(badge *models.Badge, changes *models.Badge) error {
+	badge.Name = changes.Name
+	badge.Description = changes.Description
+	badge.ImageURL = changes.ImageURL
+	badge.Criterion = changes.Criterion
+	badge.Threshold = changes.Threshold
+	badge.CategoryID = changes.CategoryID
+	badge.Category = nil
+
+	if err := validateBadge(badge); err != nil {
+		return err
+	}
+	err := database.CurrentDatabase.Model(badge).Select("name", "description", "image_url", "criterion", "threshold", "category_id").Updates(badge).Error
+	if err != nil {
+		return err
+	}
+	return s.awardQualifyingUsers(badge)
+}
+
+// DeleteBadge supprime la définition du badge et le retire aux utilisateurs qui l'avaient obtenu
+func (s *BadgeService) DeleteBadge(badge *models.Badge) error {
+	return database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("badge_id = ?", badge.ID).Delete(&models.UserBadge{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(badge).Error
+	})
+}
+
+// Evaluate attribue à l'utilisateur les badges des critères donnés dont il atteint désormais le seuil, et
+// l'en prévient. Renvoie les badges attribués.
+func (s *BadgeService) Evaluate(userID string, criteria ...enums.BadgeCriterion) ([]models.Badge, error) {
+	var badges []models.Badge
+	err := database.CurrentDatabase.
+		Where("criterion IN ?", criteria).
+		Where("id NOT IN (SELECT badge_id FROM user_badges WHERE user_id = ?)", userID).
+		Find(&badges).Error
+	if err != nil || len(badges) == 0 {
+		return nil, err
+	}
+
+	// Plusieurs badges partagent souvent la même règle avec des seuils différents
+	counts := map[string]int64{}
+	var awarded []models.Badge
+	for _, badge := range badges {
+		key := string(badge.Criterion)
+		if badge.CategoryID != nil {
+			key += ":" + *badge.CategoryID
+		}
+		count, ok := counts[key]
+		if !ok {
+			if err := activityQuery(&badge).Where(criterionUserColumn(badge.Criterion)+" = ?", userID).Count(&count).Error; err != nil {
+				return awarded, err
+			}
+			counts[key] = count
+		}
+		if count < int64(badge.Threshold) {
+			continue
+		}
+
+		created, err := awardBadge(userID, badge.ID)
+		if err != nil {
+			return awarded, err
+		}
+		if created {
+			awarded = append(awarded, badge)
+		}
+	}
+
+	if len(awarded) > 0 {
+		var user models.User
+		if err := database.CurrentDatabase.First(&user, "id = ?", userID).Error; err != nil {
+			return awarded, err
+		}
+		for i := range awarded {
+			badge := awarded[i]
+			go s.notificationService.NotifyBadgeAwarded(&user, &badge)
+		}
+	}
+	return awarded, nil
+}
+
+// Refresh évalue les badges de l'utilisateur après une action qui a pu les lui faire gagner. Les erreurs sont
+// journalisées sans faire échouer l'action, déjà enregistrée.
+func (s *BadgeService) Refresh(userID string, criteria ...enums.BadgeCriterion) {
+	if _, err := s.Evaluate(userID, criteria...); err != nil {
+		fmt.Printf("Erreur lors de l'évaluation des badges de %s: %v\n", userID, err)
+	}
+}
+
+// awardQualifyingUsers attribue le badge à tous les utilisateurs qui remplissent déjà sa règle
+func (s *BadgeService) awardQualifyingUsers(badge *models.Badge) error {
+	var userIDs []string
+	userColumn := criterionUserColumn(badge.Criterion)
+	err := activityQuery(badge).
+		Where(userColumn+" NOT IN (SELECT user_id FROM user_badges WHERE badge_id = ?)", badge.ID).
+		Group(userColumn).
+		Having("COUNT(*) >= ?", badge.Threshold).
+		Pluck(userColumn, &userIDs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		created, err := awardBadge(userID, badge.ID)
+		if err != nil {
+			return err
+		}
+		if !created {
+			continue
+		}
+
+		var user models.User
+		if err := database.CurrentDatabase.First(&user, "id = ?", userID).Error; err != nil {
+			continue
+		}
+		awarded := *badge
+		go s.notificationService.NotifyBadgeAwarded(&user, &awarded)
+	}
+	return nil
+}
+
+// activityQuery renvoie la requête listant les activités comptées par la règle du badge, à filtrer par
+// la colonne criterionUserColumn
+func activityQuery(badge *models.Badge) *gorm.DB {
+	db := database.CurrentDatabase
+	switch badge.Criterion {
+	case enums.AttendedEventsCriterion:
+		query := db.Model(&models.Participation{}).
+			Where("participations.status = ? OR participations.attendance = ?", enums.ParticipationConfirmed, enums.Present)
+		if badge.CategoryID != nil {
+			query = query.Joins("JOIN events ON events.id = participations.event_id").
+				Where("events.category_id = ?", *badge.CategoryID)
+		}
+		return query
+	case enums.JoinedAssociationsCriterion:
+		return db.Model(&models.Membership{}).Where("memberships.status = ?", enums.Accepted)
+	case enums.OrganisedEventsCriterion:
+		// Une série compte pour un seul événement
+		return db.Model(&models.Event{}).
+			Joins("JOIN associations ON associations.id = events.association_id").
+			Where("events.publication_status = ? AND events.recurrence_parent_id IS NULL", enums.PublishedPublication)
+	case enums.CompletedShiftsCriterion:
+		return db.Model(&models.ShiftSignup{}).Where("shift_signups.completed_at IS NOT NULL")
+	}
+	return db.Model(&models.Participation{}).Where("1 = 0")
+}
+
+// criterionUserColumn est la colonne désignant l'utilisateur dans la requête du critère
+func criterionUserColumn(criterion enums.BadgeCriterion) string {
+	switch criterion {
+	case enums.JoinedAssociationsCriterion:
+		return "memberships.user_id"
+	case enums.OrganisedEventsCriterion:
+		return "associations.owner_id"
+	case enums.CompletedShiftsCriterion:
+		return "shift_signups.user_id"
+	}
+	return "participations.user_id"
+}
+
+// awardBadge enregistre le badge de l'utilisateur ; renvoie false s'il l'avait déjà, par exemple lorsque
+// deux évaluations concurrentes l'attribuent en même temps
+func awardBadge(userID string, badgeID string) (bool, error) {
+	result := database.CurrentDatabase.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.UserBadge{
+		UserID:  userID,
+		BadgeID: badgeID,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+func validateBadge(badge *models.Badge) error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(badge); err != nil {
+		return err
+	}
+
+	if badge.CategoryID != nil {
+		var count int64
+		if err := database.CurrentDatabase.Model(&models.Category{}).Where("id = ?", *badge.CategoryID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return coreErrors.ErrInvalidBadgeCategory
+		}
+	}
+	return nil
+}
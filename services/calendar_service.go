@@ -0,0 +1,229 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	"backend/models"
+	"backend/utils"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+// calendarHistory est la période passée conservée dans les flux, pour que les calendriers
+// abonnés gardent l'historique récent
+const calendarHistory = 30 * 24 * time.Hour
+
+type CalendarService struct {
+	userService *UserService
+}
+
+func NewCalendarService() *CalendarService {
+	return &CalendarService{
+		userService: NewUserService(),
+	}
+}
+
+func (s *CalendarService) GetUserByCalendarToken(token string) (*models.User, error) {
+	var user models.User
+	if err := database.CurrentDatabase.Where("calendar_token = ? AND calendar_token <> ''", token).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *CalendarService) GetAssociationByCalendarToken(token string) (*models.Association, error) {
+	var association models.Association
+	if err := database.CurrentDatabase.Where("calendar_token = ? AND calendar_token <> ''", token).First(&association).Error; err != nil {
+		return nil, err
+	}
+	return &association, nil
+}
+
+// GetUserCalendarToken renvoie le jeton du flux de l'utilisateur, en le créant au premier appel
+func (s *CalendarService) GetUserCalendarToken(user *models.User) (string, error) {
+	if user.CalendarToken != "" {
+		return user.CalendarToken, nil
+	}
+	return s.RegenerateUserCalendarToken(user)
+}
+
+// RegenerateUserCalendarToken remplace le jeton du flux : l'ancienne URL cesse immédiatement de fonctionner
+func (s *CalendarService) RegenerateUserCalendarToken(user *models.User) (string, error) {
+	token := utils.GenerateSecureToken()
+	if err := database.CurrentDatabase.Model(&models.User{}).Where("id = ?", user.ID).Update("calendar_token", token).Error; err != nil {
+		return "", err
+	}
+	user.CalendarToken = token
+	return token, nil
+}
+
+// GetAssociationCalendarToken renvoie le jeton du flux de l'association, en le créant au premier appel
+func (s *CalendarService) GetAssociationCalendarToken(association *models.Association) (string, error) {
+	if association.CalendarToken != "" {
+		return association.CalendarToken, nil
+	}
+	return s.RegenerateAssociationCalendarToken(association)
+}
+
+func (s *CalendarService) RegenerateAssociationCalendarToken(association *models.Association) (string, error) {
+	token := utils.GenerateSecureToken()
+	if err := database.CurrentDatabase.Model(&models.Association{}).Where("id = ?", association.ID).Update("calendar_token", token).Error; err != nil {
+		return "", err
+	}
+	association.CalendarToken = token
+	return token, nil
+}
+
+// BuildUserFeed construit le flux des événements auxquels l'utilisateur participe
+func (s *CalendarService) BuildUserFeed(user *models.User) (*ics.Calendar, error) {
+	from := time.Now().Add(-calendarHistory)
+
+	events, err := s.userService.FindParticipatedEvents(user.ID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := newCalendar(user.Name, from)
+	for i := range events {
+		event := &events[i]
+
+		var participation *models.Participation
+		for j := range event.Participations {
+			if event.Participations[j].UserID == user.ID {
+				participation = &event.Participations[j]
+			}
+		}
+
+		// Une occurrence matérialisée est publiée comme un événement à part entière :
+		// le flux de l'utilisateur ne contient pas forcément la série d'origine
+		vevent := addCalendarEvent(calendar, event.ID, event)
+		vevent.SetStatus(calendarEventStatus(event, participation))
+	}
+
+	return calendar, nil
+}
+
+// BuildAssociationFeed construit le flux des événements à venir de l'association. Les séries sont
+// publiées avec leur RRULE et leurs exceptions, les occurrences modifiées comme surcharges (RECURRENCE-ID).
+// Le flux étant accessible par simple lien, les événements sur invitation n'y figurent pas.
+func (s *CalendarService) BuildAssociationFeed(association *models.Association) (*ics.Calendar, error) {
+	from := utils.StartOfDay(time.Now())
+
+	var events []models.Event
+	err := database.CurrentDatabase.
+		Preload("Venue").
+		Preload("RecurrenceExceptions").
+		Scopes(HostedByScope(association.ID)).
+		Where("visibility <> ? AND publication_status = ?", enums.InviteOnlyVisibility, enums.PublishedPublication).
+		Where("recurrence_parent_id IS NULL").
+		Where("("+eventEndsAtSQL+" >= ? OR recurrence_rule <> '')", from).
+		Order("date").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var seriesIDs []string
+	for _, event := range events {
+		if event.IsRecurring() {
+			seriesIDs = append(seriesIDs, event.ID)
+		}
+	}
+
+	var instances []models.Event
+	if len(seriesIDs) > 0 {
+		if err := database.CurrentDatabase.Preload("Venue").Where("recurrence_parent_id IN ?", seriesIDs).Find(&instances).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	calendar := newCalendar(association.Name, from)
+	for i := range events {
+		vevent := addCalendarEvent(calendar, events[i].ID, &events[i])
+		vevent.SetStatus(calendarEventStatus(&events[i], nil))
+	}
+	for i := range instances {
+		vevent := addCalendarEvent(calendar, *instances[i].RecurrenceParentID, &instances[i])
+		utils.SetICalDateTime(&vevent.ComponentBase, ics.ComponentPropertyRecurrenceId, *instances[i].RecurrenceID, instances[i].TimeLocation(utils.CalendarLocation()))
+		vevent.SetStatus(calendarEventStatus(&instances[i], nil))
+	}
+
+	return calendar, nil
+}
+
+// newCalendar crée un calendrier nommé avec la définition du fuseau horaire couvrant la période publiée
+func newCalendar(name string, from time.Time) *ics.Calendar {
+	calendar := ics.NewCalendarFor("challenge-s4")
+	calendar.SetMethod(ics.MethodPublish)
+	calendar.SetName(name)
+	calendar.SetXWRCalName(name)
+	calendar.SetXWRTimezone(utils.CalendarLocation().String())
+	calendar.SetRefreshInterval("PT1H")
+	calendar.SetXPublishedTTL("PT1H")
+
+	utils.AddVTimezone(calendar, utils.CalendarLocation(), from, time.Now().Add(RecurrenceHorizon))
+	return calendar
+}
+
+// addCalendarEvent publie l'événement dans son propre fuseau horaire, pour que les règles de récurrence
+// suivent ses changements d'heure
+func addCalendarEvent(calendar *ics.Calendar, uidSource string, event *models.Event) *ics.VEvent {
+	location := event.TimeLocation(utils.CalendarLocation())
+	addMissingVTimezone(calendar, location)
+
+	vevent := calendar.AddEvent(utils.ICalUID(uidSource))
+	vevent.SetDtStampTime(time.Now())
+	vevent.SetCreatedTime(event.CreatedAt)
+	vevent.SetModifiedAt(event.UpdatedAt)
+	vevent.SetSummary(event.Name)
+	vevent.SetDescription(event.Description)
+	vevent.SetLocation(event.Location)
+	if event.Venue != nil {
+		vevent.SetLocation(event.Venue.FullAddress())
+		vevent.SetGeo(event.Venue.Latitude, event.Venue.Longitude)
+	}
+	utils.SetICalDateTime(&vevent.ComponentBase, ics.ComponentPropertyDtStart, event.Date, location)
+	utils.SetICalDateTime(&vevent.ComponentBase, ics.ComponentPropertyDtEnd, event.EndsAt(), location)
+
+	if event.IsRecurring() {
+		vevent.AddRrule(event.RecurrenceRule)
+		for _, exdate := range event.ExceptionDates() {
+			utils.AddICalDateTime(&vevent.ComponentBase, ics.ComponentPropertyExdate, exdate, location)
+		}
+	}
+
+	return vevent
+}
+
+// addMissingVTimezone ajoute la définition du fuseau horaire si le calendrier ne la contient pas encore
+func addMissingVTimezone(calendar *ics.Calendar, location *time.Location) {
+	for _, timezone := range calendar.Timezones() {
+		if property := timezone.GetProperty(ics.ComponentPropertyTzid); property != nil && property.Value == location.String() {
+			return
+		}
+	}
+	utils.AddVTimezone(calendar, location, time.Now().Add(-calendarHistory), time.Now().Add(RecurrenceHorizon))
+}
+
+// calendarEventStatus renvoie le statut iCalendar de l'événement : un événement annulé ou une participation
+// refusée apparaît comme annulé pour que le calendrier abonné le retire, un événement reporté ou une place
+// en liste d'attente comme provisoire
+func calendarEventStatus(event *models.Event, participation *models.Participation) ics.ObjectStatus {
+	switch event.Status {
+	case enums.EventCancelled:
+		return ics.ObjectStatusCancelled
+	case enums.EventPostponed:
+		return ics.ObjectStatusTentative
+	}
+
+	if participation != nil {
+		switch participation.Status {
+		case enums.ParticipationDeclined:
+			return ics.ObjectStatusCancelled
+		case enums.ParticipationWaitlisted:
+			return ics.ObjectStatusTentative
+		}
+	}
+	return ics.ObjectStatusConfirmed
+}
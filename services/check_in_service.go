@@ -0,0 +1,454 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	checkInTokenPurpose = "check_in"
+	// CheckInOpensBefore est le délai avant le début de l'événement à partir duquel les QR codes sont acceptés
+	CheckInOpensBefore = 2 * time.Hour
+	// checkInTokenValidity prolonge la validité du QR code au-delà de la fin de l'événement
+	checkInTokenValidity = 24 * time.Hour
+	checkInQRCodeSize    = 512
+	// MaxBulkAttendance limite le nombre de participations désignées dans un même lot
+	MaxBulkAttendance = 500
+)
+
+// BulkAttendanceInput désigne les participations d'un lot : la liste participation_ids, ou toutes celles
+// encore à traiter pour l'action avec all_pending
+type BulkAttendanceInput struct {
+	Action           enums.AttendanceAction `json:"action"`
+	ParticipationIDs []string               `json:"participation_ids"`
+	AllPending       bool                   `json:"all_pending"`
+}
+
+// AttendanceResult est le résultat du traitement d'une participation d'un lot ; Err explique un refus
+type AttendanceResult struct {
+	ParticipationID string
+	UserID          string
+	Err             error
+}
+
+type CheckInService struct {
+	badgeService        *BadgeService
+	notificationService *NotificationService
+}
+
+func NewCheckInService() *CheckInService {
+	return &CheckInService{
+		badgeService:        NewBadgeService(),
+		notificationService: NewNotificationService(),
+	}
+}
+
+// GenerateCheckInToken signe le jeton encodé dans le QR code du participant. Il ne contient pas
+// de claim "id" et ne peut donc pas servir de jeton d'authentification.
+func (s *CheckInService) GenerateCheckInToken(participation *models.Participation, event *models.Event) (string, error) {
+	jwtSecret, ok := os.LookupEnv("JWT_KEY")
+	if !ok {
+		return "", coreErrors.ErrInternal
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
+		jwt.MapClaims{
+			"sub":      participation.ID,
+			"event_id": participation.EventID,
+			"purpose":  checkInTokenPurpose,
+			"exp":      event.EndsAt().Add(checkInTokenValidity).Unix(),
+			"iat":      time.Now().Unix(),
+		},
+	)
+
+	return token.SignedString([]byte(jwtSecret))
+}
+
+// GenerateCheckInQRCode renvoie le QR code PNG du participant
+func (s *CheckInService) GenerateCheckInQRCode(participation *models.Participation, event *models.Event) ([]byte, error) {
+	token, err := s.GenerateCheckInToken(participation, event)
+	if err != nil {
+		return nil, err
+	}
+	return qrcode.Encode(token, qrcode.Medium, checkInQRCodeSize)
+}
+
+// ParseCheckInToken vérifie la signature du jeton et renvoie l'identifiant de la participation
+func (s *CheckInService) ParseCheckInToken(tokenString string) (string, error) {
+	jwtSecret, ok := os.LookupEnv("JWT_KEY")
+	if !ok {
+		return "", coreErrors.ErrInternal
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(jwtSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return "", coreErrors.ErrInvalidCheckInToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != checkInTokenPurpose {
+		return "", coreErrors.ErrInvalidCheckInToken
+	}
+
+	participationID, ok := claims["sub"].(string)
+	if !ok || participationID == "" {
+		return "", coreErrors.ErrInvalidCheckInToken
+	}
+	return participationID, nil
+}
+
+// GetCheckInParticipation renvoie la participation désignée par le jeton, avec son événement et son utilisateur
+func (s *CheckInService) GetCheckInParticipation(tokenString string) (*models.Participation, error) {
+	participationID, err := s.ParseCheckInToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	var participation models.Participation
+	err = database.CurrentDatabase.
+		Preload("Event.Association").
+		Preload("Event.Category").
+		Preload("User").
+		First(&participation, "id = ?", participationID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Participation supprimée depuis la génération du QR code (désinscription)
+		return nil, coreErrors.ErrNotRegistered
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &participation, nil
+}
+
+// CheckIn marque le participant présent et lui attribue les points de la catégorie de l'événement.
+// Les points ne sont pas attribués une seconde fois si la participation avait déjà été confirmée.
+// actor est le responsable ayant scanné le QR code.
+func (s *CheckInService) CheckIn(participation *models.Participation, actor *models.User, now time.Time) error {
+	if participation.Event == nil {
+		return coreErrors.ErrNotFound
+	}
+	if !canCheckIn(participation) {
+		return coreErrors.ErrNotRegistered
+	}
+	if participation.Event.IsCancelled() {
+		return coreErrors.ErrEventCancelled
+	}
+	if now.Before(participation.Event.Date.Add(-CheckInOpensBefore)) {
+		return coreErrors.ErrCheckInNotOpen
+	}
+
+	var granted *models.PointsEntry
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		var current models.Participation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, "id = ?", participation.ID).Error; err != nil {
+			return err
+		}
+		if current.CheckedInAt != nil {
+			participation.CheckedInAt = current.CheckedInAt
+			return coreErrors.ErrAlreadyCheckedIn
+		}
+		// La participation a pu être refusée ou passer en liste d'attente depuis la génération du QR code
+		if !canCheckIn(&current) {
+			return coreErrors.ErrNotRegistered
+		}
+
+		if err := tx.Model(&models.Participation{}).Where("id = ?", participation.ID).Updates(map[string]interface{}{
+			"checked_in_at": now,
+			"attendance":    enums.Present,
+			"status":        enums.ParticipationConfirmed,
+			"is_attending":  true,
+		}).Error; err != nil {
+			return err
+		}
+
+		if current.Status != enums.ParticipationConfirmed {
+			granted = participationPoints(participation, actor)
+			if err := recordPoints(tx, granted); err != nil {
+				return err
+			}
+		}
+
+		participation.CheckedInAt = &now
+		participation.Attendance = enums.Present
+		participation.Status = enums.ParticipationConfirmed
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	publishPoints(granted, nil)
+	s.badgeService.Refresh(participation.UserID, enums.AttendedEventsCriterion)
+	return nil
+}
+
+// ConfirmParticipation confirme une participation en attente et attribue au participant les points de la
+// catégorie de l'événement, dans une même transaction
+func (s *CheckInService) ConfirmParticipation(participation *models.Participation, actor *models.User) error {
+	if participation.Event == nil {
+		return coreErrors.ErrNotFound
+	}
+
+	granted := participationPoints(participation, actor)
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		var current models.Participation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, "id = ?", participation.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != enums.ParticipationPending {
+			return coreErrors.ErrParticipationAlreadyHandled
+		}
+
+		if err := tx.Model(&models.Participation{}).Where("id = ?", participation.ID).Updates(map[string]interface{}{
+			"status":       enums.ParticipationConfirmed,
+			"is_attending": true,
+		}).Error; err != nil {
+			return err
+		}
+		if err := recordPoints(tx, granted); err != nil {
+			return err
+		}
+
+		participation.Status = enums.ParticipationConfirmed
+		participation.IsAttending = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	publishPoints(granted, nil)
+	s.badgeService.Refresh(participation.UserID, enums.AttendedEventsCriterion)
+	return nil
+}
+
+// canCheckIn indique si la participation peut être pointée : l'inscrit participe et sa participation est en
+// attente ou confirmée, jamais refusée ni en liste d'attente
+func canCheckIn(participation *models.Participation) bool {
+	return participation.IsAttending &&
+		(participation.Status == enums.ParticipationPending || participation.Status == enums.ParticipationConfirmed)
+}
+
+// BulkAttendance applique l'action aux participations désignées de l'événement, dans une même transaction.
+// Une participation qui ne s'y prête pas (inconnue, déjà traitée ou déjà pointée) est signalée dans son
+// résultat sans interrompre le lot ; toute autre erreur annule le lot entier. Les places libérées par des
+// refus sont proposées à la liste d'attente. L'événement doit avoir sa catégorie chargée.
+func (s *CheckInService) BulkAttendance(event *models.Event, input BulkAttendanceInput, actor *models.User, now time.Time) ([]AttendanceResult, error) {
+	if !enums.IsValidAttendanceAction(input.Action) || input.AllPending == (len(input.ParticipationIDs) > 0) || len(input.ParticipationIDs) > MaxBulkAttendance {
+		return nil, coreErrors.ErrInvalidBulkAttendance
+	}
+	if input.Action == enums.MarkPresent || input.Action == enums.MarkAbsent {
+		if event.IsCancelled() {
+			return nil, coreErrors.ErrEventCancelled
+		}
+		if now.Before(event.Date.Add(-CheckInOpensBefore)) {
+			return nil, coreErrors.ErrCheckInNotOpen
+		}
+	}
+
+	var results []AttendanceResult
+	var granted []*models.PointsEntry
+	var promoted []models.Participation
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		// Verrouiller l'événement sérialise les lots et les inscriptions, dont dépend la liste d'attente
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Event{}, "id = ?", event.ID).Error; err != nil {
+			return err
+		}
+
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("event_id = ?", event.ID)
+		if input.AllPending {
+			query = pendingAttendance(query, input.Action)
+		} else {
+			query = query.Where("id IN ?", input.ParticipationIDs)
+		}
+		var participations []models.Participation
+		if err := query.Order("id").Find(&participations).Error; err != nil {
+			return err
+		}
+
+		ordered := participations
+		if !input.AllPending {
+			// Les résultats suivent l'ordre de la demande, les identifiants répétés n'étant traités qu'une fois
+			found := make(map[string]models.Participation, len(participations))
+			for _, participation := range participations {
+				found[participation.ID] = participation
+			}
+			ordered = make([]models.Participation, 0, len(input.ParticipationIDs))
+			seen := make(map[string]bool, len(input.ParticipationIDs))
+			for _, id := range input.ParticipationIDs {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				participation, ok := found[id]
+				if !ok {
+					results = append(results, AttendanceResult{ParticipationID: id, Err: coreErrors.ErrNotRegistered})
+					continue
+				}
+				ordered = append(ordered, participation)
+			}
+		}
+
+		declined := 0
+		for i := range ordered {
+			participation := &ordered[i]
+			participation.Event = event
+			entry, err := applyAttendance(tx, participation, input.Action, actor, now)
+			if err != nil && !isAttendanceRefusal(err) {
+				return err
+			}
+			if err == nil && input.Action == enums.DeclineAttendance {
+				declined++
+			}
+			if entry != nil && entry.Amount != 0 {
+				granted = append(granted, entry)
+			}
+			results = append(results, AttendanceResult{ParticipationID: participation.ID, UserID: participation.UserID, Err: err})
+		}
+
+		if declined > 0 {
+			var err error
+			promoted, err = promoteFromWaitlist(tx, event)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Les badges de chaque utilisateur ne sont recalculés qu'une fois, en arrière-plan pour ne pas retarder la réponse
+	refreshed := make(map[string]bool, len(granted))
+	userIDs := make([]string, 0, len(granted))
+	for _, entry := range granted {
+		publishPoints(entry, nil)
+		if !refreshed[entry.UserID] {
+			refreshed[entry.UserID] = true
+			userIDs = append(userIDs, entry.UserID)
+		}
+	}
+	if len(userIDs) > 0 {
+		go func() {
+			for _, userID := range userIDs {
+				s.badgeService.Refresh(userID, enums.AttendedEventsCriterion)
+			}
+		}()
+	}
+	if len(promoted) > 0 {
+		go s.notificationService.NotifyWaitlistPromotion(event, promoted)
+	}
+	return results, nil
+}
+
+// applyAttendance applique l'action à la participation verrouillée par la transaction tx et renvoie
+// l'écriture des points attribués, le cas échéant
+func applyAttendance(tx *gorm.DB, participation *models.Participation, action enums.AttendanceAction, actor *models.User, now time.Time) (*models.PointsEntry, error) {
+	updates := map[string]interface{}{}
+	var granted *models.PointsEntry
+
+	switch action {
+	case enums.ConfirmAttendance, enums.DeclineAttendance:
+		if participation.Status != enums.ParticipationPending {
+			return nil, coreErrors.ErrParticipationAlreadyHandled
+		}
+		if action == enums.ConfirmAttendance {
+			updates["status"] = enums.ParticipationConfirmed
+			updates["is_attending"] = true
+			granted = participationPoints(participation, actor)
+		} else {
+			updates["status"] = enums.ParticipationDeclined
+			updates["is_attending"] = false
+		}
+	case enums.MarkPresent, enums.MarkAbsent:
+		if !canCheckIn(participation) {
+			return nil, coreErrors.ErrNotRegistered
+		}
+		if participation.CheckedInAt != nil {
+			return nil, coreErrors.ErrAlreadyCheckedIn
+		}
+		if action == enums.MarkPresent {
+			updates["checked_in_at"] = now
+			updates["attendance"] = enums.Present
+			updates["status"] = enums.ParticipationConfirmed
+			// Comme au scan du QR code, une participation déjà confirmée a déjà rapporté ses points
+			if participation.Status != enums.ParticipationConfirmed {
+				granted = participationPoints(participation, actor)
+			}
+		} else {
+			updates["attendance"] = enums.Absent
+		}
+	}
+
+	if err := tx.Model(&models.Participation{}).Where("id = ?", participation.ID).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	if granted != nil {
+		if err := recordPoints(tx, granted); err != nil {
+			return nil, err
+		}
+	}
+	return granted, nil
+}
+
+// pendingAttendance restreint la requête aux participations encore à traiter pour l'action : en attente de
+// confirmation, ou pouvant être pointées sans présence relevée pour le pointage
+func pendingAttendance(query *gorm.DB, action enums.AttendanceAction) *gorm.DB {
+	if action == enums.ConfirmAttendance || action == enums.DeclineAttendance {
+		return query.Where("status = ?", enums.ParticipationPending)
+	}
+	return query.Where("is_attending = ? AND checked_in_at IS NULL", true).
+		Where("status IN ?", []string{enums.ParticipationPending, enums.ParticipationConfirmed}).
+		Where("attendance IS NULL OR attendance = ''")
+}
+
+// isAttendanceRefusal indique si l'erreur concerne seulement la participation, sans annuler le lot
+func isAttendanceRefusal(err error) bool {
+	return errors.Is(err, coreErrors.ErrParticipationAlreadyHandled) ||
+		errors.Is(err, coreErrors.ErrAlreadyCheckedIn) ||
+		errors.Is(err, coreErrors.ErrNotRegistered)
+}
+
+// participationPoints construit l'écriture des points gagnés par une participation confirmée
+func participationPoints(participation *models.Participation, actor *models.User) *models.PointsEntry {
+	return &models.PointsEntry{
+		UserID:        participation.UserID,
+		Amount:        participation.Event.Category.Note,
+		Source:        enums.ParticipationPoints,
+		Reference:     &participation.ID,
+		Reason:        participation.Event.Name,
+		ActorID:       actorID(actor),
+		AssociationID: &participation.Event.AssociationID,
+		CategoryID:    optionalID(participation.Event.CategoryID),
+	}
+}
+
+// MarkAbsentees marque absents les inscrits qui ne se sont pas présentés aux événements terminés.
+// Les événements annulés ou reportés sont ignorés.
+func (s *CheckInService) MarkAbsentees(now time.Time) (int64, error) {
+	endedEvents := database.CurrentDatabase.Model(&models.Event{}).
+		Select("id").
+		Where("recurrence_rule = '' OR recurrence_parent_id IS NOT NULL").
+		Where("status = ?", enums.EventScheduled).
+		Where(eventEndsAtSQL+" < ?", now)
+
+	result := database.CurrentDatabase.Model(&models.Participation{}).
+		Where("event_id IN (?)", endedEvents).
+		Where("is_attending = ? AND checked_in_at IS NULL", true).
+		Where("attendance IS NULL OR attendance = ''").
+		Update("attendance", enums.Absent)
+
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventCoHostService struct {
+	notificationService *NotificationService
+}
+
+func NewEventCoHostService() *EventCoHostService {
+	return &EventCoHostService{
+		notificationService: NewNotificationService(),
+	}
+}
+
+// HostedByScope restreint une requête sur la table events aux événements organisés ou co-organisés
+// (invitation acceptée) par les associations données : un identifiant ou une sous-requête d'identifiants
+func HostedByScope(associationIDs interface{}) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"events.association_id IN (?) OR COALESCE(events.recurrence_parent_id, events.id) IN "+
+				"(SELECT event_id FROM event_co_hosts WHERE status = ? AND association_id IN (?))",
+			associationIDs, enums.Accepted, associationIDs,
+		)
+	}
+}
+
+// hostedBySQL est l'équivalent de HostedByScope pour une sous-requête écrite en SQL. Ses paramètres
+// apparaissent deux fois dans la condition et doivent donc être passés deux fois.
+func hostedBySQL(associationsSQL string) string {
+	return fmt.Sprintf(
+		"(events.association_id IN (%s) OR COALESCE(events.recurrence_parent_id, events.id) IN "+
+			"(SELECT event_id FROM event_co_hosts WHERE status = '%s' AND association_id IN (%s)))",
+		associationsSQL, enums.Accepted, associationsSQL,
+	)
+}
+
+// InviteCoHost invite l'association à co-organiser l'événement et prévient son responsable.
+// Une invitation refusée peut être renouvelée ; une invitation en cours ou acceptée est renvoyée telle quelle.
+func (s *EventCoHostService) InviteCoHost(event *models.Event, associationID string, invitedByID string) (*models.EventCoHost, error) {
+	if associationID == event.AssociationID {
+		return nil, coreErrors.ErrInvalidCoHost
+	}
+
+	var association models.Association
+	if err := database.CurrentDatabase.Preload("Owner").First(&association, "id = ?", associationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, coreErrors.ErrAssociationNotFound
+		}
+		return nil, err
+	}
+
+	coHost := models.EventCoHost{
+		EventID:       event.FormEventID(),
+		AssociationID: associationID,
+		InvitedByID:   invitedByID,
+	}
+	err := database.CurrentDatabase.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "event_id"}, {Name: "association_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"status":        gorm.Expr("CASE WHEN event_co_hosts.status = ? THEN ? ELSE event_co_hosts.status END", enums.Rejected, enums.Pending),
+			"invited_by_id": invitedByID,
+		}),
+	}).Create(&coHost).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var stored models.EventCoHost
+	err = database.CurrentDatabase.Preload("Association").
+		First(&stored, "event_id = ? AND association_id = ?", coHost.EventID, associationID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.Status == enums.Pending {
+		go s.notificationService.NotifyCoHostInvitation(&association.Owner, event)
+	}
+	return &stored, nil
+}
+
+// GetCoHosts renvoie les associations invitées à co-organiser l'événement, quelle que soit leur réponse
+func (s *EventCoHostService) GetCoHosts(event *models.Event) ([]models.EventCoHost, error) {
+	var coHosts []models.EventCoHost
+	err := database.CurrentDatabase.
+		Preload("Association").
+		Where("event_id = ?", event.FormEventID()).
+		Order("created_at").
+		Find(&coHosts).Error
+	return coHosts, err
+}
+
+// GetPendingInvitations renvoie les invitations à co-organiser en attente de réponse de l'association
+func (s *EventCoHostService) GetPendingInvitations(associationID string) ([]models.EventCoHost, error) {
+	var coHosts []models.EventCoHost
+	err := database.CurrentDatabase.
+		Preload("Event.Association").
+		Where("association_id = ? AND status = ?", associationID, enums.Pending).
+		Order("created_at").
+		Find(&coHosts).Error
+	return coHosts, err
+}
+
+// RespondToInvitation enregistre l'acceptation ou le refus de l'association. Refuser une invitation
+// déjà acceptée revient à se retirer de l'organisation.
+func (s *EventCoHostService) RespondToInvitation(event *models.Event, associationID string, accept bool) (*models.EventCoHost, error) {
+	var coHost models.EventCoHost
+	err := database.CurrentDatabase.First(&coHost, "event_id = ? AND association_id = ?", event.FormEventID(), associationID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	status := enums.Rejected
+	if accept {
+		status = enums.Accepted
+	}
+	now := time.Now()
+	if err := database.CurrentDatabase.Model(&coHost).Updates(map[string]interface{}{
+		"status":       status,
+		"responded_at": now,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	coHost.Status = status
+	coHost.RespondedAt = &now
+	return &coHost, nil
+}
+
+// RemoveCoHost retire l'association des co-organisatrices de l'événement
+func (s *EventCoHostService) RemoveCoHost(event *models.Event, associationID string) error {
+	result := database.CurrentDatabase.Delete(&models.EventCoHost{}, "event_id = ? AND association_id = ?", event.FormEventID(), associationID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
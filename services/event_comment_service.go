@@ -0,0 +1,148 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/utils"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+type EventCommentService struct {
+	notificationService *NotificationService
+}
+
+func NewEventCommentService() *EventCommentService {
+	return &EventCommentService{
+		notificationService: NewNotificationService(),
+	}
+}
+
+// GetComments renvoie les commentaires de premier niveau de l'événement, du plus ancien au plus récent,
+// accompagnés de leurs réponses
+func (s *EventCommentService) GetComments(event *models.Event, pagination utils.Pagination) (*utils.Pagination, error) {
+	var comments []models.EventComment
+	query := database.CurrentDatabase.Model(&models.EventComment{}).
+		Where("event_id = ? AND parent_id IS NULL", event.FormEventID())
+
+	err := query.Session(&gorm.Session{}).
+		Scopes(utils.Paginate(comments, &pagination, query.Session(&gorm.Session{}))).
+		Preload("User").
+		Preload("Replies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		Preload("Replies.User").
+		Order("created_at").
+		Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = comments
+	return &pagination, nil
+}
+
+// GetComment renvoie un commentaire du fil de l'événement
+func (s *EventCommentService) GetComment(event *models.Event, commentID string) (*models.EventComment, error) {
+	var comment models.EventComment
+	err := database.CurrentDatabase.First(&comment, "id = ? AND event_id = ?", commentID, event.FormEventID()).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, coreErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// AddComment publie un commentaire, ou une réponse à un commentaire de premier niveau si parentID est renseigné.
+// Les responsables des associations organisatrices sont prévenus des nouvelles questions, l'auteur d'un
+// commentaire des réponses qu'il reçoit.
+func (s *EventCommentService) AddComment(event *models.Event, author *models.User, content string, parentID *string) (*models.EventComment, error) {
+	comment := &models.EventComment{
+		Content:  strings.TrimSpace(content),
+		EventID:  event.FormEventID(),
+		UserID:   author.ID,
+		ParentID: parentID,
+	}
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(comment); err != nil {
+		return nil, err
+	}
+
+	var parent *models.EventComment
+	if parentID != nil {
+		var err error
+		parent, err = s.GetComment(event, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ParentID != nil {
+			return nil, coreErrors.ErrInvalidCommentReply
+		}
+	}
+
+	if err := database.CurrentDatabase.Create(comment).Error; err != nil {
+		return nil, err
+	}
+	comment.User = author
+
+	if parent == nil {
+		go s.notifyOrganisers(event, author)
+	} else if parent.UserID != author.ID {
+		go s.notifyReply(event, parent.UserID)
+	}
+	return comment, nil
+}
+
+// SetAnswered marque une question comme résolue, ou la rouvre
+func (s *EventCommentService) SetAnswered(comment *models.EventComment, answered bool) error {
+	if comment.ParentID != nil {
+		return coreErrors.ErrInvalidCommentReply
+	}
+	if err := database.CurrentDatabase.Model(comment).Update("answered", answered).Error; err != nil {
+		return err
+	}
+	comment.Answered = answered
+	return nil
+}
+
+// DeleteComment supprime le commentaire et ses réponses
+func (s *EventCommentService) DeleteComment(comment *models.EventComment) error {
+	return database.CurrentDatabase.Delete(&models.EventComment{}, "id = ? OR parent_id = ?", comment.ID, comment.ID).Error
+}
+
+// notifyOrganisers prévient les responsables des associations organisatrices, hors auteur du commentaire
+func (s *EventCommentService) notifyOrganisers(event *models.Event, author *models.User) {
+	var organisers []models.User
+	err := database.CurrentDatabase.
+		Where("id <> ?", author.ID).
+		Where("id IN (SELECT owner_id FROM associations WHERE id = ? OR id IN "+
+			"(SELECT association_id FROM event_co_hosts WHERE event_id = ? AND status = ?))",
+			event.AssociationID, event.FormEventID(), enums.Accepted).
+		Find(&organisers).Error
+	if err != nil {
+		fmt.Printf("Erreur lors de la recherche des organisateurs de l'événement %s: %v\n", event.ID, err)
+		return
+	}
+
+	for i := range organisers {
+		s.notificationService.NotifyEventComment(&organisers[i], author, event)
+	}
+}
+
+// notifyReply prévient l'auteur d'un commentaire qu'une réponse y a été apportée
+func (s *EventCommentService) notifyReply(event *models.Event, userID string) {
+	var user models.User
+	if err := database.CurrentDatabase.First(&user, "id = ?", userID).Error; err != nil {
+		fmt.Printf("Erreur lors de la recherche de l'auteur du commentaire sur l'événement %s: %v\n", event.ID, err)
+		return
+	}
+	s.notificationService.NotifyCommentReply(&user, event)
+}
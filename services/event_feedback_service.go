@@ -0,0 +1,202 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FeedbackWindow est la durée, après la fin de l'événement, pendant laquelle les participants peuvent donner leur avis
+const FeedbackWindow = 14 * 24 * time.Hour
+
+type EventFeedbackService struct {
+}
+
+func NewEventFeedbackService() *EventFeedbackService {
+	return &EventFeedbackService{}
+}
+
+// FeedbackSummary agrège les notes reçues : nombre d'avis, moyenne et répartition par note (de 1 à 5)
+type FeedbackSummary struct {
+	Count         int64         `json:"count"`
+	AverageRating float64       `json:"average_rating"`
+	Distribution  map[int]int64 `json:"distribution"`
+}
+
+// EventFeedbackReport regroupe les résultats d'un événement et les avis détaillés des participants
+type EventFeedbackReport struct {
+	FeedbackSummary
+	Feedback []models.EventFeedback `json:"feedback"`
+}
+
+// EventFeedbackSummary est le résultat d'un événement dans le bilan d'une association
+type EventFeedbackSummary struct {
+	EventID       string    `json:"event_id"`
+	EventName     string    `json:"event_name"`
+	Date          time.Time `json:"date"`
+	Count         int64     `json:"count"`
+	AverageRating float64   `json:"average_rating"`
+}
+
+// AssociationFeedbackReport est le bilan des avis sur l'ensemble des événements d'une association
+type AssociationFeedbackReport struct {
+	FeedbackSummary
+	Events []EventFeedbackSummary `json:"events"`
+}
+
+// SubmitFeedback enregistre l'avis de l'utilisateur, ou remplace celui qu'il a déjà donné. Seuls les participants
+// confirmés ou pointés présents peuvent noter l'événement, entre sa fin et la fermeture du recueil.
+func (s *EventFeedbackService) SubmitFeedback(event *models.Event, userID string, feedback *models.EventFeedback, now time.Time) error {
+	if event.IsCancelled() {
+		return coreErrors.ErrEventCancelled
+	}
+	if now.Before(event.EndsAt()) || now.After(event.EndsAt().Add(FeedbackWindow)) {
+		return coreErrors.ErrFeedbackClosed
+	}
+
+	attended, err := hasAttended(event.ID, userID)
+	if err != nil {
+		return err
+	}
+	if !attended {
+		return coreErrors.ErrNotRegistered
+	}
+
+	feedback.EventID = event.ID
+	feedback.UserID = userID
+	feedback.Comment = strings.TrimSpace(feedback.Comment)
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(feedback); err != nil {
+		return err
+	}
+
+	err = database.CurrentDatabase.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rating", "comment", "updated_at"}),
+	}).Create(feedback).Error
+	if err != nil {
+		return err
+	}
+
+	// En cas de mise à jour, l'identifiant généré n'est pas celui de l'avis enregistré
+	var stored models.EventFeedback
+	if err := database.CurrentDatabase.First(&stored, "event_id = ? AND user_id = ?", event.ID, userID).Error; err != nil {
+		return err
+	}
+	*feedback = stored
+	return nil
+}
+
+// GetUserFeedback renvoie l'avis donné par l'utilisateur, ou nil s'il n'en a pas encore laissé
+func (s *EventFeedbackService) GetUserFeedback(eventID string, userID string) (*models.EventFeedback, error) {
+	var feedback models.EventFeedback
+	err := database.CurrentDatabase.First(&feedback, "event_id = ? AND user_id = ?", eventID, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &feedback, nil
+}
+
+// GetEventFeedback renvoie les résultats de l'événement. Pour une série, les avis de toutes ses occurrences sont regroupés.
+func (s *EventFeedbackService) GetEventFeedback(event *models.Event) (*EventFeedbackReport, error) {
+	query := database.CurrentDatabase.Model(&models.EventFeedback{})
+	if event.IsRecurring() {
+		query = query.Where("event_id IN (?)", database.CurrentDatabase.Model(&models.Event{}).
+			Select("id").Where("id = ? OR recurrence_parent_id = ?", event.ID, event.ID))
+	} else {
+		query = query.Where("event_id = ?", event.ID)
+	}
+
+	summary, err := summarizeFeedback(query.Session(&gorm.Session{}))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &EventFeedbackReport{FeedbackSummary: *summary}
+	err = query.Session(&gorm.Session{}).
+		Preload("User").
+		Order("created_at DESC").
+		Find(&report.Feedback).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// GetAssociationFeedback renvoie le bilan des avis de l'association et le résultat de chacun de ses événements notés
+func (s *EventFeedbackService) GetAssociationFeedback(associationID string) (*AssociationFeedbackReport, error) {
+	query := database.CurrentDatabase.Model(&models.EventFeedback{}).
+		Joins("JOIN events ON events.id = event_feedbacks.event_id").
+		Where("events.association_id = ?", associationID)
+
+	summary, err := summarizeFeedback(query.Session(&gorm.Session{}))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AssociationFeedbackReport{FeedbackSummary: *summary}
+	err = query.Session(&gorm.Session{}).
+		Select("events.id AS event_id, events.name AS event_name, events.date, COUNT(*) AS count, AVG(event_feedbacks.rating) AS average_rating").
+		Group("events.id, events.name, events.date").
+		Order("events.date DESC").
+		Scan(&report.Events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// summarizeFeedback calcule le nombre d'avis, la moyenne et la répartition des notes sur la requête donnée
+func summarizeFeedback(query *gorm.DB) (*FeedbackSummary, error) {
+	var ratings []struct {
+		Rating int
+		Count  int64
+	}
+	err := query.
+		Select("event_feedbacks.rating AS rating, COUNT(*) AS count").
+		Group("event_feedbacks.rating").
+		Scan(&ratings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &FeedbackSummary{Distribution: make(map[int]int64)}
+	var total int64
+	for rating := 1; rating <= 5; rating++ {
+		summary.Distribution[rating] = 0
+	}
+	for _, row := range ratings {
+		summary.Distribution[row.Rating] = row.Count
+		summary.Count += row.Count
+		total += int64(row.Rating) * row.Count
+	}
+	if summary.Count > 0 {
+		summary.AverageRating = float64(total) / float64(summary.Count)
+	}
+
+	return summary, nil
+}
+
+// hasAttended indique si l'utilisateur a participé à l'événement : inscription confirmée ou présence pointée
+func hasAttended(eventID string, userID string) (bool, error) {
+	var count int64
+	err := database.CurrentDatabase.Model(&models.Participation{}).
+		Where("event_id = ? AND user_id = ? AND is_attending = ?", eventID, userID, true).
+		Where("status = ? OR attendance = ?", enums.ParticipationConfirmed, enums.Present).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// maxTextAnswerLength borne la longueur des réponses libres
+const maxTextAnswerLength = 2000
+
+// ParticipationAnswerInput est une réponse envoyée à l'inscription. Value est une chaîne pour les
+// questions texte et choix unique, un nombre pour les questions numériques, une liste de chaînes
+// pour les choix multiples.
+type ParticipationAnswerInput struct {
+	QuestionID string      `json:"question_id"`
+	Value      interface{} `json:"value"`
+}
+
+// AnswersValidationError détaille, par identifiant de question, les réponses refusées
+type AnswersValidationError struct {
+	Details map[string]string
+}
+
+func (e *AnswersValidationError) Error() string {
+	return coreErrors.ErrInvalidAnswers.Error()
+}
+
+func (e *AnswersValidationError) Unwrap() error {
+	return coreErrors.ErrInvalidAnswers
+}
+
+type EventFormService struct {
+}
+
+func NewEventFormService() *EventFormService {
+	return &EventFormService{}
+}
+
+// GetQuestions renvoie le formulaire d'inscription de l'événement dans l'ordre d'affichage
+func (s *EventFormService) GetQuestions(event *models.Event) ([]models.EventQuestion, error) {
+	return findQuestions(database.CurrentDatabase, event.FormEventID())
+}
+
+// SetQuestions remplace le formulaire d'inscription. Les questions envoyées avec l'identifiant d'une question
+// du formulaire sont mises à jour, les autres créées ; les questions absentes sont supprimées avec leurs réponses.
+// Les réponses à une question dont le type change sont supprimées, leur format ne correspondant plus.
+func (s *EventFormService) SetQuestions(event *models.Event, questions []models.EventQuestion) ([]models.EventQuestion, error) {
+	eventID := event.FormEventID()
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	for i := range questions {
+		question := &questions[i]
+		question.Label = strings.TrimSpace(question.Label)
+		if err := validate.Struct(question); err != nil {
+			return nil, err
+		}
+
+		if !enums.IsChoiceQuestion(question.Type) {
+			question.Options = nil
+			continue
+		}
+		if err := validateQuestionOptions(question.Options); err != nil {
+			return nil, err
+		}
+	}
+
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		existing, err := findQuestions(tx, eventID)
+		if err != nil {
+			return err
+		}
+		existingByID := make(map[string]models.EventQuestion, len(existing))
+		for _, question := range existing {
+			existingByID[question.ID] = question
+		}
+
+		keptIDs := make(map[string]bool, len(questions))
+		for i := range questions {
+			question := &questions[i]
+			question.EventID = eventID
+			question.Position = i
+
+			if previous, ok := existingByID[question.ID]; ok && !keptIDs[question.ID] {
+				keptIDs[question.ID] = true
+				if previous.Type != question.Type {
+					if err := tx.Delete(&models.ParticipationAnswer{}, "question_id = ?", question.ID).Error; err != nil {
+						return err
+					}
+				}
+				if err := tx.Model(&models.EventQuestion{ID: question.ID}).Updates(map[string]interface{}{
+					"label":    question.Label,
+					"type":     question.Type,
+					"options":  question.Options,
+					"required": question.Required,
+					"position": question.Position,
+				}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			// Un identifiant inconnu du formulaire (autre événement, doublon) désigne une nouvelle question
+			question.ID = ""
+			if err := tx.Create(question).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, question := range existing {
+			if keptIDs[question.ID] {
+				continue
+			}
+			if err := tx.Delete(&models.ParticipationAnswer{}, "question_id = ?", question.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(&models.EventQuestion{}, "id = ?", question.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return findQuestions(database.CurrentDatabase, eventID)
+}
+
+// formatAnswer met en forme une réponse pour un export, les choix multiples séparés par des virgules
+func formatAnswer(question models.EventQuestion, answer models.ParticipationAnswer) string {
+	if question.Type == enums.MultipleChoiceQuestion {
+		return strings.Join(answer.Choices, ", ")
+	}
+	return answer.Value
+}
+
+func findQuestions(db *gorm.DB, eventID string) ([]models.EventQuestion, error) {
+	var questions []models.EventQuestion
+	err := db.Where("event_id = ?", eventID).Order("position, created_at").Find(&questions).Error
+	return questions, err
+}
+
+func validateQuestionOptions(options models.StringList) error {
+	if len(options) == 0 {
+		return fmt.Errorf("%w: une question à choix doit proposer au moins une option", coreErrors.ErrInvalidQuestion)
+	}
+
+	seen := make(map[string]bool, len(options))
+	for i, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" || seen[option] {
+			return fmt.Errorf("%w: options vides ou en double", coreErrors.ErrInvalidQuestion)
+		}
+		seen[option] = true
+		options[i] = option
+	}
+	return nil
+}
+
+// ValidateAnswers vérifie les réponses au formulaire et les convertit en réponses à enregistrer
+func ValidateAnswers(questions []models.EventQuestion, inputs []ParticipationAnswerInput) ([]models.ParticipationAnswer, error) {
+	details := make(map[string]string)
+
+	byQuestion := make(map[string]ParticipationAnswerInput, len(inputs))
+	for _, input := range inputs {
+		if _, ok := byQuestion[input.QuestionID]; ok {
+			details[input.QuestionID] = "Réponse en double"
+			continue
+		}
+		byQuestion[input.QuestionID] = input
+	}
+
+	var answers []models.ParticipationAnswer
+	for i := range questions {
+		question := &questions[i]
+		input, ok := byQuestion[question.ID]
+		delete(byQuestion, question.ID)
+
+		answer, message := parseAnswer(question, input.Value)
+		if message != "" {
+			details[question.ID] = message
+			continue
+		}
+		if !ok || answer == nil {
+			if question.Required {
+				details[question.ID] = "Réponse obligatoire"
+			}
+			continue
+		}
+
+		answer.QuestionID = question.ID
+		answers = append(answers, *answer)
+	}
+
+	for questionID := range byQuestion {
+		details[questionID] = "Question inconnue"
+	}
+
+	if len(details) > 0 {
+		return nil, &AnswersValidationError{Details: details}
+	}
+	return answers, nil
+}
+
+// parseAnswer convertit la valeur selon le type de la question. Une réponse vide renvoie nil
+// sans erreur ; le message d'erreur est renvoyé pour une valeur invalide.
+func parseAnswer(question *models.EventQuestion, value interface{}) (*models.ParticipationAnswer, string) {
+	if value == nil {
+		return nil, ""
+	}
+
+	switch question.Type {
+	case enums.TextQuestion:
+		text, ok := value.(string)
+		if !ok {
+			return nil, "Une réponse texte est attendue"
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, ""
+		}
+		if utf8.RuneCountInString(text) > maxTextAnswerLength {
+			return nil, fmt.Sprintf("La réponse ne doit pas dépasser %d caractères", maxTextAnswerLength)
+		}
+		return &models.ParticipationAnswer{Value: text}, ""
+
+	case enums.NumberQuestion:
+		var number float64
+		switch v := value.(type) {
+		case float64:
+			number = v
+		case string:
+			if strings.TrimSpace(v) == "" {
+				return nil, ""
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, "Un nombre est attendu"
+			}
+			number = parsed
+		default:
+			return nil, "Un nombre est attendu"
+		}
+		return &models.ParticipationAnswer{Value: strconv.FormatFloat(number, 'f', -1, 64)}, ""
+
+	case enums.SingleChoiceQuestion:
+		choice, ok := value.(string)
+		if !ok {
+			return nil, "Un choix unique est attendu"
+		}
+		if choice == "" {
+			return nil, ""
+		}
+		if !question.HasOption(choice) {
+			return nil, fmt.Sprintf("Choix invalide : %s", choice)
+		}
+		return &models.ParticipationAnswer{Value: choice}, ""
+
+	case enums.MultipleChoiceQuestion:
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, "Une liste de choix est attendue"
+		}
+
+		var choices models.StringList
+		seen := make(map[string]bool, len(values))
+		for _, v := range values {
+			choice, ok := v.(string)
+			if !ok || !question.HasOption(choice) {
+				return nil, fmt.Sprintf("Choix invalide : %v", v)
+			}
+			if !seen[choice] {
+				seen[choice] = true
+				choices = append(choices, choice)
+			}
+		}
+		if len(choices) == 0 {
+			return nil, ""
+		}
+		return &models.ParticipationAnswer{Choices: choices}, ""
+	}
+
+	return nil, "Type de question inconnu"
+}
+
+// replaceAnswers remplace les réponses enregistrées pour la participation
+func replaceAnswers(tx *gorm.DB, participationID string, answers []models.ParticipationAnswer) error {
+	if err := tx.Delete(&models.ParticipationAnswer{}, "participation_id = ?", participationID).Error; err != nil {
+		return err
+	}
+
+	for i := range answers {
+		answers[i].ParticipationID = participationID
+	}
+	if len(answers) == 0 {
+		return nil
+	}
+	return tx.Create(&answers).Error
+}
@@ -0,0 +1,525 @@
+package services
+
+import (
+	"backend/database"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/utils"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxCalendarImportSize borne la taille des fichiers et flux iCalendar importés
+const maxCalendarImportSize = 5 << 20
+
+// defaultCalendarFetchTimeout est le délai de récupération d'un calendrier distant sans CALENDAR_FETCH_TIMEOUT
+const defaultCalendarFetchTimeout = 15 * time.Second
+
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged"
+	ImportActionSkip      = "skip"
+)
+
+type EventImportItem struct {
+	UID    string       `json:"uid"`
+	Action string       `json:"action"`
+	Reason string       `json:"reason,omitempty"`
+	Event  models.Event `json:"event"`
+
+	exceptions []time.Time
+}
+
+type EventImportReport struct {
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Unchanged int               `json:"unchanged"`
+	Skipped   int               `json:"skipped"`
+	Items     []EventImportItem `json:"items"`
+}
+
+type EventImportService struct {
+	// HTTPClient récupère les calendriers distants, remplaçable pour les tests
+	HTTPClient *http.Client
+}
+
+func NewEventImportService() *EventImportService {
+	return &EventImportService{
+		HTTPClient: utils.NewPublicHTTPClient(calendarFetchTimeout()),
+	}
+}
+
+// calendarFetchTimeout lit le délai de récupération des calendriers distants (CALENDAR_FETCH_TIMEOUT, ex. 15s)
+func calendarFetchTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv("CALENDAR_FETCH_TIMEOUT"))
+	if err != nil || timeout <= 0 {
+		return defaultCalendarFetchTimeout
+	}
+	return timeout
+}
+
+// ParseCalendar lit un calendrier iCalendar en refusant les contenus trop volumineux
+func (s *EventImportService) ParseCalendar(r io.Reader) (*ics.Calendar, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxCalendarImportSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxCalendarImportSize {
+		return nil, coreErrors.ErrCalendarTooLarge
+	}
+
+	calendar, err := ics.ParseCalendar(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", coreErrors.ErrInvalidCalendar, err)
+	}
+	return calendar, nil
+}
+
+// FetchCalendar télécharge un calendrier distant (http, https ou webcal). Le client par défaut refuse les
+// hôtes internes, y compris derrière une redirection, et la taille du calendrier est bornée.
+func (s *EventImportService) FetchCalendar(rawURL string) (*ics.Calendar, error) {
+	calendarURL, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || calendarURL.Host == "" {
+		return nil, coreErrors.ErrInvalidCalendarURL
+	}
+
+	switch calendarURL.Scheme {
+	case "webcal":
+		calendarURL.Scheme = "https"
+	case "http", "https":
+	default:
+		return nil, coreErrors.ErrInvalidCalendarURL
+	}
+
+	request, err := http.NewRequest(http.MethodGet, calendarURL.String(), nil)
+	if err != nil {
+		return nil, coreErrors.ErrInvalidCalendarURL
+	}
+	request.Header.Set("Accept", "text/calendar")
+
+	response, err := s.HTTPClient.Do(request)
+	if errors.Is(err, utils.ErrForbiddenHost) {
+		return nil, coreErrors.ErrInvalidCalendarURL
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", coreErrors.ErrCalendarFetchFailed, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", coreErrors.ErrCalendarFetchFailed, response.Status)
+	}
+
+	return s.ParseCalendar(io.LimitReader(response.Body, maxCalendarImportSize+1))
+}
+
+// PreviewImport renvoie les événements que l'import créerait ou mettrait à jour, sans rien enregistrer
+func (s *EventImportService) PreviewImport(calendar *ics.Calendar, associationID string, categoryID string) (*EventImportReport, error) {
+	return s.planImport(database.CurrentDatabase, calendar, associationID, categoryID)
+}
+
+// ImportCalendar enregistre les événements du calendrier. Les événements déjà importés sont retrouvés
+// par leur UID d'origine : réimporter le même fichier ne crée pas de doublons.
+func (s *EventImportService) ImportCalendar(calendar *ics.Calendar, associationID string, categoryID string) (*EventImportReport, error) {
+	var report *EventImportReport
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		var err error
+		report, err = s.planImport(tx, calendar, associationID, categoryID)
+		if err != nil {
+			return err
+		}
+		return s.applyImport(tx, report)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *EventImportService) planImport(db *gorm.DB, calendar *ics.Calendar, associationID string, categoryID string) (*EventImportReport, error) {
+	var existingEvents []models.Event
+	err := db.Preload("RecurrenceExceptions").
+		Where("association_id = ? AND source_uid <> '' AND recurrence_parent_id IS NULL", associationID).
+		Find(&existingEvents).Error
+	if err != nil {
+		return nil, err
+	}
+	existingByUID := make(map[string]*models.Event, len(existingEvents))
+	for i := range existingEvents {
+		existingByUID[existingEvents[i].SourceUID] = &existingEvents[i]
+	}
+
+	categories, err := s.categoriesByName(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Les séries sont traitées avant leurs occurrences modifiées (RECURRENCE-ID)
+	vevents := calendar.Events()
+	sort.SliceStable(vevents, func(i, j int) bool {
+		return !vevents[i].HasProperty(ics.ComponentPropertyRecurrenceId) && vevents[j].HasProperty(ics.ComponentPropertyRecurrenceId)
+	})
+
+	report := &EventImportReport{Items: []EventImportItem{}}
+	plannedSeries := make(map[string]*models.Event)
+	seen := make(map[string]bool)
+	now := time.Now()
+
+	for _, vevent := range vevents {
+		item, recurrenceID := buildImportItem(vevent, associationID, categoryID, categories)
+
+		key := item.UID
+		if recurrenceID != nil {
+			key = fmt.Sprintf("%s|%d", item.UID, recurrenceID.Unix())
+		}
+		if item.Action == "" && seen[key] {
+			item.Action, item.Reason = ImportActionSkip, "UID en double dans le calendrier"
+		}
+		seen[key] = true
+
+		if item.Action == "" {
+			if recurrenceID == nil {
+				planSeriesItem(&item, existingByUID[item.UID])
+				validateImportItem(&item, now)
+				if item.Action != ImportActionSkip {
+					plannedSeries[item.UID] = &item.Event
+				}
+			} else {
+				series := plannedSeries[item.UID]
+				if series == nil {
+					series = existingByUID[item.UID]
+				}
+				if err := planOccurrenceItem(db, &item, *recurrenceID, series); err != nil {
+					return nil, err
+				}
+				validateImportItem(&item, now)
+			}
+		}
+
+		switch item.Action {
+		case ImportActionCreate:
+			report.Created++
+		case ImportActionUpdate:
+			report.Updated++
+		case ImportActionUnchanged:
+			report.Unchanged++
+		case ImportActionSkip:
+			report.Skipped++
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	return report, nil
+}
+
+// buildImportItem convertit un VEVENT en événement. Une action "skip" est renseignée si le VEVENT ne peut pas être importé.
+func buildImportItem(vevent *ics.VEvent, associationID string, categoryID string, categories map[string]string) (EventImportItem, *time.Time) {
+	item := EventImportItem{UID: vevent.Id()}
+	item.Event = models.Event{
+		Name:          icalText(vevent, ics.ComponentPropertySummary),
+		Description:   icalText(vevent, ics.ComponentPropertyDescription),
+		Location:      icalText(vevent, ics.ComponentPropertyLocation),
+		SourceUID:     item.UID,
+		AssociationID: associationID,
+		CategoryID:    categoryID,
+	}
+
+	// Une catégorie iCalendar portant le nom d'une catégorie existante est prioritaire sur la catégorie par défaut
+	for _, name := range strings.Split(icalText(vevent, ics.ComponentPropertyCategories), ",") {
+		if id, ok := categories[strings.ToLower(strings.TrimSpace(name))]; ok {
+			item.Event.CategoryID = id
+			break
+		}
+	}
+
+	if item.UID == "" {
+		item.Action, item.Reason = ImportActionSkip, "UID manquant"
+		return item, nil
+	}
+	if item.Event.Name == "" {
+		item.Action, item.Reason = ImportActionSkip, "Titre manquant"
+		return item, nil
+	}
+	if strings.EqualFold(icalText(vevent, ics.ComponentPropertyStatus), string(ics.ObjectStatusCancelled)) {
+		item.Action, item.Reason = ImportActionSkip, "Événement annulé"
+		return item, nil
+	}
+
+	location := utils.CalendarLocation()
+
+	start, err := icalDateTime(vevent, ics.ComponentPropertyDtStart, location)
+	if err != nil {
+		item.Action, item.Reason = ImportActionSkip, "Date de début invalide"
+		return item, nil
+	}
+	item.Event.Date = start
+	item.Event.TimeZone = icalTimeZone(vevent.GetProperty(ics.ComponentPropertyDtStart))
+
+	if vevent.HasProperty(ics.ComponentPropertyDtEnd) {
+		end, err := icalDateTime(vevent, ics.ComponentPropertyDtEnd, location)
+		if err != nil {
+			item.Action, item.Reason = ImportActionSkip, "Date de fin invalide"
+			return item, nil
+		}
+		// Un DTEND égal au DTSTART désigne un événement sans durée
+		if !end.Equal(start) {
+			item.Event.EndDate = &end
+		}
+	}
+
+	var recurrenceID *time.Time
+	if vevent.HasProperty(ics.ComponentPropertyRecurrenceId) {
+		date, err := icalDateTime(vevent, ics.ComponentPropertyRecurrenceId, location)
+		if err != nil {
+			item.Action, item.Reason = ImportActionSkip, "RECURRENCE-ID invalide"
+			return item, nil
+		}
+		recurrenceID = &date
+		item.Event.RecurrenceID = &date
+		return item, recurrenceID
+	}
+
+	if rule := icalText(vevent, ics.ComponentPropertyRrule); rule != "" {
+		item.Event.RecurrenceRule, err = utils.NormalizeRecurrenceRule(rule, recurrenceStart(&item.Event))
+		if err != nil {
+			item.Action, item.Reason = ImportActionSkip, "Règle de récurrence non supportée"
+			return item, nil
+		}
+
+		for _, property := range vevent.GetProperties(ics.ComponentPropertyExdate) {
+			dates, err := utils.ParseICalDateTimes(property, location)
+			if err != nil {
+				item.Action, item.Reason = ImportActionSkip, "EXDATE invalide"
+				return item, nil
+			}
+			item.exceptions = append(item.exceptions, dates...)
+		}
+	}
+
+	return item, nil
+}
+
+// planSeriesItem compare l'événement importé à celui déjà enregistré avec le même UID
+func planSeriesItem(item *EventImportItem, existing *models.Event) {
+	if existing == nil {
+		item.Action = ImportActionCreate
+		return
+	}
+
+	item.Event.ID = existing.ID
+	// La catégorie d'un événement déjà importé a pu être modifiée dans l'application : elle est conservée
+	item.Event.CategoryID = existing.CategoryID
+
+	knownExceptions := make(map[int64]bool)
+	for _, date := range existing.ExceptionDates() {
+		knownExceptions[date.Unix()] = true
+	}
+	var newExceptions []time.Time
+	for _, date := range item.exceptions {
+		if !knownExceptions[date.Unix()] {
+			newExceptions = append(newExceptions, date)
+		}
+	}
+	item.exceptions = newExceptions
+
+	if existing.Name == item.Event.Name &&
+		existing.Description == item.Event.Description &&
+		existing.Location == item.Event.Location &&
+		existing.Date.Equal(item.Event.Date) &&
+		sameEndDate(existing, &item.Event) &&
+		existing.TimeZone == item.Event.TimeZone &&
+		existing.RecurrenceRule == item.Event.RecurrenceRule &&
+		len(newExceptions) == 0 {
+		item.Action = ImportActionUnchanged
+		return
+	}
+	item.Action = ImportActionUpdate
+}
+
+// validateImportItem applique à l'événement à créer ou modifier les contrôles d'AddEvent.
+// Un événement invalide est signalé dans le rapport et n'est pas enregistré.
+func validateImportItem(item *EventImportItem, now time.Time) {
+	if item.Action != ImportActionCreate && item.Action != ImportActionUpdate {
+		return
+	}
+
+	err := validateNewEvent(&item.Event)
+	if err == nil && item.Action == ImportActionCreate {
+		err = preparePublication(&item.Event, now)
+	}
+	if err != nil {
+		item.Action, item.Reason = ImportActionSkip, importFailureReason(err)
+	}
+}
+
+func importFailureReason(err error) string {
+	switch {
+	case errors.Is(err, coreErrors.ErrInvalidEventSchedule):
+		return "Date de fin antérieure au début"
+	case errors.Is(err, coreErrors.ErrInvalidRecurrenceRule):
+		return "Règle de récurrence non supportée"
+	default:
+		return "Événement invalide"
+	}
+}
+
+// planOccurrenceItem prépare une occurrence modifiée, rattachée à la série du même UID
+func planOccurrenceItem(db *gorm.DB, item *EventImportItem, recurrenceID time.Time, series *models.Event) error {
+	if series == nil || !series.IsRecurring() {
+		item.Action, item.Reason = ImportActionSkip, "Série d'origine introuvable"
+		return nil
+	}
+
+	isOccurrence, err := utils.IsRecurrenceOccurrence(series.RecurrenceRule, recurrenceStart(series), nil, recurrenceID)
+	if err != nil || !isOccurrence {
+		item.Action, item.Reason = ImportActionSkip, "Occurrence inconnue de la série"
+		return nil
+	}
+
+	item.Event.RecurrenceParentID = &series.ID
+	if series.ID == "" {
+		item.Action = ImportActionCreate
+		return nil
+	}
+
+	var existing models.Event
+	err = db.Where("recurrence_parent_id = ? AND recurrence_id = ?", series.ID, recurrenceID).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		item.Action = ImportActionCreate
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	item.Event.ID = existing.ID
+	item.Event.CategoryID = existing.CategoryID
+	if existing.Name == item.Event.Name &&
+		existing.Description == item.Event.Description &&
+		existing.Location == item.Event.Location &&
+		existing.Date.Equal(item.Event.Date) &&
+		sameEndDate(&existing, &item.Event) {
+		item.Action = ImportActionUnchanged
+		return nil
+	}
+	item.Action = ImportActionUpdate
+	return nil
+}
+
+func (s *EventImportService) applyImport(tx *gorm.DB, report *EventImportReport) error {
+	seriesIDs := make(map[string]string)
+
+	for i := range report.Items {
+		item := &report.Items[i]
+		event := &item.Event
+
+		// Une occurrence d'une série créée par ce même import ne connaît l'identifiant de sa série qu'ici
+		if event.RecurrenceID != nil {
+			if seriesID, ok := seriesIDs[item.UID]; ok {
+				event.RecurrenceParentID = &seriesID
+			}
+		}
+
+		switch item.Action {
+		case ImportActionCreate:
+			if err := tx.Create(event).Error; err != nil {
+				return err
+			}
+		case ImportActionUpdate:
+			if err := tx.Model(&models.Event{ID: event.ID}).Updates(map[string]interface{}{
+				"name":            event.Name,
+				"description":     event.Description,
+				"date":            event.Date,
+				"end_date":        event.EndDate,
+				"time_zone":       event.TimeZone,
+				"location":        event.Location,
+				"recurrence_rule": event.RecurrenceRule,
+			}).Error; err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+
+		if event.RecurrenceID == nil {
+			seriesIDs[item.UID] = event.ID
+		}
+
+		for _, date := range item.exceptions {
+			exception := models.EventRecurrenceException{EventID: event.ID, OccurrenceDate: date}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&exception).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *EventImportService) categoriesByName(db *gorm.DB) (map[string]string, error) {
+	var categories []models.Category
+	if err := db.Select("id", "name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]string, len(categories))
+	for _, category := range categories {
+		byName[strings.ToLower(category.Name)] = category.ID
+	}
+	return byName, nil
+}
+
+func icalText(vevent *ics.VEvent, property ics.ComponentProperty) string {
+	if p := vevent.GetProperty(property); p != nil {
+		return strings.TrimSpace(p.Value)
+	}
+	return ""
+}
+
+// icalTimeZone renvoie le TZID de la propriété s'il désigne un fuseau IANA connu
+func icalTimeZone(property *ics.IANAProperty) string {
+	if property == nil {
+		return ""
+	}
+	tzid, ok := property.ICalParameters[string(ics.ParameterTzid)]
+	if !ok || len(tzid) != 1 {
+		return ""
+	}
+	if _, err := time.LoadLocation(tzid[0]); err != nil {
+		return ""
+	}
+	return tzid[0]
+}
+
+func sameEndDate(existing *models.Event, imported *models.Event) bool {
+	if existing.EndDate == nil || imported.EndDate == nil {
+		return existing.EndDate == nil && imported.EndDate == nil
+	}
+	return existing.EndDate.Equal(*imported.EndDate)
+}
+
+func icalDateTime(vevent *ics.VEvent, property ics.ComponentProperty, location *time.Location) (time.Time, error) {
+	p := vevent.GetProperty(property)
+	if p == nil {
+		return time.Time{}, fmt.Errorf("missing %s", property)
+	}
+
+	dates, err := utils.ParseICalDateTimes(p, location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return dates[0], nil
+}
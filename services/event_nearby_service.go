@@ -0,0 +1,98 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	"backend/models"
+	"backend/utils"
+	"sort"
+	"time"
+)
+
+// earthRadiusKm est le rayon moyen de la Terre utilisé pour le calcul des distances
+const earthRadiusKm = 6371.0
+
+// venueDistanceSQL calcule en SQL la distance en kilomètres entre le lieu de l'événement et le point
+// recherché (formule de haversine). Paramètres : rayon terrestre, latitude, latitude, longitude.
+const venueDistanceSQL = "? * 2 * ASIN(LEAST(1, SQRT(" +
+	"POWER(SIN(RADIANS(venues.latitude - ?) / 2), 2) + " +
+	"COS(RADIANS(?)) * COS(RADIANS(venues.latitude)) * POWER(SIN(RADIANS(venues.longitude - ?) / 2), 2))))"
+
+// NearbyEvent est un événement accompagné de sa distance, en kilomètres, au point recherché
+type NearbyEvent struct {
+	models.Event
+	Distance float64 `json:"distance"`
+}
+
+// GetNearbyEvents renvoie les événements à venir visibles par viewer dont le lieu se trouve à moins de radiusKm
+// du point donné, du plus proche au plus lointain. Les séries récurrentes sont dépliées sur l'horizon habituel.
+func (s *EventService) GetNearbyEvents(viewer *models.User, latitude, longitude, radiusKm float64, pagination utils.Pagination) (*utils.Pagination, error) {
+	from := time.Now()
+	distanceArgs := []interface{}{earthRadiusKm, latitude, latitude, longitude}
+
+	var matches []struct {
+		ID       string
+		Distance float64
+	}
+	err := database.CurrentDatabase.Model(&models.Event{}).
+		Select("events.id, "+venueDistanceSQL+" AS distance", distanceArgs...).
+		Joins("JOIN venues ON venues.id = events.venue_id").
+		Scopes(VisibleEventsScope(viewer)).
+		Where("events.recurrence_parent_id IS NULL").
+		Where("events.status <> ?", enums.EventCancelled).
+		Where("("+eventEndsAtSQL+" >= ? OR events.recurrence_rule <> '')", from).
+		Where(venueDistanceSQL+" <= ?", append(distanceArgs, radiusKm)...).
+		Order("distance, events.date").
+		Scan(&matches).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		pagination.Rows = utils.PaginateSlice([]NearbyEvent{}, &pagination)
+		return &pagination, nil
+	}
+
+	ids := make([]string, len(matches))
+	distances := make(map[string]float64, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+		distances[match.ID] = match.Distance
+	}
+
+	var events []models.Event
+	err = database.CurrentDatabase.
+		Preload("Category").
+		Preload("Association").
+		Preload("Venue").
+		Preload("RecurrenceExceptions").
+		Where("id IN ?", ids).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	expanded, err := s.ExpandOccurrences(events, from, from.Add(RecurrenceHorizon))
+	if err != nil {
+		return nil, err
+	}
+
+	nearby := make([]NearbyEvent, 0, len(expanded))
+	for _, event := range expanded {
+		// Les occurrences annulées ou déjà terminées d'une série ne sont pas proposées
+		if event.IsCancelled() || event.EndsAt().Before(from) {
+			continue
+		}
+		nearby = append(nearby, NearbyEvent{Event: event, Distance: distances[event.FormEventID()]})
+	}
+
+	sort.SliceStable(nearby, func(i, j int) bool {
+		if nearby[i].Distance != nearby[j].Distance {
+			return nearby[i].Distance < nearby[j].Distance
+		}
+		return nearby[i].Date.Before(nearby[j].Date)
+	})
+
+	pagination.Rows = utils.PaginateSlice(nearby, &pagination)
+	return &pagination, nil
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"backend/database"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/utils"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// MaxPhotoSize est la taille maximale d'une photo envoyée, en octets
+	MaxPhotoSize = 10 << 20
+	// MaxPhotosPerEvent est le nombre de photos qu'un utilisateur peut partager dans la galerie d'un événement
+	MaxPhotosPerEvent = 30
+	// PhotoStorageQuota est l'espace total, en octets, que les photos d'un utilisateur peuvent occuper
+	PhotoStorageQuota = 200 << 20
+
+	// maxPhotoPixels protège le décodage contre les images aux dimensions démesurées
+	maxPhotoPixels      = 50_000_000
+	photoThumbnailSide  = 400
+	photoPublicPrefix   = "public"
+	photoHiddenDir      = "private"
+	photoStorageSubPath = "events"
+)
+
+type EventPhotoService struct {
+	// StorageDir est le dossier servi sous /public dans lequel les photos sont enregistrées
+	StorageDir string
+	// HiddenDir est le dossier, non servi, dans lequel sont déplacées les photos masquées
+	HiddenDir string
+}
+
+func NewEventPhotoService() *EventPhotoService {
+	return &EventPhotoService{
+		StorageDir: photoPublicPrefix,
+		HiddenDir:  photoHiddenDir,
+	}
+}
+
+// UploadPhoto ajoute la photo à la galerie de l'événement. Hors responsable (bypassAttendance), seuls les
+// participants confirmés ou pointés présents peuvent en envoyer, à partir du début de l'événement.
+func (s *EventPhotoService) UploadPhoto(event *models.Event, userID string, file *multipart.FileHeader, bypassAttendance bool) (*models.EventPhoto, error) {
+	if event.IsCancelled() {
+		return nil, coreErrors.ErrEventCancelled
+	}
+	if !bypassAttendance {
+		if time.Now().Before(event.Date) {
+			return nil, coreErrors.ErrGalleryNotOpen
+		}
+		attended, err := hasAttended(event.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !attended {
+			return nil, coreErrors.ErrNotRegistered
+		}
+	}
+
+	if file.Size > MaxPhotoSize {
+		return nil, coreErrors.ErrImageTooLarge
+	}
+	if !utils.IsImage(*file) {
+		return nil, coreErrors.ErrInvalidImage
+	}
+
+	data, err := readUpload(file)
+	if err != nil {
+		return nil, err
+	}
+	width, height, err := utils.ImageDimensions(data)
+	if err != nil {
+		return nil, coreErrors.ErrInvalidImage
+	}
+	if width*height > maxPhotoPixels {
+		return nil, coreErrors.ErrImageTooLarge
+	}
+	thumbnail, err := utils.GenerateThumbnail(data, photoThumbnailSide)
+	if err != nil {
+		return nil, coreErrors.ErrInvalidImage
+	}
+
+	photo := &models.EventPhoto{
+		ID:          utils.GenerateULID(),
+		ContentType: utils.MimeFromIncipit(data[:min(len(data), 512)]),
+		Size:        int64(len(data)),
+		Width:       width,
+		Height:      height,
+		EventID:     event.ID,
+		UserID:      userID,
+	}
+	relativePath := path.Join(photoStorageSubPath, event.ID, photo.ID+utils.GetImageExt(*file))
+	relativeThumbnailPath := path.Join(photoStorageSubPath, event.ID, photo.ID+"_thumb.jpg")
+	photo.URL = path.Join(photoPublicPrefix, relativePath)
+	photo.ThumbnailURL = path.Join(photoPublicPrefix, relativeThumbnailPath)
+
+	if err := s.writeFile(relativePath, data); err != nil {
+		return nil, err
+	}
+	if err := s.writeFile(relativeThumbnailPath, thumbnail); err != nil {
+		s.removePhotoFiles([]models.EventPhoto{*photo})
+		return nil, err
+	}
+
+	err = database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		// Le verrou sur l'utilisateur empêche des envois simultanés de dépasser ses quotas
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.User{}, "id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		var usage struct {
+			EventCount int64
+			TotalSize  int64
+		}
+		err := tx.Model(&models.EventPhoto{}).
+			Select("COUNT(*) FILTER (WHERE event_id = ?) AS event_count, COALESCE(SUM(size), 0) AS total_size", event.ID).
+			Where("user_id = ?", userID).
+			Scan(&usage).Error
+		if err != nil {
+			return err
+		}
+		if !bypassAttendance && usage.EventCount >= MaxPhotosPerEvent {
+			return coreErrors.ErrPhotoQuotaExceeded
+		}
+		if usage.TotalSize+photo.Size > PhotoStorageQuota {
+			return coreErrors.ErrPhotoQuotaExceeded
+		}
+
+		return tx.Create(photo).Error
+	})
+	if err != nil {
+		s.removePhotoFiles([]models.EventPhoto{*photo})
+		return nil, err
+	}
+
+	return photo, nil
+}
+
+// GetEventPhotos renvoie la galerie de l'événement. Les photos masquées ne sont renvoyées qu'au responsable
+// (includeHidden) et à leur auteur.
+func (s *EventPhotoService) GetEventPhotos(eventID string, viewerID string, includeHidden bool) ([]models.EventPhoto, error) {
+	query := database.CurrentDatabase.Preload("User").Where("event_id = ?", eventID)
+	if !includeHidden {
+		query = query.Where("hidden = ? OR user_id = ?", false, viewerID)
+	}
+
+	var photos []models.EventPhoto
+	err := query.Order("created_at").Find(&photos).Error
+	return photos, err
+}
+
+// GetPhoto renvoie une photo de la galerie de l'événement
+func (s *EventPhotoService) GetPhoto(eventID string, photoID string) (*models.EventPhoto, error) {
+	var photo models.EventPhoto
+	err := database.CurrentDatabase.First(&photo, "id = ? AND event_id = ?", photoID, eventID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, coreErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &photo, nil
+}
+
+// SetPhotoHidden masque ou rétablit une photo ; une photo masquée ne peut plus servir de couverture. Les
+// fichiers d'une photo masquée sont déplacés hors du dossier public et ne sont plus servis que par PhotoFile.
+func (s *EventPhotoService) SetPhotoHidden(photo *models.EventPhoto, hidden bool) error {
+	if photo.Hidden != hidden {
+		if err := s.movePhotoFiles(photo, hidden); err != nil {
+			return err
+		}
+	}
+
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(photo).Update("hidden", hidden).Error; err != nil {
+			return err
+		}
+		if !hidden {
+			return nil
+		}
+		return tx.Model(&models.Event{}).Where("cover_photo_id = ?", photo.ID).Update("cover_photo_id", nil).Error
+	})
+	if err != nil {
+		if photo.Hidden != hidden {
+			if err := s.movePhotoFiles(photo, photo.Hidden); err != nil {
+				fmt.Printf("Erreur lors du déplacement de la photo %s: %v\n", photo.ID, err)
+			}
+		}
+		return err
+	}
+
+	photo.Hidden = hidden
+	return nil
+}
+
+// PhotoFile renvoie le chemin sur le disque de la photo, ou de sa miniature, qu'elle soit masquée ou non
+func (s *EventPhotoService) PhotoFile(photo *models.EventPhoto, thumbnail bool) string {
+	url := photo.URL
+	if thumbnail {
+		url = photo.ThumbnailURL
+	}
+	return s.filePath(s.photoDir(photo.Hidden), url)
+}
+
+// DeletePhoto supprime la photo de la galerie et ses fichiers
+func (s *EventPhotoService) DeletePhoto(photo *models.EventPhoto) error {
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Event{}).Where("cover_photo_id = ?", photo.ID).Update("cover_photo_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.EventPhoto{}, "id = ?", photo.ID).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.removePhotoFiles([]models.EventPhoto{*photo})
+	return nil
+}
+
+// SetCoverPhoto choisit la photo de couverture de l'événement parmi les photos visibles de sa galerie ;
+// photoID vide retire la couverture
+func (s *EventPhotoService) SetCoverPhoto(event *models.Event, photoID string) error {
+	if photoID == "" {
+		event.CoverPhotoID = nil
+		event.CoverPhoto = nil
+		return database.CurrentDatabase.Model(&models.Event{}).Where("id = ?", event.ID).Update("cover_photo_id", nil).Error
+	}
+
+	photo, err := s.GetPhoto(event.ID, photoID)
+	if err != nil {
+		return err
+	}
+	if photo.Hidden {
+		return coreErrors.ErrNotFound
+	}
+
+	if err := database.CurrentDatabase.Model(&models.Event{}).Where("id = ?", event.ID).Update("cover_photo_id", photo.ID).Error; err != nil {
+		return err
+	}
+	event.CoverPhotoID = &photo.ID
+	event.CoverPhoto = photo
+	return nil
+}
+
+// photoDir renvoie le dossier contenant les fichiers des photos masquées ou visibles
+func (s *EventPhotoService) photoDir(hidden bool) string {
+	if hidden {
+		return s.HiddenDir
+	}
+	return s.StorageDir
+}
+
+// filePath renvoie le chemin dans dir du fichier désigné par l'URL publique d'une photo
+func (s *EventPhotoService) filePath(dir string, url string) string {
+	return filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(url, photoPublicPrefix+"/")))
+}
+
+// movePhotoFiles déplace les fichiers de la photo vers le dossier des photos masquées (hidden) ou vers le
+// dossier public
+func (s *EventPhotoService) movePhotoFiles(photo *models.EventPhoto, hidden bool) error {
+	for _, url := range []string{photo.URL, photo.ThumbnailURL} {
+		destination := s.filePath(s.photoDir(hidden), url)
+		if err := os.MkdirAll(filepath.Dir(destination), os.ModePerm); err != nil {
+			return err
+		}
+		err := os.Rename(s.filePath(s.photoDir(!hidden), url), destination)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *EventPhotoService) writeFile(relativePath string, data []byte) error {
+	fullPath := filepath.Join(s.StorageDir, filepath.FromSlash(relativePath))
+	if err := os.MkdirAll(filepath.Dir(fullPath), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(fullPath, data, 0o644)
+}
+
+// removePhotoFiles supprime les fichiers des photos ; les échecs sont journalisés sans interrompre le traitement
+func (s *EventPhotoService) removePhotoFiles(photos []models.EventPhoto) {
+	for _, photo := range photos {
+		for _, url := range []string{photo.URL, photo.ThumbnailURL} {
+			err := os.Remove(s.filePath(s.photoDir(photo.Hidden), url))
+			if err != nil && !os.IsNotExist(err) {
+				fmt.Printf("Erreur lors de la suppression de la photo %s: %v\n", photo.ID, err)
+			}
+		}
+	}
+}
+
+// readUpload lit le fichier envoyé en refusant tout contenu au-delà de MaxPhotoSize
+func readUpload(file *multipart.FileHeader) ([]byte, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, MaxPhotoSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxPhotoSize {
+		return nil, coreErrors.ErrImageTooLarge
+	}
+	return data, nil
+}
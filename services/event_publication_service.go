@@ -0,0 +1,175 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// preparePublication valide l'état de publication d'un nouvel événement ; sans précision, il est publié immédiatement
+func preparePublication(event *models.Event, now time.Time) error {
+	event.PublishedAt = nil
+	switch event.PublicationStatus {
+	case "", enums.PublishedPublication:
+		event.PublicationStatus = enums.PublishedPublication
+		event.PublishAt = nil
+		event.PublishedAt = &now
+	case enums.DraftPublication:
+		event.PublishAt = nil
+	case enums.ScheduledPublication:
+		if event.PublishAt == nil || !event.PublishAt.After(now) {
+			return coreErrors.ErrInvalidPublishAt
+		}
+	}
+	return nil
+}
+
+// PublishEvent publie immédiatement le brouillon ou l'événement programmé, avec les occurrences d'une série,
+// et prévient les membres qui peuvent le consulter
+func (s *EventService) PublishEvent(event *models.Event, now time.Time) error {
+	changed, err := changePublication(event, map[string]interface{}{
+		"publication_status": enums.PublishedPublication,
+		"publish_at":         nil,
+		"published_at":       now,
+	})
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return coreErrors.ErrEventAlreadyPublished
+	}
+
+	event.PublicationStatus = enums.PublishedPublication
+	event.PublishAt = nil
+	event.PublishedAt = &now
+
+	published := *event
+	go s.notifyPublication(&published)
+	return nil
+}
+
+// SchedulePublication programme la publication de l'événement à publishAt
+func (s *EventService) SchedulePublication(event *models.Event, publishAt time.Time, now time.Time) error {
+	if !publishAt.After(now) {
+		return coreErrors.ErrInvalidPublishAt
+	}
+
+	changed, err := changePublication(event, map[string]interface{}{
+		"publication_status": enums.ScheduledPublication,
+		"publish_at":         publishAt,
+	})
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return coreErrors.ErrEventAlreadyPublished
+	}
+
+	event.PublicationStatus = enums.ScheduledPublication
+	event.PublishAt = &publishAt
+	return nil
+}
+
+// RevertToDraft repasse en brouillon un événement pas encore publié, annulant sa publication programmée
+func (s *EventService) RevertToDraft(event *models.Event) error {
+	changed, err := changePublication(event, map[string]interface{}{
+		"publication_status": enums.DraftPublication,
+		"publish_at":         nil,
+	})
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return coreErrors.ErrEventAlreadyPublished
+	}
+
+	event.PublicationStatus = enums.DraftPublication
+	event.PublishAt = nil
+	return nil
+}
+
+// PublishDueEvents publie les événements dont la date de publication programmée est atteinte et
+// renvoie leur nombre
+func (s *EventService) PublishDueEvents(now time.Time) (int, error) {
+	var events []models.Event
+	err := database.CurrentDatabase.
+		Where("publication_status = ? AND publish_at <= ?", enums.ScheduledPublication, now).
+		Where("recurrence_parent_id IS NULL").
+		Order("publish_at").
+		Find(&events).Error
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := range events {
+		err := s.PublishEvent(&events[i], now)
+		// Un responsable a pu publier l'événement entre-temps
+		if errors.Is(err, coreErrors.ErrEventAlreadyPublished) {
+			continue
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// changePublication applique les changements à l'événement pas encore publié et aux occurrences matérialisées
+// d'une série. La publication porte sur la série : une occurrence ne peut pas être publiée seule.
+// Renvoie false si l'événement était déjà publié.
+func changePublication(event *models.Event, changes map[string]interface{}) (bool, error) {
+	if event.RecurrenceParentID != nil {
+		return false, coreErrors.ErrInvalidOccurrence
+	}
+
+	changed := false
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Event{}).
+			Where("id = ? AND publication_status <> ?", event.ID, enums.PublishedPublication).
+			Updates(changes)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		changed = true
+
+		return tx.Model(&models.Event{}).Where("recurrence_parent_id = ?", event.ID).Updates(changes).Error
+	})
+	return changed, err
+}
+
+// notifyPublication prévient de la publication les utilisateurs qui peuvent consulter l'événement : ses invités
+// pour un événement sur invitation, les membres acceptés des associations organisatrices sinon.
+// Le responsable de l'association peut y gagner un badge d'organisateur.
+func (s *EventService) notifyPublication(event *models.Event) {
+	var association models.Association
+	if err := database.CurrentDatabase.Select("id", "owner_id").First(&association, "id = ?", event.AssociationID).Error; err == nil {
+		s.badgeService.Refresh(association.OwnerID, enums.OrganisedEventsCriterion)
+	}
+
+	query := database.CurrentDatabase.Model(&models.User{})
+	if event.Visibility == enums.InviteOnlyVisibility {
+		query = query.Where("id IN (SELECT user_id FROM event_invitations WHERE event_id = ?)", event.FormEventID())
+	} else {
+		query = query.Where(
+			"id IN (SELECT user_id FROM memberships WHERE status = ? AND deleted_at IS NULL AND "+
+				"(association_id = ? OR association_id IN (SELECT association_id FROM event_co_hosts WHERE event_id = ? AND status = ?)))",
+			enums.Accepted, event.AssociationID, event.FormEventID(), enums.Accepted,
+		)
+	}
+
+	var users []models.User
+	if err := query.Find(&users).Error; err != nil {
+		fmt.Printf("Erreur lors de la recherche des membres à prévenir pour l'événement %s: %v\n", event.ID, err)
+		return
+	}
+
+	s.notificationService.NotifyEventPublished(event, users)
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"backend/database"
+	"backend/models"
+	"backend/utils"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Tris proposés par la recherche d'événements
+const (
+	EventSortDate       = "date"
+	EventSortDateDesc   = "-date"
+	EventSortPopularity = "popularity"
+)
+
+// EventSearch regroupe les filtres de la recherche d'événements ; les champs vides sont ignorés
+type EventSearch struct {
+	// Query est recherché dans le nom et la description
+	Query         string
+	CategoryID    string
+	AssociationID string
+	// From vaut par défaut le début de la journée ; To borne la date de début des événements
+	From *time.Time
+	To   *time.Time
+	// Location est recherché dans le lieu de l'événement, le nom, la ville et le code postal de son adresse
+	Location     string
+	HasFreeSeats bool
+	// Attending restreint aux événements auxquels viewer est inscrit
+	Attending bool
+	Sort      string
+}
+
+// IsValidEventSort indique si le tri demandé est pris en charge ; vide, le tri se fait par date
+func IsValidEventSort(value string) bool {
+	switch value {
+	case "", EventSortDate, EventSortDateDesc, EventSortPopularity:
+		return true
+	}
+	return false
+}
+
+// GetEvents renvoie les événements à venir que viewer peut consulter (nil pour un visiteur anonyme) dont le nom
+// ou la description contient search
+func (s *EventService) GetEvents(viewer *models.User, pagination utils.Pagination, search *string) (*utils.Pagination, error) {
+	var filters EventSearch
+	if search != nil {
+		filters.Query = *search
+	}
+	return s.SearchEvents(viewer, filters, pagination)
+}
+
+// SearchEvents renvoie les événements visibles par viewer correspondant aux filtres. Les séries récurrentes sont
+// dépliées entre From et To (l'horizon habituel à défaut) : chaque occurrence est filtrée et triée séparément.
+func (s *EventService) SearchEvents(viewer *models.User, filters EventSearch, pagination utils.Pagination) (*utils.Pagination, error) {
+	from := utils.StartOfDay(time.Now())
+	if filters.From != nil {
+		from = *filters.From
+	}
+	to := from.Add(RecurrenceHorizon)
+	if filters.To != nil {
+		to = *filters.To
+	}
+
+	query := database.CurrentDatabase.
+		Preload("Category").
+		Preload("Association").
+		Preload("Venue").
+		Preload("RecurrenceExceptions").
+		Scopes(VisibleEventsScope(viewer)).
+		Where("events.recurrence_parent_id IS NULL").
+		Where("("+eventEndsAtSQL+" >= ? OR events.recurrence_rule <> '')", from).
+		Where("events.date <= ?", to)
+
+	if filters.Query != "" {
+		pattern := "%" + strings.ToLower(filters.Query) + "%"
+		query = query.Where("LOWER(events.name) LIKE ? OR LOWER(events.description) LIKE ?", pattern, pattern)
+	}
+	if filters.CategoryID != "" {
+		query = query.Where("events.category_id = ?", filters.CategoryID)
+	}
+	if filters.AssociationID != "" {
+		query = query.Scopes(HostedByScope(filters.AssociationID))
+	}
+	if filters.Location != "" {
+		pattern := "%" + strings.ToLower(filters.Location) + "%"
+		query = query.Where(
+			"LOWER(events.location) LIKE ? OR events.venue_id IN "+
+				"(SELECT id FROM venues WHERE LOWER(name) LIKE ? OR LOWER(city) LIKE ? OR LOWER(postal_code) LIKE ?)",
+			pattern, pattern, pattern, pattern,
+		)
+	}
+	if filters.Attending {
+		query = query.Where(
+			"events.id IN (SELECT COALESCE(attended.recurrence_parent_id, attended.id) FROM events attended "+
+				"JOIN participations ON participations.event_id = attended.id "+
+				"WHERE participations.user_id = ? AND participations.is_attending = ?)",
+			viewerID(viewer), true,
+		)
+	}
+
+	var events []models.Event
+	if err := query.Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	expanded, err := s.ExpandOccurrences(events, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	seatsTaken, err := countSeatsTakenByEvent(expanded)
+	if err != nil {
+		return nil, err
+	}
+	attended := make(map[string]bool)
+	if filters.Attending {
+		attended, err = attendedEventIDs(viewerID(viewer), expanded)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]models.Event, 0, len(expanded))
+	for _, event := range expanded {
+		if event.EndsAt().Before(from) || event.Date.After(to) {
+			continue
+		}
+		seats := seatsTaken[occurrenceKey(&event)]
+		if filters.HasFreeSeats && (event.IsCancelled() || event.IsFull(seats)) {
+			continue
+		}
+		if filters.Attending && !attended[occurrenceKey(&event)] {
+			continue
+		}
+		results = append(results, event)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		switch filters.Sort {
+		case EventSortDateDesc:
+			return results[i].Date.After(results[j].Date)
+		case EventSortPopularity:
+			seatsI, seatsJ := seatsTaken[occurrenceKey(&results[i])], seatsTaken[occurrenceKey(&results[j])]
+			if seatsI != seatsJ {
+				return seatsI > seatsJ
+			}
+		}
+		return results[i].Date.Before(results[j].Date)
+	})
+
+	pagination.Rows = utils.PaginateSlice(results, &pagination)
+	return &pagination, nil
+}
+
+// occurrenceKey identifie un événement déplié : son identifiant, vide pour une occurrence virtuelle
+// qui porte encore celui de sa série et n'a donc aucune participation
+func occurrenceKey(event *models.Event) string {
+	if event.RecurrenceParentID != nil && event.ID == *event.RecurrenceParentID {
+		return ""
+	}
+	return event.ID
+}
+
+// countSeatsTakenByEvent compte, pour chaque événement enregistré, les participants occupant une place
+func countSeatsTakenByEvent(events []models.Event) (map[string]int64, error) {
+	ids := eventKeys(events)
+	counts := make(map[string]int64, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		EventID string
+		Count   int64
+	}
+	err := database.CurrentDatabase.Model(&models.Participation{}).
+		Select("event_id, COUNT(*) AS count").
+		Where("event_id IN ? AND is_attending = ?", ids, true).
+		Group("event_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.EventID] = row.Count
+	}
+	return counts, nil
+}
+
+// attendedEventIDs renvoie les événements enregistrés auxquels l'utilisateur est inscrit
+func attendedEventIDs(userID string, events []models.Event) (map[string]bool, error) {
+	attended := make(map[string]bool)
+	ids := eventKeys(events)
+	if len(ids) == 0 {
+		return attended, nil
+	}
+
+	var eventIDs []string
+	err := database.CurrentDatabase.Model(&models.Participation{}).
+		Where("event_id IN ? AND user_id = ? AND is_attending = ?", ids, userID, true).
+		Pluck("event_id", &eventIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range eventIDs {
+		attended[id] = true
+	}
+	return attended, nil
+}
+
+func eventKeys(events []models.Event) []string {
+	ids := make([]string, 0, len(events))
+	for i := range events {
+		if key := occurrenceKey(&events[i]); key != "" {
+			ids = append(ids, key)
+		}
+	}
+	return ids
+}
+
+func viewerID(viewer *models.User) string {
+	if viewer == nil {
+		return ""
+	}
+	return viewer.ID
+}
@@ -2,25 +2,47 @@ package services
 
 import (
 	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
 	"backend/models"
 	"backend/utils"
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// RecurrenceHorizon limite la période sur laquelle les séries récurrentes sont dépliées à la lecture
+const RecurrenceHorizon = 180 * 24 * time.Hour
+
+// eventEndsAtSQL est l'équivalent SQL de Event.EndsAt sur la table events
+var eventEndsAtSQL = fmt.Sprintf("COALESCE(events.end_date, events.date + interval '%d seconds')", int64(models.DefaultEventDuration.Seconds()))
+
 type EventService struct {
+	notificationService *NotificationService
+	badgeService        *BadgeService
 }
 
 func NewEventService() *EventService {
-	return &EventService{}
+	return &EventService{
+		notificationService: NewNotificationService(),
+		badgeService:        NewBadgeService(),
+	}
 }
 
 func (s *EventService) AddEvent(event *models.Event) (*models.Event, error) {
-	validate := validator.New(validator.WithRequiredStructEnabled())
-
-	err := validate.Struct(event)
-	if err != nil {
+	if err := validateNewEvent(event); err != nil {
+		return nil, err
+	}
+	if err := applyVenue(database.CurrentDatabase, event); err != nil {
+		return nil, err
+	}
+	if err := preparePublication(event, time.Now()); err != nil {
 		return nil, err
 	}
 
@@ -29,66 +51,740 @@ func (s *EventService) AddEvent(event *models.Event) (*models.Event, error) {
 		return nil, create.Error
 	}
 
-	return event, nil
-}
-
-func (s *EventService) GetEvents(pagination utils.Pagination, search *string) (*utils.Pagination, error) {
-	var events []models.Event
-	query := database.CurrentDatabase
-
-	if search != nil && *search != "" {
-		query = query.Where("LOWER(name) LIKE ? OR LOWER(description) LIKE ?",
-			"%"+strings.ToLower(*search)+"%",
-			"%"+strings.ToLower(*search)+"%",
-		)
-	}
-
-	err := query.Scopes(utils.Paginate(events, &pagination, query)).
-		Order("date asc").
-		Find(&events).Error
-	if err != nil {
-		return nil, err
+	if event.IsPublished() {
+		published := *event
+		go s.notifyPublication(&published)
 	}
 
-	pagination.Rows = events
-	return &pagination, nil
+	return event, nil
 }
 
 func (s *EventService) GetEventById(id string) (*models.Event, error) {
 	var event models.Event
-	if err := database.CurrentDatabase.Preload("Category").Preload("Association").First(&event, "id = ?", id).Error; err != nil {
+	if err := database.CurrentDatabase.Preload("Category").Preload("Association").Preload("Venue").Preload("CoverPhoto").Preload("RecurrenceExceptions").
+		Preload("CoHosts", "status = ?", enums.Accepted).Preload("CoHosts.Association").
+		First(&event, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &event, nil
 }
 
+// IsEventLeader indique si l'utilisateur peut gérer l'événement et ses participations : administrateur,
+// responsable de l'association organisatrice ou d'une association co-organisatrice
+func (s *EventService) IsEventLeader(user *models.User, event *models.Event) (bool, error) {
+	if user == nil {
+		return false, nil
+	}
+	if enums.IsAdmin(user.Role) {
+		return true, nil
+	}
+
+	var count int64
+	err := database.CurrentDatabase.Model(&models.Association{}).
+		Where("owner_id = ?", user.ID).
+		Where("id = ? OR id IN (SELECT association_id FROM event_co_hosts WHERE event_id = ? AND status = ?)",
+			event.AssociationID, event.FormEventID(), enums.Accepted).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func (s *EventService) UpdateEvent(event *models.Event) error {
 	var existingEvent models.Event
 	if err := database.CurrentDatabase.First(&existingEvent, "id = ?", event.ID).Error; err != nil {
 		return err
 	}
 
-	if err := database.CurrentDatabase.Model(&existingEvent).Updates(map[string]interface{}{
-		"name":           event.Name,
-		"description":    event.Description,
-		"date":           event.Date,
-		"location":       event.Location,
-		"category_id":    event.CategoryID,
-		"association_id": event.AssociationID,
-	}).Error; err != nil {
+	if event.Visibility == "" {
+		event.Visibility = existingEvent.Visibility
+	}
+	if err := validateSchedule(event); err != nil {
+		return err
+	}
+	if event.AssociationID == "" {
+		event.AssociationID = existingEvent.AssociationID
+	}
+	if err := applyVenue(database.CurrentDatabase, event); err != nil {
+		return err
+	}
+
+	// Une occurrence ne peut pas devenir elle-même une série
+	if event.RecurrenceRule != "" {
+		if existingEvent.RecurrenceParentID != nil {
+			return coreErrors.ErrInvalidRecurrenceRule
+		}
+
+		rule, err := utils.NormalizeRecurrenceRule(event.RecurrenceRule, recurrenceStart(event))
+		if err != nil {
+			return coreErrors.ErrInvalidRecurrenceRule
+		}
+		event.RecurrenceRule = rule
+	}
+
+	var promoted []models.Participation
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&existingEvent, "id = ?", event.ID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&existingEvent).Updates(map[string]interface{}{
+			"name":            event.Name,
+			"description":     event.Description,
+			"date":            event.Date,
+			"end_date":        event.EndDate,
+			"time_zone":       event.TimeZone,
+			"location":        event.Location,
+			"venue_id":        event.VenueID,
+			"capacity":        event.Capacity,
+			"visibility":      event.Visibility,
+			"category_id":     event.CategoryID,
+			"association_id":  event.AssociationID,
+			"recurrence_rule": event.RecurrenceRule,
+		}).Error; err != nil {
+			return err
+		}
+
+		// Les occurrences matérialisées d'une série suivent sa visibilité
+		if existingEvent.IsRecurring() {
+			if err := tx.Model(&models.Event{}).Where("recurrence_parent_id = ?", event.ID).Update("visibility", event.Visibility).Error; err != nil {
+				return err
+			}
+		}
+
+		// Une capacité augmentée ou supprimée libère des places pour la liste d'attente
+		var err error
+		promoted, err = promoteFromWaitlist(tx, event)
+		return err
+	})
+	if err != nil {
 		return err
 	}
 
+	if len(promoted) > 0 {
+		go s.notificationService.NotifyWaitlistPromotion(event, promoted)
+	}
+
 	return nil
 }
 
 func (s *EventService) DeleteEvent(id string) error {
-	if err := database.CurrentDatabase.Delete(&models.Event{}, "ID = ?", id).Error; err != nil {
+	var photos []models.EventPhoto
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		// Une série emporte ses exceptions et ses occurrences matérialisées
+		instanceIDs := tx.Model(&models.Event{}).Select("id").Where("recurrence_parent_id = ?", id)
+		if err := tx.Where("event_id IN (?) OR event_id = ?", instanceIDs, id).Find(&photos).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.EventPhoto{}, "event_id IN (?) OR event_id = ?", instanceIDs, id).Error; err != nil {
+			return err
+		}
+		if err := deleteParticipations(tx, "event_id IN (?) OR event_id = ?", instanceIDs, id); err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.EventQuestion{}, "event_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.EventInvitation{}, "event_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.EventCoHost{}, "event_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.EventComment{}, "event_id = ?", id).Error; err != nil {
+			return err
+		}
+		shifts := tx.Model(&models.EventShift{}).Select("id").Where("event_id IN (?) OR event_id = ?", instanceIDs, id)
+		if err := tx.Delete(&models.ShiftSignup{}, "shift_id IN (?)", shifts).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.EventShift{}, "event_id IN (?) OR event_id = ?", instanceIDs, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.EventFeedback{}, "event_id IN (?) OR event_id = ?", instanceIDs, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.EventRecurrenceException{}, "event_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Event{}, "recurrence_parent_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Event{}, "ID = ?", id).Error
+	})
+	if err != nil {
 		return err
 	}
+
+	NewEventPhotoService().removePhotoFiles(photos)
 	return nil
 }
 
+// CancelEvent annule l'événement sans le supprimer et prévient ses participants. Pour une série,
+// toutes les occurrences sont annulées.
+func (s *EventService) CancelEvent(event *models.Event, reason string) error {
+	event.Status = enums.EventCancelled
+	event.StatusReason = reason
+
+	participations, err := s.changeEventStatus(event, map[string]interface{}{
+		"status":        event.Status,
+		"status_reason": event.StatusReason,
+	})
+	if err != nil {
+		return err
+	}
+
+	go s.notificationService.NotifyEventCancelled(event, participations)
+	return nil
+}
+
+// RescheduleEvent reprogramme l'événement à newDate et prévient ses participants. Sans nouvelle date,
+// l'événement est marqué reporté. Une série ne peut être que reportée : ses occurrences se
+// reprogramment une par une.
+func (s *EventService) RescheduleEvent(event *models.Event, newDate *time.Time, reason string) error {
+	changes := map[string]interface{}{"status_reason": reason}
+	if newDate == nil {
+		event.Status = enums.EventPostponed
+	} else {
+		if event.IsRecurring() {
+			return coreErrors.ErrInvalidOccurrence
+		}
+		// La durée de l'événement est conservée
+		if event.EndDate != nil {
+			endDate := newDate.Add(event.Duration())
+			event.EndDate = &endDate
+			changes["end_date"] = event.EndDate
+		}
+		event.Status = enums.EventScheduled
+		event.Date = *newDate
+		changes["date"] = event.Date
+	}
+	event.StatusReason = reason
+	changes["status"] = event.Status
+
+	participations, err := s.changeEventStatus(event, changes)
+	if err != nil {
+		return err
+	}
+
+	go s.notificationService.NotifyEventRescheduled(event, participations)
+	return nil
+}
+
+// changeEventStatus applique les changements à l'événement, et à ses occurrences matérialisées pour une série,
+// puis renvoie les participations à prévenir (une par utilisateur) avec leur utilisateur
+func (s *EventService) changeEventStatus(event *models.Event, changes map[string]interface{}) ([]models.Participation, error) {
+	var participations []models.Participation
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Event{}).Where("id = ?", event.ID).Updates(changes).Error; err != nil {
+			return err
+		}
+
+		// Les occurrences passées d'une série gardent leur statut
+		now := time.Now()
+		occurrenceIDs := tx.Model(&models.Event{}).Select("id").Where("recurrence_parent_id = ? AND date >= ?", event.ID, now)
+		if event.IsRecurring() {
+			occurrenceChanges := map[string]interface{}{
+				"status":        changes["status"],
+				"status_reason": changes["status_reason"],
+			}
+			if err := tx.Model(&models.Event{}).Where("recurrence_parent_id = ? AND date >= ?", event.ID, now).Updates(occurrenceChanges).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Preload("User").
+			Where("event_id = ? OR event_id IN (?)", event.ID, occurrenceIDs).
+			Where("is_attending = ? OR status = ?", true, enums.ParticipationWaitlisted).
+			Order("created_at").
+			Find(&participations).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(participations))
+	unique := participations[:0]
+	for _, participation := range participations {
+		if !seen[participation.UserID] {
+			seen[participation.UserID] = true
+			unique = append(unique, participation)
+		}
+	}
+	return unique, nil
+}
+
+// validateNewEvent applique les contrôles d'un nouvel événement (champs, horaires, fuseau horaire)
+// et normalise sa règle de récurrence
+func validateNewEvent(event *models.Event) error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(event); err != nil {
+		return err
+	}
+	if err := validateSchedule(event); err != nil {
+		return err
+	}
+
+	if event.RecurrenceRule != "" {
+		rule, err := utils.NormalizeRecurrenceRule(event.RecurrenceRule, recurrenceStart(event))
+		if err != nil {
+			return coreErrors.ErrInvalidRecurrenceRule
+		}
+		event.RecurrenceRule = rule
+	}
+	return nil
+}
+
+// validateSchedule vérifie que l'événement se termine après son début
+func validateSchedule(event *models.Event) error {
+	if event.EndDate != nil && !event.EndDate.After(event.Date) {
+		return coreErrors.ErrInvalidEventSchedule
+	}
+	return nil
+}
+
+// recurrenceStart renvoie le DTSTART de la série, exprimé dans le fuseau horaire de l'événement (celui du calendrier
+// à défaut) pour que les règles hebdomadaires conservent l'heure locale lors des changements d'heure
+func recurrenceStart(event *models.Event) time.Time {
+	return event.Date.In(event.TimeLocation(utils.CalendarLocation()))
+}
+
+// occurrenceEndDate renvoie la fin d'une occurrence de la série, qui garde la durée de la série
+func occurrenceEndDate(series *models.Event, occurrence time.Time) *time.Time {
+	if series.EndDate == nil {
+		return nil
+	}
+	endDate := occurrence.Add(series.Duration())
+	return &endDate
+}
+
+// ExpandOccurrences remplace chaque série récurrente par ses occurrences comprises entre from et to.
+// Les occurrences matérialisées (modifiées ou portant des participations) sont renvoyées telles
+// quelles, les autres sont des copies de la série datées de l'occurrence.
+func (s *EventService) ExpandOccurrences(events []models.Event, from, to time.Time) ([]models.Event, error) {
+	var seriesIDs []string
+	for _, event := range events {
+		if event.IsRecurring() {
+			seriesIDs = append(seriesIDs, event.ID)
+		}
+	}
+
+	instancesBySeries := make(map[string]map[int64]models.Event)
+	if len(seriesIDs) > 0 {
+		var instances []models.Event
+		err := database.CurrentDatabase.
+			Preload("Participations").
+			Preload("Category").
+			Preload("Association").
+			Preload("Venue").
+			Where("recurrence_parent_id IN ?", seriesIDs).
+			Find(&instances).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instance := range instances {
+			if instancesBySeries[*instance.RecurrenceParentID] == nil {
+				instancesBySeries[*instance.RecurrenceParentID] = make(map[int64]models.Event)
+			}
+			instancesBySeries[*instance.RecurrenceParentID][instance.RecurrenceID.Unix()] = instance
+		}
+	}
+
+	seen := make(map[string]bool)
+	expanded := make([]models.Event, 0, len(events))
+	for _, event := range events {
+		if !event.IsRecurring() {
+			if !seen[event.ID] {
+				seen[event.ID] = true
+				expanded = append(expanded, event)
+			}
+			continue
+		}
+
+		occurrences, err := utils.RecurrenceOccurrences(event.RecurrenceRule, recurrenceStart(&event), event.ExceptionDates(), from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, occurrence := range occurrences {
+			instance, ok := instancesBySeries[event.ID][occurrence.Unix()]
+			if !ok {
+				expanded = append(expanded, newVirtualOccurrence(event, occurrence))
+				continue
+			}
+			if !seen[instance.ID] {
+				seen[instance.ID] = true
+				expanded = append(expanded, instance)
+			}
+		}
+	}
+
+	sort.SliceStable(expanded, func(i, j int) bool {
+		return expanded[i].Date.Before(expanded[j].Date)
+	})
+
+	return expanded, nil
+}
+
+// newVirtualOccurrence construit une occurrence non enregistrée : elle garde l'ID de la série
+// et porte la date de l'occurrence dans recurrence_id
+func newVirtualOccurrence(series models.Event, occurrence time.Time) models.Event {
+	virtual := series
+	virtual.Date = occurrence
+	virtual.EndDate = occurrenceEndDate(&series, occurrence)
+	virtual.RecurrenceParentID = &series.ID
+	virtual.RecurrenceID = &occurrence
+	virtual.Participations = nil
+	virtual.RecurrenceExceptions = nil
+	return virtual
+}
+
+// validateOccurrence vérifie que la date est bien une occurrence non supprimée de la série
+func validateOccurrence(series *models.Event, occurrence time.Time) error {
+	if !series.IsRecurring() {
+		return coreErrors.ErrNotRecurringEvent
+	}
+
+	isOccurrence, err := utils.IsRecurrenceOccurrence(series.RecurrenceRule, recurrenceStart(series), series.ExceptionDates(), occurrence)
+	if err != nil {
+		return coreErrors.ErrInvalidRecurrenceRule
+	}
+	if !isOccurrence {
+		return coreErrors.ErrInvalidOccurrence
+	}
+
+	return nil
+}
+
+// FindOccurrence renvoie l'occurrence matérialisée de la série à cette date, ou nil si elle n'existe pas encore
+func (s *EventService) FindOccurrence(seriesID string, occurrence time.Time) (*models.Event, error) {
+	var instance models.Event
+	err := database.CurrentDatabase.
+		Where("recurrence_parent_id = ? AND recurrence_id = ?", seriesID, occurrence).
+		First(&instance).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &instance, nil
+}
+
+// GetOrCreateOccurrence matérialise une occurrence de la série pour pouvoir la modifier ou y participer
+func (s *EventService) GetOrCreateOccurrence(seriesID string, occurrence time.Time) (*models.Event, error) {
+	series, err := s.GetEventById(seriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateOccurrence(series, occurrence); err != nil {
+		return nil, err
+	}
+
+	instance, err := s.FindOccurrence(series.ID, occurrence)
+	if err != nil || instance != nil {
+		return instance, err
+	}
+
+	instance = &models.Event{
+		Name:               series.Name,
+		Description:        series.Description,
+		Date:               occurrence,
+		EndDate:            occurrenceEndDate(series, occurrence),
+		TimeZone:           series.TimeZone,
+		Location:           series.Location,
+		VenueID:            series.VenueID,
+		Capacity:           series.Capacity,
+		Visibility:         series.Visibility,
+		Status:             series.Status,
+		StatusReason:       series.StatusReason,
+		PublicationStatus:  series.PublicationStatus,
+		PublishAt:          series.PublishAt,
+		PublishedAt:        series.PublishedAt,
+		CategoryID:         series.CategoryID,
+		AssociationID:      series.AssociationID,
+		RecurrenceParentID: &series.ID,
+		RecurrenceID:       &occurrence,
+	}
+
+	// Une autre requête a pu matérialiser la même occurrence entre-temps
+	if err := database.CurrentDatabase.Clauses(clause.OnConflict{DoNothing: true}).Create(instance).Error; err != nil {
+		return nil, err
+	}
+
+	return s.FindOccurrence(series.ID, occurrence)
+}
+
+// UpdateFollowingOccurrences applique les modifications à l'occurrence donnée et à toutes les suivantes :
+// la série est coupée et une nouvelle série reprend à partir de changes.Date
+func (s *EventService) UpdateFollowingOccurrences(series *models.Event, occurrence time.Time, changes *models.Event) (*models.Event, error) {
+	if err := validateOccurrence(series, occurrence); err != nil {
+		return nil, err
+	}
+	if err := validateSchedule(changes); err != nil {
+		return nil, err
+	}
+
+	// Une nouvelle règle remplace celle de la série à partir de l'occurrence ; la règle de la série renvoyée
+	// telle quelle n'est pas un changement, la suite de la série gardant alors ses occurrences restantes
+	if changes.RecurrenceRule != "" {
+		rule, err := utils.NormalizeRecurrenceRule(changes.RecurrenceRule, recurrenceStart(changes))
+		if err != nil {
+			return nil, coreErrors.ErrInvalidRecurrenceRule
+		}
+		changes.RecurrenceRule = rule
+		if rule == series.RecurrenceRule {
+			changes.RecurrenceRule = ""
+		}
+	}
+
+	if occurrence.Equal(series.Date) {
+		changes.ID = series.ID
+		if changes.RecurrenceRule == "" {
+			changes.RecurrenceRule = series.RecurrenceRule
+		}
+		return changes, s.UpdateEvent(changes)
+	}
+
+	before, after, err := utils.SplitRecurrenceRule(series.RecurrenceRule, recurrenceStart(series), occurrence)
+	if err != nil {
+		return nil, coreErrors.ErrInvalidRecurrenceRule
+	}
+	if changes.RecurrenceRule != "" {
+		after = changes.RecurrenceRule
+	}
+
+	newSeries := &models.Event{
+		Name:              changes.Name,
+		Description:       changes.Description,
+		Date:              changes.Date,
+		EndDate:           changes.EndDate,
+		TimeZone:          changes.TimeZone,
+		Location:          changes.Location,
+		VenueID:           changes.VenueID,
+		Capacity:          changes.Capacity,
+		Visibility:        changes.Visibility,
+		Status:            changes.Status,
+		StatusReason:      changes.StatusReason,
+		PublicationStatus: series.PublicationStatus,
+		PublishAt:         series.PublishAt,
+		PublishedAt:       series.PublishedAt,
+		CategoryID:        changes.CategoryID,
+		AssociationID:     series.AssociationID,
+		RecurrenceRule:    after,
+	}
+	if err := applyVenue(database.CurrentDatabase, newSeries); err != nil {
+		return nil, err
+	}
+	shift := fmt.Sprintf("%d seconds", int64(changes.Date.Sub(occurrence).Seconds()))
+
+	// Les occurrences déplacées prennent la durée de la nouvelle série
+	var endDate interface{}
+	if newSeries.EndDate != nil {
+		endDate = gorm.Expr("date + ?::interval + ?::interval", shift, fmt.Sprintf("%d seconds", int64(newSeries.Duration().Seconds())))
+	}
+
+	err = database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Event{}).Where("id = ?", series.ID).Update("recurrence_rule", before).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(newSeries).Error; err != nil {
+			return err
+		}
+
+		// La nouvelle série reprend la liste des invités
+		var invitations []models.EventInvitation
+		if err := tx.Where("event_id = ?", series.ID).Find(&invitations).Error; err != nil {
+			return err
+		}
+		for i := range invitations {
+			invitations[i].EventID = newSeries.ID
+		}
+		if len(invitations) > 0 {
+			if err := tx.Create(&invitations).Error; err != nil {
+				return err
+			}
+		}
+
+		// ainsi que ses associations co-organisatrices
+		var coHosts []models.EventCoHost
+		if err := tx.Where("event_id = ?", series.ID).Find(&coHosts).Error; err != nil {
+			return err
+		}
+		for i := range coHosts {
+			coHosts[i].EventID = newSeries.ID
+		}
+		if len(coHosts) > 0 {
+			if err := tx.Create(&coHosts).Error; err != nil {
+				return err
+			}
+		}
+
+		// Les occurrences matérialisées et les exceptions suivantes passent sur la nouvelle série
+		if err := tx.Model(&models.Event{}).
+			Where("recurrence_parent_id = ? AND recurrence_id >= ?", series.ID, occurrence).
+			Updates(map[string]interface{}{
+				"recurrence_parent_id": newSeries.ID,
+				"recurrence_id":        gorm.Expr("recurrence_id + ?::interval", shift),
+				"date":                 gorm.Expr("date + ?::interval", shift),
+				"end_date":             endDate,
+				"time_zone":            newSeries.TimeZone,
+				"name":                 newSeries.Name,
+				"description":          newSeries.Description,
+				"location":             newSeries.Location,
+				"venue_id":             newSeries.VenueID,
+				"visibility":           newSeries.Visibility,
+				"category_id":          newSeries.CategoryID,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.EventRecurrenceException{}).
+			Where("event_id = ? AND occurrence_date >= ?", series.ID, occurrence).
+			Updates(map[string]interface{}{
+				"event_id":        newSeries.ID,
+				"occurrence_date": gorm.Expr("occurrence_date + ?::interval", shift),
+			}).Error; err != nil {
+			return err
+		}
+
+		if changes.RecurrenceRule != "" {
+			return dropStaleOccurrences(tx, newSeries)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newSeries, nil
+}
+
+// dropStaleOccurrences supprime les occurrences matérialisées et les exceptions reprises par une série dont la
+// règle a changé, lorsqu'elles ne tombent plus sur une occurrence de la nouvelle règle
+func dropStaleOccurrences(tx *gorm.DB, series *models.Event) error {
+	isOccurrence := func(date time.Time) (bool, error) {
+		matches, err := utils.IsRecurrenceOccurrence(series.RecurrenceRule, recurrenceStart(series), nil, date)
+		if err != nil {
+			return false, coreErrors.ErrInvalidRecurrenceRule
+		}
+		return matches, nil
+	}
+
+	var instances []models.Event
+	if err := tx.Select("id", "recurrence_id").Where("recurrence_parent_id = ?", series.ID).Find(&instances).Error; err != nil {
+		return err
+	}
+	var staleDates []time.Time
+	for _, instance := range instances {
+		matches, err := isOccurrence(*instance.RecurrenceID)
+		if err != nil {
+			return err
+		}
+		if !matches {
+			staleDates = append(staleDates, *instance.RecurrenceID)
+		}
+	}
+	if len(staleDates) > 0 {
+		if err := deleteOccurrencesFrom(tx, series.ID, "recurrence_id IN ?", staleDates); err != nil {
+			return err
+		}
+	}
+
+	var exceptions []models.EventRecurrenceException
+	if err := tx.Where("event_id = ?", series.ID).Find(&exceptions).Error; err != nil {
+		return err
+	}
+	for _, exception := range exceptions {
+		matches, err := isOccurrence(exception.OccurrenceDate)
+		if err != nil {
+			return err
+		}
+		if !matches {
+			if err := tx.Delete(&exception).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteOccurrence supprime une seule occurrence de la série en l'ajoutant aux exceptions
+func (s *EventService) DeleteOccurrence(series *models.Event, occurrence time.Time) error {
+	if err := validateOccurrence(series, occurrence); err != nil {
+		return err
+	}
+
+	return database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := deleteOccurrencesFrom(tx, series.ID, "recurrence_id = ?", occurrence); err != nil {
+			return err
+		}
+
+		return tx.Create(&models.EventRecurrenceException{
+			EventID:        series.ID,
+			OccurrenceDate: occurrence,
+		}).Error
+	})
+}
+
+// DeleteFollowingOccurrences supprime l'occurrence donnée et toutes les suivantes en arrêtant la série
+func (s *EventService) DeleteFollowingOccurrences(series *models.Event, occurrence time.Time) error {
+	if err := validateOccurrence(series, occurrence); err != nil {
+		return err
+	}
+
+	if occurrence.Equal(series.Date) {
+		return s.DeleteEvent(series.ID)
+	}
+
+	before, _, err := utils.SplitRecurrenceRule(series.RecurrenceRule, recurrenceStart(series), occurrence)
+	if err != nil {
+		return coreErrors.ErrInvalidRecurrenceRule
+	}
+
+	return database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := deleteOccurrencesFrom(tx, series.ID, "recurrence_id >= ?", occurrence); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&models.EventRecurrenceException{}, "event_id = ? AND occurrence_date >= ?", series.ID, occurrence).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Event{}).Where("id = ?", series.ID).Update("recurrence_rule", before).Error
+	})
+}
+
+// deleteOccurrencesFrom supprime les occurrences matérialisées de la série correspondant à la condition
+func deleteOccurrencesFrom(tx *gorm.DB, seriesID string, condition string, args ...interface{}) error {
+	instances := tx.Model(&models.Event{}).Select("id").
+		Where("recurrence_parent_id = ?", seriesID).
+		Where(condition, args...)
+
+	if err := deleteParticipations(tx, "event_id IN (?)", instances); err != nil {
+		return err
+	}
+	if err := tx.Delete(&models.EventFeedback{}, "event_id IN (?)", instances).Error; err != nil {
+		return err
+	}
+	if err := tx.Delete(&models.EventPhoto{}, "event_id IN (?)", instances).Error; err != nil {
+		return err
+	}
+
+	return tx.Where("recurrence_parent_id = ?", seriesID).Where(condition, args...).Delete(&models.Event{}).Error
+}
+
+// deleteParticipations supprime les participations correspondant à la condition, avec leurs réponses au formulaire
+func deleteParticipations(tx *gorm.DB, condition string, args ...interface{}) error {
+	participationIDs := tx.Model(&models.Participation{}).Select("id").Where(condition, args...)
+	if err := tx.Delete(&models.ParticipationAnswer{}, "participation_id IN (?)", participationIDs).Error; err != nil {
+		return err
+	}
+
+	return tx.Where(condition, args...).Delete(&models.Participation{}).Error
+}
+
 func (s *EventService) GetEventParticipations(eventID string, pagination utils.Pagination, status *string) (*utils.Pagination, error) {
 	var participations []models.Participation
 
@@ -124,36 +820,137 @@ func (s *EventService) GetUserEventParticipation(eventID string, userID string,
 		return nil
 	}
 
+	if participation.Status == enums.ParticipationWaitlisted {
+		_ = setWaitlistPosition(database.CurrentDatabase, &participation)
+	}
+
 	return &participation
 }
 
+// ChangeUserEventAttend inscrit ou désinscrit l'utilisateur. Si l'événement est complet, l'inscription
+// est placée en liste d'attente ; une désinscription libère la place pour le premier de la liste.
+// La ligne de l'événement est verrouillée pendant la transaction pour que des inscriptions simultanées
+// ne dépassent jamais la capacité.
 func (s *EventService) ChangeUserEventAttend(isAttending bool, eventID string, userID string) (*models.Participation, error) {
-	participation := s.GetUserEventParticipation(eventID, userID)
+	return s.ChangeUserEventAttendWithAnswers(isAttending, eventID, userID, nil)
+}
+
+// ChangeUserEventAttendWithAnswers inscrit l'utilisateur avec ses réponses au formulaire d'inscription.
+// Les réponses sont validées à l'inscription ; envoyées par un utilisateur déjà inscrit, elles remplacent
+// les précédentes. Des réponses invalides renvoient une *AnswersValidationError.
+func (s *EventService) ChangeUserEventAttendWithAnswers(isAttending bool, eventID string, userID string, inputs []ParticipationAnswerInput) (*models.Participation, error) {
+	var event models.Event
+	var participation *models.Participation
+	var promoted []models.Participation
+
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, "id = ?", eventID).Error; err != nil {
+			return err
+		}
+
+		var existing models.Participation
+		err := tx.Where("event_id = ? AND user_id = ?", eventID, userID).First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil {
+			participation = &existing
+		}
+
+		// Les désinscriptions restent possibles après l'annulation, pas les inscriptions
+		if isAttending && event.IsCancelled() {
+			return coreErrors.ErrEventCancelled
+		}
+		if isAttending && !event.IsPublished() {
+			return coreErrors.ErrEventNotPublished
+		}
+
+		if !isAttending {
+			if participation == nil {
+				return nil
+			}
+			// Si on veut se désinscrire et que la participation existe, on la supprime
+			if err := deleteParticipations(tx, "id = ?", participation.ID); err != nil {
+				return err
+			}
+			participation = nil
+
+			promoted, err = promoteFromWaitlist(tx, &event)
+			return err
+		}
+
+		alreadyRegistered := participation != nil && (participation.IsAttending || participation.Status == enums.ParticipationWaitlisted)
+		if alreadyRegistered && inputs == nil {
+			return nil
+		}
 
-	if !isAttending && participation != nil {
-		// Si on veut se désinscrire et que la participation existe, on la supprime
-		err := database.CurrentDatabase.Delete(&participation).Error
+		questions, err := findQuestions(tx, event.FormEventID())
 		if err != nil {
-			return nil, err
+			return err
+		}
+		answers, err := ValidateAnswers(questions, inputs)
+		if err != nil {
+			return err
 		}
-		return nil, nil
-	}
 
-	if participation == nil {
-		// Création d'une nouvelle participation
-		participation = &models.Participation{
-			EventID:     eventID,
-			UserID:      userID,
-			IsAttending: true,
+		if alreadyRegistered {
+			participation.Answers = answers
+			return replaceAnswers(tx, participation.ID, answers)
+		}
+
+		seatsTaken, err := countSeatsTaken(tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		if participation == nil {
+			participation = &models.Participation{
+				EventID: eventID,
+				UserID:  userID,
+			}
+		}
+
+		if event.IsFull(seatsTaken) {
+			now := time.Now()
+			participation.IsAttending = false
+			participation.Status = enums.ParticipationWaitlisted
+			participation.WaitlistedAt = &now
+		} else {
+			participation.IsAttending = true
+			if participation.Status == "" || participation.Status == enums.ParticipationDeclined {
+				participation.Status = enums.ParticipationPending
+			}
+		}
+
+		if participation.ID == "" {
+			err = tx.Create(participation).Error
+		} else {
+			err = tx.Save(participation).Error
 		}
-		err := database.CurrentDatabase.Create(&participation).Error
 		if err != nil {
+			return err
+		}
+
+		participation.Answers = answers
+		return replaceAnswers(tx, participation.ID, answers)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(promoted) > 0 {
+		go s.notificationService.NotifyWaitlistPromotion(&event, promoted)
+	}
+
+	if participation != nil && participation.Status == enums.ParticipationWaitlisted {
+		if err := setWaitlistPosition(database.CurrentDatabase, participation); err != nil {
 			return nil, err
 		}
-	} else {
-		// Mise à jour de la participation existante
-		participation.IsAttending = true
-		err := database.CurrentDatabase.Save(&participation).Error
+	}
+
+	// Le chevauchement avec un autre événement n'empêche pas l'inscription, il est seulement signalé
+	if participation != nil {
+		participation.ScheduleConflicts, err = s.FindAttendanceConflicts(userID, &event)
 		if err != nil {
 			return nil, err
 		}
@@ -162,6 +959,147 @@ func (s *EventService) ChangeUserEventAttend(isAttending bool, eventID string, u
 	return participation, nil
 }
 
+// FindAttendanceConflicts renvoie les événements non annulés auxquels l'utilisateur est inscrit
+// et qui chevauchent l'événement donné
+func (s *EventService) FindAttendanceConflicts(userID string, event *models.Event) ([]models.Event, error) {
+	var events []models.Event
+	err := database.CurrentDatabase.
+		Joins("JOIN participations ON participations.event_id = events.id").
+		Where("participations.user_id = ? AND participations.is_attending = ?", userID, true).
+		Where("events.id <> ? AND events.status <> ?", event.ID, enums.EventCancelled).
+		Where("events.date < ? AND "+eventEndsAtSQL+" > ?", event.EndsAt(), event.Date).
+		Order("events.date").
+		Find(&events).Error
+	return events, err
+}
+
+// FindLocationConflicts renvoie les événements non annulés prévus au même lieu sur un créneau qui chevauche
+// celui de l'événement. Pour une série, seule sa première occurrence est comparée ; les séries enregistrées
+// sont dépliées pour trouver leurs occurrences concernées.
+func (s *EventService) FindLocationConflicts(event *models.Event) ([]models.Event, error) {
+	location := strings.TrimSpace(event.Location)
+	if location == "" {
+		return nil, nil
+	}
+
+	// L'événement, sa série et les occurrences d'une même série ne sont pas en conflit entre eux
+	seriesID := event.FormEventID()
+	sameLocation := database.CurrentDatabase.
+		Where("LOWER(TRIM(events.location)) = LOWER(?)", location).
+		Where("events.status <> ?", enums.EventCancelled).
+		Where("events.id <> ? AND events.id <> ?", event.ID, seriesID).
+		Where("events.recurrence_parent_id IS NULL OR events.recurrence_parent_id <> ?", seriesID)
+
+	var conflicts []models.Event
+	err := sameLocation.Session(&gorm.Session{}).
+		Where("events.recurrence_rule = '' OR events.recurrence_parent_id IS NOT NULL").
+		Where("events.date < ? AND "+eventEndsAtSQL+" > ?", event.EndsAt(), event.Date).
+		Order("events.date").
+		Find(&conflicts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var series []models.Event
+	err = sameLocation.Session(&gorm.Session{}).
+		Preload("RecurrenceExceptions").
+		Where("events.recurrence_rule <> '' AND events.recurrence_parent_id IS NULL").
+		Where("events.date < ?", event.EndsAt()).
+		Find(&series).Error
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(conflicts))
+	for _, conflict := range conflicts {
+		seen[conflict.ID] = true
+	}
+	for _, candidate := range series {
+		occurrences, err := s.ExpandOccurrences([]models.Event{candidate}, event.Date.Add(-candidate.Duration()), event.EndsAt())
+		if err != nil {
+			return nil, err
+		}
+		for _, occurrence := range occurrences {
+			if occurrence.IsCancelled() || !occurrence.Overlaps(event.Date, event.EndsAt()) || !strings.EqualFold(strings.TrimSpace(occurrence.Location), location) {
+				continue
+			}
+			// Les occurrences matérialisées ont déjà été trouvées par la première requête
+			if occurrence.ID != candidate.ID && seen[occurrence.ID] {
+				continue
+			}
+			conflicts = append(conflicts, occurrence)
+		}
+	}
+
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		return conflicts[i].Date.Before(conflicts[j].Date)
+	})
+	return conflicts, nil
+}
+
+// countSeatsTaken compte les participants occupant une place, liste d'attente exclue
+func countSeatsTaken(tx *gorm.DB, eventID string) (int64, error) {
+	var count int64
+	err := tx.Model(&models.Participation{}).
+		Where("event_id = ? AND is_attending = ?", eventID, true).
+		Count(&count).Error
+	return count, err
+}
+
+// promoteFromWaitlist inscrit, dans l'ordre d'arrivée, autant de personnes en attente que de places libres.
+// L'événement doit être verrouillé par la transaction appelante.
+func promoteFromWaitlist(tx *gorm.DB, event *models.Event) ([]models.Participation, error) {
+	seatsTaken, err := countSeatsTaken(tx, event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := tx.Preload("User").
+		Where("event_id = ? AND status = ?", event.ID, enums.ParticipationWaitlisted).
+		Order("waitlisted_at ASC, created_at ASC")
+	if event.Capacity != nil {
+		available := int64(*event.Capacity) - seatsTaken
+		if available <= 0 {
+			return nil, nil
+		}
+		query = query.Limit(int(available))
+	}
+
+	var promoted []models.Participation
+	if err := query.Find(&promoted).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range promoted {
+		promoted[i].IsAttending = true
+		promoted[i].Status = enums.ParticipationPending
+		promoted[i].WaitlistedAt = nil
+		if err := tx.Model(&promoted[i]).Updates(map[string]interface{}{
+			"is_attending":  true,
+			"status":        enums.ParticipationPending,
+			"waitlisted_at": nil,
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return promoted, nil
+}
+
+// setWaitlistPosition renseigne le rang de la participation dans la liste d'attente (à partir de 1)
+func setWaitlistPosition(db *gorm.DB, participation *models.Participation) error {
+	var ahead int64
+	err := db.Model(&models.Participation{}).
+		Where("event_id = ? AND status = ?", participation.EventID, enums.ParticipationWaitlisted).
+		Where("waitlisted_at < ? OR (waitlisted_at = ? AND created_at < ?)", participation.WaitlistedAt, participation.WaitlistedAt, participation.CreatedAt).
+		Count(&ahead).Error
+	if err != nil {
+		return err
+	}
+	participation.WaitlistPosition = int(ahead) + 1
+	return nil
+}
+
 func (s *EventService) IsUserAttendingEvent(eventID string, userID string) bool {
 	participation := s.GetUserEventParticipation(eventID, userID)
 	if participation == nil {
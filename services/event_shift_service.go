@@ -0,0 +1,324 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// roleRanks ordonne les rôles : un rôle satisfait les exigences des rôles de rang inférieur ou égal
+var roleRanks = map[enums.Role]int{
+	enums.UserRole:              1,
+	enums.AssociationLeaderRole: 2,
+	enums.AdminRole:             3,
+}
+
+type EventShiftService struct {
+	badgeService *BadgeService
+}
+
+func NewEventShiftService() *EventShiftService {
+	return &EventShiftService{
+		badgeService: NewBadgeService(),
+	}
+}
+
+// GetShifts renvoie les créneaux de l'événement par ordre chronologique, avec leur nombre d'inscrits
+func (s *EventShiftService) GetShifts(event *models.Event) ([]models.EventShift, error) {
+	var shifts []models.EventShift
+	if err := database.CurrentDatabase.Where("event_id = ?", event.ID).Order("starts_at, name").Find(&shifts).Error; err != nil {
+		return nil, err
+	}
+	if err := countSignups(shifts); err != nil {
+		return nil, err
+	}
+	return shifts, nil
+}
+
+// GetRoster renvoie les créneaux de l'événement avec leurs bénévoles, pour les responsables
+func (s *EventShiftService) GetRoster(event *models.Event) ([]models.EventShift, error) {
+	var shifts []models.EventShift
+	err := database.CurrentDatabase.
+		Preload("Signups", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		Preload("Signups.User").
+		Where("event_id = ?", event.ID).
+		Order("starts_at, name").
+		Find(&shifts).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range shifts {
+		shifts[i].SignedUp = int64(len(shifts[i].Signups))
+	}
+	return shifts, nil
+}
+
+// GetShift renvoie un créneau de l'événement
+func (s *EventShiftService) GetShift(event *models.Event, shiftID string) (*models.EventShift, error) {
+	var shift models.EventShift
+	err := database.CurrentDatabase.First(&shift, "id = ? AND event_id = ?", shiftID, event.ID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, coreErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// CreateShift ajoute un créneau à l'événement
+func (s *EventShiftService) CreateShift(event *models.Event, shift *models.EventShift) error {
+	shift.EventID = event.ID
+	if err := validateShift(shift); err != nil {
+		return err
+	}
+	return database.CurrentDatabase.Create(shift).Error
+}
+
+// UpdateShift modifie un créneau. La capacité ne peut pas descendre sous le nombre d'inscrits, et un créneau
+// déplacé ne doit chevaucher aucun autre créneau de ses bénévoles.
+func (s *EventShiftService) UpdateShift(shift *models.EventShift, input *models.EventShift) error {
+	shift.Name = input.Name
+	shift.Description = input.Description
+	shift.StartsAt = input.StartsAt
+	shift.EndsAt = input.EndsAt
+	shift.Capacity = input.Capacity
+	shift.RequiredRole = input.RequiredRole
+	if err := validateShift(shift); err != nil {
+		return err
+	}
+
+	return database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.EventShift{}, "id = ?", shift.ID).Error; err != nil {
+			return err
+		}
+
+		var signedUp int64
+		if err := tx.Model(&models.ShiftSignup{}).Where("shift_id = ?", shift.ID).Count(&signedUp).Error; err != nil {
+			return err
+		}
+		if int64(shift.Capacity) < signedUp {
+			return coreErrors.ErrShiftFull
+		}
+
+		// Verrouiller les bénévoles inscrits sérialise le contrôle avec leurs inscriptions concurrentes à d'autres créneaux
+		var volunteerIDs []string
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(&models.User{}).
+			Where("id IN (SELECT user_id FROM shift_signups WHERE shift_id = ?)", shift.ID).
+			Order("id").
+			Pluck("id", &volunteerIDs).Error
+		if err != nil {
+			return err
+		}
+
+		var overlapping int64
+		err = tx.Model(&models.ShiftSignup{}).
+			Joins("JOIN event_shifts ON event_shifts.id = shift_signups.shift_id").
+			Where("shift_signups.shift_id <> ?", shift.ID).
+			Where("shift_signups.user_id IN (SELECT user_id FROM shift_signups WHERE shift_id = ?)", shift.ID).
+			Where("event_shifts.starts_at < ? AND event_shifts.ends_at > ?", shift.EndsAt, shift.StartsAt).
+			Count(&overlapping).Error
+		if err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return coreErrors.ErrShiftOverlap
+		}
+
+		shift.SignedUp = signedUp
+		return tx.Select("name", "description", "starts_at", "ends_at", "capacity", "required_role", "updated_at").Updates(shift).Error
+	})
+}
+
+// DeleteShift supprime le créneau et les inscriptions des bénévoles
+func (s *EventShiftService) DeleteShift(shift *models.EventShift) error {
+	return database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.ShiftSignup{}, "shift_id = ?", shift.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(shift).Error
+	})
+}
+
+// SignUp inscrit l'utilisateur au créneau s'il a le rôle requis, s'il reste de la place et s'il n'est pas
+// déjà inscrit à un créneau qui le chevauche, quel que soit l'événement
+func (s *EventShiftService) SignUp(shift *models.EventShift, user *models.User) (*models.ShiftSignup, error) {
+	if !hasRequiredRole(user, shift.RequiredRole) {
+		return nil, coreErrors.ErrShiftRoleRequired
+	}
+
+	signup := &models.ShiftSignup{ShiftID: shift.ID, UserID: user.ID}
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		// Verrouiller l'utilisateur sérialise ses inscriptions, et donc le contrôle des chevauchements ;
+		// verrouiller le créneau sérialise le contrôle de la capacité
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.User{}, "id = ?", user.ID).Error; err != nil {
+			return err
+		}
+		var current models.EventShift
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, "id = ?", shift.ID).Error; err != nil {
+			return err
+		}
+
+		var existing int64
+		if err := tx.Model(&models.ShiftSignup{}).Where("shift_id = ? AND user_id = ?", shift.ID, user.ID).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return coreErrors.ErrAlreadySignedUp
+		}
+
+		var signedUp int64
+		if err := tx.Model(&models.ShiftSignup{}).Where("shift_id = ?", shift.ID).Count(&signedUp).Error; err != nil {
+			return err
+		}
+		if signedUp >= int64(current.Capacity) {
+			return coreErrors.ErrShiftFull
+		}
+
+		var overlapping int64
+		err := tx.Model(&models.ShiftSignup{}).
+			Joins("JOIN event_shifts ON event_shifts.id = shift_signups.shift_id").
+			Where("shift_signups.user_id = ?", user.ID).
+			Where("event_shifts.starts_at < ? AND event_shifts.ends_at > ?", current.EndsAt, current.StartsAt).
+			Count(&overlapping).Error
+		if err != nil {
+			return err
+		}
+		if overlapping > 0 {
+			return coreErrors.ErrShiftOverlap
+		}
+
+		return tx.Create(signup).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return signup, nil
+}
+
+// Withdraw désinscrit l'utilisateur du créneau, tant que celui-ci n'a pas été validé
+func (s *EventShiftService) Withdraw(shift *models.EventShift, userID string) error {
+	signup, err := findSignup(database.CurrentDatabase, shift.ID, userID)
+	if err != nil {
+		return err
+	}
+	if signup.CompletedAt != nil {
+		return coreErrors.ErrShiftAlreadyCompleted
+	}
+	return database.CurrentDatabase.Delete(signup).Error
+}
+
+// CompleteSignup valide le créneau effectué par le bénévole et lui attribue, comme pour une participation
+// confirmée, les points de la catégorie de l'événement. actor est le responsable validant le créneau.
+func (s *EventShiftService) CompleteSignup(event *models.Event, shift *models.EventShift, userID string, actor *models.User, now time.Time) (*models.ShiftSignup, error) {
+	var signup *models.ShiftSignup
+	var granted *models.PointsEntry
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		var err error
+		signup, err = findSignup(tx.Clauses(clause.Locking{Strength: "UPDATE"}), shift.ID, userID)
+		if err != nil {
+			return err
+		}
+		if signup.CompletedAt != nil {
+			return coreErrors.ErrShiftAlreadyCompleted
+		}
+
+		if err := tx.Model(signup).Update("completed_at", now).Error; err != nil {
+			return err
+		}
+		signup.CompletedAt = &now
+
+		var category models.Category
+		err = tx.First(&category, "id = ?", event.CategoryID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		granted = &models.PointsEntry{
+			UserID:        userID,
+			Amount:        category.Note,
+			Source:        enums.ShiftPoints,
+			Reference:     &signup.ID,
+			Reason:        event.Name + " : " + shift.Name,
+			ActorID:       actorID(actor),
+			AssociationID: &event.AssociationID,
+			CategoryID:    &category.ID,
+		}
+		return recordPoints(tx, granted)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishPoints(granted, nil)
+	s.badgeService.Refresh(userID, enums.CompletedShiftsCriterion)
+	return signup, nil
+}
+
+func findSignup(db *gorm.DB, shiftID string, userID string) (*models.ShiftSignup, error) {
+	var signup models.ShiftSignup
+	err := db.First(&signup, "shift_id = ? AND user_id = ?", shiftID, userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, coreErrors.ErrNotRegistered
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &signup, nil
+}
+
+// countSignups renseigne le nombre d'inscrits de chaque créneau
+func countSignups(shifts []models.EventShift) error {
+	if len(shifts) == 0 {
+		return nil
+	}
+	ids := make([]string, len(shifts))
+	for i := range shifts {
+		ids[i] = shifts[i].ID
+	}
+
+	var rows []struct {
+		ShiftID string
+		Count   int64
+	}
+	err := database.CurrentDatabase.Model(&models.ShiftSignup{}).
+		Select("shift_id, COUNT(*) AS count").
+		Where("shift_id IN ?", ids).
+		Group("shift_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ShiftID] = row.Count
+	}
+	for i := range shifts {
+		shifts[i].SignedUp = counts[shifts[i].ID]
+	}
+	return nil
+}
+
+func validateShift(shift *models.EventShift) error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	return validate.Struct(shift)
+}
+
+func hasRequiredRole(user *models.User, required enums.Role) bool {
+	if required == "" {
+		return true
+	}
+	return roleRanks[user.Role] >= roleRanks[required]
+}
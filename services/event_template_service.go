@@ -0,0 +1,206 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+type EventTemplateService struct {
+	eventService *EventService
+	formService  *EventFormService
+	shiftService *EventShiftService
+}
+
+func NewEventTemplateService() *EventTemplateService {
+	return &EventTemplateService{
+		eventService: NewEventService(),
+		formService:  NewEventFormService(),
+		shiftService: NewEventShiftService(),
+	}
+}
+
+// GetTemplates renvoie les modèles de l'association par ordre alphabétique
+func (s *EventTemplateService) GetTemplates(associationID string) ([]models.EventTemplate, error) {
+	var templates []models.EventTemplate
+	err := database.CurrentDatabase.Preload("Category").Where("association_id = ?", associationID).Order("name").Find(&templates).Error
+	return templates, err
+}
+
+// GetTemplate renvoie un modèle
+func (s *EventTemplateService) GetTemplate(id string) (*models.EventTemplate, error) {
+	var template models.EventTemplate
+	err := database.CurrentDatabase.Preload("Category").First(&template, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, coreErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// SaveAsTemplate enregistre l'événement, son formulaire d'inscription et ses créneaux comme modèle de l'association
+// organisatrice
+func (s *EventTemplateService) SaveAsTemplate(event *models.Event, name string) (*models.EventTemplate, error) {
+	template, err := templateFromEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	template.Name = strings.TrimSpace(name)
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	if err := validate.Struct(template); err != nil {
+		return nil, err
+	}
+
+	if err := database.CurrentDatabase.Create(template).Error; err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// DeleteTemplate supprime le modèle ; les événements créés à partir de lui ne sont pas modifiés
+func (s *EventTemplateService) DeleteTemplate(template *models.EventTemplate) error {
+	return database.CurrentDatabase.Delete(template).Error
+}
+
+// CreateEventFromTemplate crée un événement débutant à date à partir du modèle ; name remplace, s'il est
+// renseigné, le nom de l'événement du modèle. L'événement est créé en brouillon pour être relu avant publication.
+func (s *EventTemplateService) CreateEventFromTemplate(template *models.EventTemplate, date time.Time, name string) (*models.Event, error) {
+	event := &models.Event{
+		Name:              template.EventName,
+		Description:       template.Description,
+		Date:              date,
+		TimeZone:          template.TimeZone,
+		Location:          template.Location,
+		Capacity:          template.Capacity,
+		Visibility:        template.Visibility,
+		PublicationStatus: enums.DraftPublication,
+		CategoryID:        template.CategoryID,
+		AssociationID:     template.AssociationID,
+		VenueID:           template.VenueID,
+	}
+	if name = strings.TrimSpace(name); name != "" {
+		event.Name = name
+	}
+	if template.Duration > 0 {
+		endDate := date.Add(time.Duration(template.Duration) * time.Minute)
+		event.EndDate = &endDate
+	}
+
+	if _, err := s.eventService.AddEvent(event); err != nil {
+		return nil, err
+	}
+
+	// Le brouillon n'étant visible que des responsables, il est supprimé si son formulaire ou ses créneaux
+	// ne peuvent pas être recréés
+	if err := s.applyTemplate(event, template); err != nil {
+		if deleteErr := s.eventService.DeleteEvent(event.ID); deleteErr != nil {
+			fmt.Printf("Erreur lors de la suppression de l'événement %s: %v\n", event.ID, deleteErr)
+		}
+		return nil, err
+	}
+	return event, nil
+}
+
+// DuplicateEvent crée une copie de l'événement débutant à date, avec son formulaire et ses créneaux.
+// Les participants, invitations et co-organisateurs ne sont pas repris.
+func (s *EventTemplateService) DuplicateEvent(event *models.Event, date time.Time, name string) (*models.Event, error) {
+	template, err := templateFromEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	return s.CreateEventFromTemplate(template, date, name)
+}
+
+func (s *EventTemplateService) applyTemplate(event *models.Event, template *models.EventTemplate) error {
+	if len(template.Questions) > 0 {
+		questions := make([]models.EventQuestion, len(template.Questions))
+		for i, question := range template.Questions {
+			questions[i] = models.EventQuestion{
+				Label:    question.Label,
+				Type:     question.Type,
+				Options:  question.Options,
+				Required: question.Required,
+			}
+		}
+		if _, err := s.formService.SetQuestions(event, questions); err != nil {
+			return err
+		}
+	}
+
+	for _, templateShift := range template.Shifts {
+		startsAt := event.Date.Add(time.Duration(templateShift.Offset) * time.Minute)
+		shift := &models.EventShift{
+			Name:         templateShift.Name,
+			Description:  templateShift.Description,
+			StartsAt:     startsAt,
+			EndsAt:       startsAt.Add(time.Duration(templateShift.Duration) * time.Minute),
+			Capacity:     templateShift.Capacity,
+			RequiredRole: templateShift.RequiredRole,
+		}
+		if err := s.shiftService.CreateShift(event, shift); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateFromEvent construit, sans l'enregistrer, le modèle correspondant à l'événement
+func templateFromEvent(event *models.Event) (*models.EventTemplate, error) {
+	template := &models.EventTemplate{
+		EventName:     event.Name,
+		Description:   event.Description,
+		Location:      event.Location,
+		TimeZone:      event.TimeZone,
+		Capacity:      event.Capacity,
+		Visibility:    event.Visibility,
+		CategoryID:    event.CategoryID,
+		AssociationID: event.AssociationID,
+		VenueID:       event.VenueID,
+		Questions:     models.TemplateQuestionList{},
+		Shifts:        models.TemplateShiftList{},
+	}
+	if event.EndDate != nil {
+		template.Duration = int(event.EndDate.Sub(event.Date).Minutes())
+	}
+
+	questions, err := findQuestions(database.CurrentDatabase, event.FormEventID())
+	if err != nil {
+		return nil, err
+	}
+	for _, question := range questions {
+		template.Questions = append(template.Questions, models.TemplateQuestion{
+			Label:    question.Label,
+			Type:     question.Type,
+			Options:  question.Options,
+			Required: question.Required,
+		})
+	}
+
+	var shifts []models.EventShift
+	if err := database.CurrentDatabase.Where("event_id = ?", event.ID).Order("starts_at, name").Find(&shifts).Error; err != nil {
+		return nil, err
+	}
+	for _, shift := range shifts {
+		template.Shifts = append(template.Shifts, models.TemplateShift{
+			Name:         shift.Name,
+			Description:  shift.Description,
+			Offset:       int(shift.StartsAt.Sub(event.Date).Minutes()),
+			Duration:     int(shift.EndsAt.Sub(shift.StartsAt).Minutes()),
+			Capacity:     shift.Capacity,
+			RequiredRole: shift.RequiredRole,
+		})
+	}
+
+	return template, nil
+}
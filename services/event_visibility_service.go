@@ -0,0 +1,111 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	"backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventVisibilityService struct {
+}
+
+func NewEventVisibilityService() *EventVisibilityService {
+	return &EventVisibilityService{}
+}
+
+// VisibleEventsScope restreint une requête sur la table events aux événements que viewer peut consulter :
+// les événements publics pour tous, ceux réservés aux membres pour les membres acceptés de l'association,
+// ceux sur invitation pour les invités de l'événement ou de sa série, une fois publiés. Les associations
+// co-organisatrices sont traitées comme l'association organisatrice. Le responsable d'une association
+// organisatrice voit tous ses événements, brouillons compris, un administrateur tous les événements.
+// viewer vaut nil pour un visiteur anonyme.
+func VisibleEventsScope(viewer *models.User) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if viewer != nil && enums.IsAdmin(viewer.Role) {
+			return db
+		}
+		if viewer == nil {
+			return db.Where("events.visibility = ? AND events.publication_status = ?", enums.PublicVisibility, enums.PublishedPublication)
+		}
+
+		ownedAssociations := "SELECT id FROM associations WHERE owner_id = ?"
+		joinedAssociations := "SELECT association_id FROM memberships WHERE user_id = ? AND status = ? AND deleted_at IS NULL"
+
+		return db.Where(
+			hostedBySQL(ownedAssociations)+" "+
+				"OR (events.publication_status = ? AND ("+
+				"events.visibility = ? "+
+				"OR (events.visibility = ? AND "+hostedBySQL(joinedAssociations)+") "+
+				"OR (events.visibility = ? AND COALESCE(events.recurrence_parent_id, events.id) IN (SELECT event_id FROM event_invitations WHERE user_id = ?))))",
+			viewer.ID, viewer.ID,
+			enums.PublishedPublication,
+			enums.PublicVisibility,
+			enums.MembersVisibility, viewer.ID, enums.Accepted, viewer.ID, enums.Accepted,
+			enums.InviteOnlyVisibility, viewer.ID,
+		)
+	}
+}
+
+// CanViewEvent indique si viewer peut consulter l'événement, selon les mêmes règles que VisibleEventsScope
+func (s *EventVisibilityService) CanViewEvent(viewer *models.User, event *models.Event) (bool, error) {
+	var count int64
+	err := database.CurrentDatabase.Model(&models.Event{}).
+		Scopes(VisibleEventsScope(viewer)).
+		Where("events.id = ?", event.ID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetInvitations renvoie les invités de l'événement (de sa série pour une occurrence)
+func (s *EventVisibilityService) GetInvitations(event *models.Event) ([]models.EventInvitation, error) {
+	var invitations []models.EventInvitation
+	err := database.CurrentDatabase.
+		Preload("User").
+		Where("event_id = ?", event.FormEventID()).
+		Order("created_at").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// InviteUsers ajoute des utilisateurs à la liste des invités. Les utilisateurs déjà invités sont ignorés,
+// les identifiants inconnus aussi.
+func (s *EventVisibilityService) InviteUsers(event *models.Event, invitedBy *models.User, userIDs []string) ([]models.EventInvitation, error) {
+	if len(userIDs) > 0 {
+		var existingIDs []string
+		if err := database.CurrentDatabase.Model(&models.User{}).Where("id IN ?", userIDs).Pluck("id", &existingIDs).Error; err != nil {
+			return nil, err
+		}
+
+		invitations := make([]models.EventInvitation, 0, len(existingIDs))
+		for _, userID := range existingIDs {
+			invitations = append(invitations, models.EventInvitation{
+				EventID:     event.FormEventID(),
+				UserID:      userID,
+				InvitedByID: invitedBy.ID,
+			})
+		}
+
+		if len(invitations) > 0 {
+			if err := database.CurrentDatabase.Clauses(clause.OnConflict{DoNothing: true}).Create(&invitations).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return s.GetInvitations(event)
+}
+
+// RevokeInvitation retire un utilisateur de la liste des invités
+func (s *EventVisibilityService) RevokeInvitation(event *models.Event, userID string) error {
+	result := database.CurrentDatabase.Delete(&models.EventInvitation{}, "event_id = ? AND user_id = ?", event.FormEventID(), userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
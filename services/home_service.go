@@ -4,6 +4,7 @@ import (
 	"backend/database"
 	"backend/enums"
 	"backend/models"
+	"fmt"
 )
 
 type HomeService struct{}
@@ -54,24 +55,88 @@ func (s *HomeService) GetStatistics(user models.User) (map[string]interface{}, e
 	}, nil
 }
 
-func (s *HomeService) GetTopAssociations() ([]models.Association, error) {
-	var associations []models.Association
+// Score de qualité : moyenne des notes lissée vers une note neutre tant que l'association a reçu peu d'avis,
+// pour qu'une seule très bonne note ne suffise pas à la placer en tête
+const (
+	qualityPriorRating = 3
+	qualityPriorWeight = 5
+)
 
-	// Récupérer les associations avec un comptage de leurs membres
+var qualityScoreSQL = fmt.Sprintf(
+	"((%d * %d + COALESCE(feedback.average_rating * feedback.rating_count, 0)) / (%d + COALESCE(feedback.rating_count, 0)))",
+	qualityPriorRating, qualityPriorWeight, qualityPriorWeight,
+)
+
+// TopAssociation est une association du classement, avec les indicateurs qui ont servi à la classer
+type TopAssociation struct {
+	models.Association
+	MemberCount   int64    `json:"member_count"`
+	RatingCount   int64    `json:"rating_count"`
+	AverageRating *float64 `json:"average_rating"`
+	QualityScore  float64  `json:"quality_score"`
+}
+
+// GetTopAssociations classe les associations selon leur nombre de membres pondéré par la note moyenne
+// laissée par les participants à leurs événements
+func (s *HomeService) GetTopAssociations() ([]TopAssociation, error) {
+	feedbackStats := database.CurrentDatabase.Model(&models.EventFeedback{}).
+		Select("events.association_id, COUNT(*) AS rating_count, AVG(event_feedbacks.rating) AS average_rating").
+		Joins("JOIN events ON events.id = event_feedbacks.event_id").
+		Group("events.association_id")
+
+	var ranking []struct {
+		ID            string
+		MemberCount   int64
+		RatingCount   int64
+		AverageRating *float64
+		QualityScore  float64
+	}
 	err := database.CurrentDatabase.
 		Model(&models.Association{}).
-		Select("associations.*, COUNT(DISTINCT memberships.user_id) as member_count").
+		Select("associations.id, COUNT(DISTINCT memberships.user_id) AS member_count, "+
+			"COALESCE(feedback.rating_count, 0) AS rating_count, feedback.average_rating, "+
+			qualityScoreSQL+" AS quality_score").
 		Joins("LEFT JOIN memberships ON memberships.association_id = associations.id").
+		Joins("LEFT JOIN (?) AS feedback ON feedback.association_id = associations.id", feedbackStats).
 		Where("memberships.status = ?", enums.Accepted).
-		Group("associations.id").
-		Order("member_count DESC").
+		Group("associations.id, feedback.rating_count, feedback.average_rating").
+		Order("COUNT(DISTINCT memberships.user_id) * " + qualityScoreSQL + " DESC").
 		Limit(3).
-		Preload("Owner").
-		Find(&associations).Error
-
+		Scan(&ranking).Error
 	if err != nil {
 		return nil, err
 	}
 
-	return associations, nil
+	ids := make([]string, len(ranking))
+	for i, row := range ranking {
+		ids[i] = row.ID
+	}
+
+	var associations []models.Association
+	if len(ids) > 0 {
+		if err := database.CurrentDatabase.Preload("Owner").Where("id IN ?", ids).Find(&associations).Error; err != nil {
+			return nil, err
+		}
+	}
+	associationsByID := make(map[string]models.Association, len(associations))
+	for _, association := range associations {
+		associationsByID[association.ID] = association
+	}
+
+	top := make([]TopAssociation, 0, len(ranking))
+	for _, row := range ranking {
+		association, ok := associationsByID[row.ID]
+		if !ok {
+			continue
+		}
+		top = append(top, TopAssociation{
+			Association:   association,
+			MemberCount:   row.MemberCount,
+			RatingCount:   row.RatingCount,
+			AverageRating: row.AverageRating,
+			QualityScore:  row.QualityScore,
+		})
+	}
+
+	return top, nil
 }
@@ -0,0 +1,563 @@
+package services
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/utils"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// leaderboardKeyPrefix préfixe les ensembles triés Redis des classements :
+// leaderboard:<portée>:<période>[:<début de période>], la portée étant global, association:<id> ou category:<id>
+const leaderboardKeyPrefix = "leaderboard:"
+
+// leaderboardRetention est la durée de conservation des classements d'une période après son début
+var leaderboardRetention = map[enums.LeaderboardPeriod]time.Duration{
+	enums.WeekLeaderboard:     8 * 7 * 24 * time.Hour,
+	enums.MonthLeaderboard:    400 * 24 * time.Hour,
+	enums.SemesterLeaderboard: 400 * 24 * time.Hour,
+}
+
+// LeaderboardScope restreint un classement à une association ou à une catégorie ; vide pour le classement général
+type LeaderboardScope struct {
+	AssociationID string
+	CategoryID    string
+}
+
+// LeaderboardEntry est une ligne d'un classement. Les ex aequo partagent le même rang.
+type LeaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	Points   int64  `json:"points"`
+	UserID   string `json:"user_id"`
+	Name     string `json:"name"`
+	ImageURL string `json:"image_url"`
+}
+
+type LeaderboardService struct{}
+
+func NewLeaderboardService() *LeaderboardService {
+	return &LeaderboardService{}
+}
+
+// leaderboardChunkSize est le nombre de membres lus à la fois dans un classement : seuls les rangs
+// nécessaires à la page demandée sont chargés
+const leaderboardChunkSize = 200
+
+// GetLeaderboard renvoie le classement de la période en cours à la date now. Les utilisateurs ayant choisi
+// de ne pas apparaître dans les classements sont retirés avant le calcul des rangs.
+func (s *LeaderboardService) GetLeaderboard(period enums.LeaderboardPeriod, scope LeaderboardScope, now time.Time, pagination utils.Pagination) (*utils.Pagination, error) {
+	if !isLeaderboardPeriod(period) || (scope.AssociationID != "" && scope.CategoryID != "") {
+		return nil, coreErrors.ErrInvalidLeaderboard
+	}
+	if config.RedisClient == nil {
+		return nil, errors.New("redis is not initialised")
+	}
+
+	ctx := context.Background()
+	key := leaderboardKey(scope.key(), period, now)
+	ranked, err := config.RedisClient.ZCount(ctx, key, "(0", "+inf").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	// Le classement est lu par tranches jusqu'à la fin de la page, les utilisateurs masqués étant sautés
+	wanted := pagination.GetOffset() + pagination.GetLimit()
+	entries := make([]LeaderboardEntry, 0, wanted)
+	for start := int64(0); start < ranked && len(entries) < wanted; start += leaderboardChunkSize {
+		scores, err := config.RedisClient.ZRevRangeWithScores(ctx, key, start, start+leaderboardChunkSize-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(scores) == 0 {
+			break
+		}
+
+		visible, err := visibleLeaderboardUsers(scores)
+		if err != nil {
+			return nil, err
+		}
+		for _, score := range scores {
+			user, ok := visible[score.Member.(string)]
+			points := int64(score.Score)
+			if !ok || points <= 0 {
+				continue
+			}
+
+			rank := len(entries) + 1
+			if len(entries) > 0 && entries[len(entries)-1].Points == points {
+				rank = entries[len(entries)-1].Rank
+			}
+			entries = append(entries, LeaderboardEntry{
+				Rank:     rank,
+				Points:   points,
+				UserID:   user.ID,
+				Name:     user.Name,
+				ImageURL: user.ImageURL,
+			})
+		}
+	}
+
+	hidden, err := hiddenLeaderboardMembers(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	pagination.Total = ranked - hidden
+	pagination.Pages = int(math.Ceil(float64(pagination.Total) / float64(pagination.GetLimit())))
+
+	first := pagination.GetOffset()
+	if first > len(entries) {
+		first = len(entries)
+	}
+	last := first + pagination.GetLimit()
+	if last > len(entries) {
+		last = len(entries)
+	}
+	pagination.Rows = entries[first:last]
+	return &pagination, nil
+}
+
+// visibleLeaderboardUsers charge les membres du classement qui acceptent d'y apparaître
+func visibleLeaderboardUsers(scores []redis.Z) (map[string]models.User, error) {
+	userIDs := make([]string, 0, len(scores))
+	for _, score := range scores {
+		userIDs = append(userIDs, score.Member.(string))
+	}
+
+	var users []models.User
+	err := database.CurrentDatabase.Select("id", "name", "image_url").
+		Where("id IN ? AND hide_from_leaderboards = ?", userIDs, false).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make(map[string]models.User, len(users))
+	for _, user := range users {
+		visible[user.ID] = user
+	}
+	return visible, nil
+}
+
+// hiddenLeaderboardMembers compte les utilisateurs masqués ayant des points dans le classement key, afin de
+// les retirer du total sans parcourir tout le classement
+func hiddenLeaderboardMembers(ctx context.Context, key string) (int64, error) {
+	var hiddenIDs []string
+	err := database.CurrentDatabase.Model(&models.User{}).Where("hide_from_leaderboards = ?", true).Pluck("id", &hiddenIDs).Error
+	if err != nil || len(hiddenIDs) == 0 {
+		return 0, err
+	}
+
+	var hidden int64
+	for start := 0; start < len(hiddenIDs); start += leaderboardChunkSize {
+		end := start + leaderboardChunkSize
+		if end > len(hiddenIDs) {
+			end = len(hiddenIDs)
+		}
+		scores, err := config.RedisClient.ZMScore(ctx, key, hiddenIDs[start:end]...).Result()
+		if err != nil {
+			return 0, err
+		}
+		for _, score := range scores {
+			if score > 0 {
+				hidden++
+			}
+		}
+	}
+	return hidden, nil
+}
+
+// Pendant une reconstruction, les écritures ne sont pas reportées dans les classements, qui vont être remplacés,
+// mais consignées dans un journal rejoué une fois les nouveaux classements en place
+const (
+	leaderboardRebuildLockKey = "leaderboard-rebuild:lock"
+	leaderboardJournalKey     = "leaderboard-rebuild:journal"
+	leaderboardRebuildTimeout = 10 * time.Minute
+)
+
+// recordScript incrémente les classements KEYS[3..], sauf si une reconstruction est en cours (KEYS[1]) : l'écriture
+// ARGV[1] est alors ajoutée au journal KEYS[2]. ARGV[2] est le nombre de points, ARGV[3] l'utilisateur et ARGV[4..]
+// la date d'expiration de chaque classement, 0 s'il est conservé.
+var recordScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('RPUSH', KEYS[2], ARGV[1])
+	return 0
+end
+for i = 3, #KEYS do
+	redis.call('ZINCRBY', KEYS[i], ARGV[2], ARGV[3])
+	local expireAt = tonumber(ARGV[i + 1])
+	if expireAt > 0 then
+		redis.call('EXPIREAT', KEYS[i], expireAt)
+	end
+end
+return 1
+`)
+
+// swapScript remplace les classements par leur reconstruction (KEYS[3..] par paires clé temporaire, clé), libère
+// le verrou KEYS[1] s'il est toujours détenu (ARGV[1]) et renvoie le journal KEYS[2] en le vidant
+var swapScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return redis.error_reply('leaderboard rebuild lock lost')
+end
+for i = 3, #KEYS, 2 do
+	redis.call('RENAME', KEYS[i], KEYS[i + 1])
+end
+local journal = redis.call('LRANGE', KEYS[2], 0, -1)
+redis.call('DEL', KEYS[2], KEYS[1])
+return journal
+`)
+
+// unlockScript libère le verrou KEYS[1] s'il est toujours détenu (ARGV[1])
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// journalEntry est une écriture consignée pendant une reconstruction
+type journalEntry struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"user_id"`
+	Amount        int       `json:"amount"`
+	AssociationID *string   `json:"association_id,omitempty"`
+	CategoryID    *string   `json:"category_id,omitempty"`
+	At            time.Time `json:"at"`
+}
+
+// Record reporte une écriture du registre de points dans les classements des périodes contenant at. Redis ne
+// participant pas à la transaction, les erreurs sont journalisées : la reconstruction les corrige.
+func (s *LeaderboardService) Record(entry *models.PointsEntry, at time.Time) {
+	if config.RedisClient == nil {
+		return
+	}
+
+	payload, err := json.Marshal(journalEntry{
+		ID:            entry.ID,
+		UserID:        entry.UserID,
+		Amount:        entry.Amount,
+		AssociationID: entry.AssociationID,
+		CategoryID:    entry.CategoryID,
+		At:            at,
+	})
+	if err != nil {
+		fmt.Printf("Erreur lors de la mise à jour des classements pour %s: %v\n", entry.UserID, err)
+		return
+	}
+
+	keys := []string{leaderboardRebuildLockKey, leaderboardJournalKey}
+	args := []interface{}{payload, entry.Amount, entry.UserID}
+	for _, scope := range entryScopes(entry.AssociationID, entry.CategoryID) {
+		for _, period := range enums.LeaderboardPeriods {
+			keys = append(keys, leaderboardKey(scope, period, at))
+			args = append(args, s.expireAt(period, at))
+		}
+	}
+	if err := recordScript.Run(context.Background(), config.RedisClient, keys, args...).Err(); err != nil {
+		fmt.Printf("Erreur lors de la mise à jour des classements pour %s: %v\n", entry.UserID, err)
+	}
+}
+
+// expireAt renvoie la date d'expiration, en secondes Unix, du classement de la période contenant at ; 0 si le
+// classement est conservé
+func (s *LeaderboardService) expireAt(period enums.LeaderboardPeriod, at time.Time) int64 {
+	retention, ok := leaderboardRetention[period]
+	if !ok {
+		return 0
+	}
+	return s.PeriodStart(period, at).Add(retention).Unix()
+}
+
+// leaderboardRow est une somme de points du registre pour un utilisateur dans une association et une catégorie
+type leaderboardRow struct {
+	UserID        string
+	AssociationID *string
+	CategoryID    *string
+	Amount        int64
+	EarnedAt      time.Time
+}
+
+// leaderboardScores est un classement en cours de reconstruction
+type leaderboardScores struct {
+	period enums.LeaderboardPeriod
+	scores map[string]float64
+}
+
+// Rebuild recalcule depuis le registre de points les classements de toutes les portées pour les périodes en
+// cours et le classement général. Les points dépensés en récompenses ne sont pas retirés des classements.
+// Le registre est lu dans un instantané pendant que Record consigne les nouvelles écritures dans un journal ;
+// les classements sont écrits dans des clés temporaires puis renommés d'un bloc, et les écritures du journal
+// absentes de l'instantané sont ensuite rejouées. Renvoie le nombre de classements écrits.
+func (s *LeaderboardService) Rebuild(now time.Time) (int, error) {
+	if config.RedisClient == nil {
+		return 0, errors.New("redis is not initialised")
+	}
+
+	ctx := context.Background()
+	token := utils.GenerateULID()
+	locked, err := config.RedisClient.SetNX(ctx, leaderboardRebuildLockKey, token, leaderboardRebuildTimeout).Result()
+	if err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, errors.New("a leaderboard rebuild is already running")
+	}
+
+	var rebuilt int
+	var replay []journalEntry
+	err = database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		boards, err := s.loadBoards(tx, now)
+		if err != nil {
+			return err
+		}
+		rebuilt = len(boards)
+
+		journal, err := s.swapBoards(ctx, boards, now, token)
+		if err != nil {
+			return err
+		}
+
+		// Les écritures déjà visibles dans l'instantané sont comptées dans les classements reconstruits
+		ids := make([]string, 0, len(journal))
+		for _, entry := range journal {
+			ids = append(ids, entry.ID)
+		}
+		var counted []string
+		if len(ids) > 0 {
+			if err := tx.Model(&models.PointsEntry{}).Where("id IN ?", ids).Pluck("id", &counted).Error; err != nil {
+				return err
+			}
+		}
+		inSnapshot := make(map[string]bool, len(counted))
+		for _, id := range counted {
+			inSnapshot[id] = true
+		}
+		for _, entry := range journal {
+			if !inSnapshot[entry.ID] {
+				replay = append(replay, entry)
+			}
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		// Le verrou n'est libéré que s'il est encore détenu par cette reconstruction
+		unlockScript.Run(ctx, config.RedisClient, []string{leaderboardRebuildLockKey}, token)
+		return 0, err
+	}
+
+	for _, entry := range replay {
+		s.Record(&models.PointsEntry{
+			ID:            entry.ID,
+			UserID:        entry.UserID,
+			Amount:        entry.Amount,
+			AssociationID: entry.AssociationID,
+			CategoryID:    entry.CategoryID,
+		}, entry.At)
+	}
+	return rebuilt, nil
+}
+
+// loadBoards calcule les classements depuis le registre lu par tx
+func (s *LeaderboardService) loadBoards(tx *gorm.DB, now time.Time) (map[string]*leaderboardScores, error) {
+	boards := map[string]*leaderboardScores{}
+	add := func(scope string, period enums.LeaderboardPeriod, userID string, amount int64) {
+		key := leaderboardKey(scope, period, now)
+		if boards[key] == nil {
+			boards[key] = &leaderboardScores{period: period, scores: map[string]float64{}}
+		}
+		boards[key].scores[userID] += float64(amount)
+	}
+
+	var totals []leaderboardRow
+	err := tx.Model(&models.PointsEntry{}).
+		Select("user_id, association_id, category_id, SUM(amount) AS amount").
+		Where("source <> ?", enums.RedemptionPoints).
+		Group("user_id, association_id, category_id").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range totals {
+		for _, scope := range entryScopes(row.AssociationID, row.CategoryID) {
+			add(scope, enums.AllTimeLeaderboard, row.UserID, row.Amount)
+		}
+	}
+
+	// Une annulation compte dans la période où les points annulés avaient été gagnés
+	since := s.PeriodStart(enums.SemesterLeaderboard, now)
+	if weekStart := s.PeriodStart(enums.WeekLeaderboard, now); weekStart.Before(since) {
+		since = weekStart
+	}
+	var recent []leaderboardRow
+	err = tx.Table("points_entries AS entries").
+		Select("entries.user_id, entries.association_id, entries.category_id, entries.amount, COALESCE(reversed.created_at, entries.created_at) AS earned_at").
+		Joins("LEFT JOIN points_entries AS reversed ON reversed.id = entries.reversal_of_id").
+		Where("COALESCE(reversed.created_at, entries.created_at) >= ?", since).
+		Where("entries.source <> ?", enums.RedemptionPoints).
+		Scan(&recent).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range recent {
+		for _, period := range []enums.LeaderboardPeriod{enums.WeekLeaderboard, enums.MonthLeaderboard, enums.SemesterLeaderboard} {
+			if row.EarnedAt.Before(s.PeriodStart(period, now)) {
+				continue
+			}
+			for _, scope := range entryScopes(row.AssociationID, row.CategoryID) {
+				add(scope, period, row.UserID, row.Amount)
+			}
+		}
+	}
+	return boards, nil
+}
+
+// swapBoards écrit les classements reconstruits dans des clés temporaires, supprime les classements des
+// périodes en cours qui n'ont plus aucun point puis met les nouveaux classements en place en libérant le
+// verrou. Renvoie les écritures consignées dans le journal pendant la reconstruction.
+func (s *LeaderboardService) swapBoards(ctx context.Context, boards map[string]*leaderboardScores, now time.Time, token string) ([]journalEntry, error) {
+	keys := []string{leaderboardRebuildLockKey, leaderboardJournalKey}
+	for key, board := range boards {
+		members := make([]redis.Z, 0, len(board.scores))
+		for userID, score := range board.scores {
+			members = append(members, redis.Z{Score: score, Member: userID})
+		}
+
+		temporaryKey := key + ":rebuild"
+		pipe := config.RedisClient.TxPipeline()
+		pipe.Del(ctx, temporaryKey)
+		pipe.ZAdd(ctx, temporaryKey, members...)
+		if expireAt := s.expireAt(board.period, now); expireAt > 0 {
+			pipe.ExpireAt(ctx, temporaryKey, time.Unix(expireAt, 0))
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+		keys = append(keys, temporaryKey, key)
+	}
+
+	// Les classements des périodes en cours sans plus aucun point, après une annulation par exemple, sont
+	// supprimés ; le verrou garantit qu'aucune écriture ne les recrée entre-temps
+	currentSuffixes := make([]string, 0, len(enums.LeaderboardPeriods))
+	for _, period := range enums.LeaderboardPeriods {
+		currentSuffixes = append(currentSuffixes, leaderboardKey("", period, now))
+	}
+	iter := config.RedisClient.Scan(ctx, 0, leaderboardKeyPrefix+"*", 500).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if _, rebuilt := boards[key]; rebuilt || !hasAnySuffix(key, currentSuffixes) {
+			continue
+		}
+		if err := config.RedisClient.Del(ctx, key).Err(); err != nil {
+			return nil, err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	payloads, err := swapScript.Run(ctx, config.RedisClient, keys, token).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+	journal := make([]journalEntry, 0, len(payloads))
+	for _, payload := range payloads {
+		var entry journalEntry
+		if err := json.Unmarshal([]byte(payload), &entry); err != nil {
+			fmt.Printf("Écriture du journal des classements illisible: %v\n", err)
+			continue
+		}
+		journal = append(journal, entry)
+	}
+	return journal, nil
+}
+
+// PeriodStart renvoie le début de la période contenant t, dans le fuseau horaire des calendriers. Les semaines
+// commencent le lundi ; la période all commence à l'instant zéro.
+func (s *LeaderboardService) PeriodStart(period enums.LeaderboardPeriod, t time.Time) time.Time {
+	return leaderboardPeriodStart(period, t)
+}
+
+func leaderboardPeriodStart(period enums.LeaderboardPeriod, t time.Time) time.Time {
+	local := t.In(utils.CalendarLocation())
+	year, month, day := local.Date()
+	switch period {
+	case enums.WeekLeaderboard:
+		daysSinceMonday := (int(local.Weekday()) + 6) % 7
+		return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, local.Location())
+	case enums.MonthLeaderboard:
+		return time.Date(year, month, 1, 0, 0, 0, 0, local.Location())
+	case enums.SemesterLeaderboard:
+		switch {
+		case month >= time.September:
+			return time.Date(year, time.September, 1, 0, 0, 0, 0, local.Location())
+		case month < time.February:
+			return time.Date(year-1, time.September, 1, 0, 0, 0, 0, local.Location())
+		}
+		return time.Date(year, time.February, 1, 0, 0, 0, 0, local.Location())
+	}
+	return time.Time{}
+}
+
+func (scope LeaderboardScope) key() string {
+	switch {
+	case scope.AssociationID != "":
+		return "association:" + scope.AssociationID
+	case scope.CategoryID != "":
+		return "category:" + scope.CategoryID
+	}
+	return "global"
+}
+
+// entryScopes renvoie les portées des classements dans lesquels comptent des points
+func entryScopes(associationID *string, categoryID *string) []string {
+	scopes := []string{"global"}
+	if associationID != nil && *associationID != "" {
+		scopes = append(scopes, LeaderboardScope{AssociationID: *associationID}.key())
+	}
+	if categoryID != nil && *categoryID != "" {
+		scopes = append(scopes, LeaderboardScope{CategoryID: *categoryID}.key())
+	}
+	return scopes
+}
+
+// leaderboardKey renvoie la clé du classement ; une portée vide donne le suffixe commun aux classements de la période
+func leaderboardKey(scope string, period enums.LeaderboardPeriod, at time.Time) string {
+	suffix := ":" + string(period)
+	if period != enums.AllTimeLeaderboard {
+		suffix += ":" + leaderboardPeriodStart(period, at).Format("2006-01-02")
+	}
+	if scope == "" {
+		return suffix
+	}
+	return leaderboardKeyPrefix + scope + suffix
+}
+
+func isLeaderboardPeriod(period enums.LeaderboardPeriod) bool {
+	for _, candidate := range enums.LeaderboardPeriods {
+		if candidate == period {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnySuffix(value string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(value, suffix) {
+			return true
+		}
+	}
+	return false
+}
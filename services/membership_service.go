@@ -1,21 +1,27 @@
 package services
 
 import (
+	"backend/enums"
 	"backend/models"
 
 	"gorm.io/gorm"
 )
 
 type MembershipService struct {
-	db *gorm.DB
+	db           *gorm.DB
+	badgeService *BadgeService
 }
 
 func NewMembershipService(db *gorm.DB) *MembershipService {
-	return &MembershipService{db: db}
+	return &MembershipService{db: db, badgeService: NewBadgeService()}
 }
 
 func (s *MembershipService) Create(membership *models.Membership) error {
-	return s.db.Create(membership).Error
+	if err := s.db.Create(membership).Error; err != nil {
+		return err
+	}
+	s.badgeService.Refresh(membership.UserID, enums.JoinedAssociationsCriterion)
+	return nil
 }
 
 func (s *MembershipService) GetByID(id string) (*models.Membership, error) {
@@ -31,7 +37,12 @@ func (s *MembershipService) GetAll() ([]models.Membership, error) {
 }
 
 func (s *MembershipService) Update(membership *models.Membership) error {
-	return s.db.Save(membership).Error
+	if err := s.db.Save(membership).Error; err != nil {
+		return err
+	}
+	// Une adhésion acceptée après coup compte pour les badges
+	s.badgeService.Refresh(membership.UserID, enums.JoinedAssociationsCriterion)
+	return nil
 }
 
 func (s *MembershipService) Delete(id string) error {
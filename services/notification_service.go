@@ -0,0 +1,121 @@
+package services
+
+import (
+	"backend/enums"
+	"backend/models"
+	"backend/utils"
+	"fmt"
+	"html"
+	"time"
+)
+
+type NotificationService struct {
+}
+
+func NewNotificationService() *NotificationService {
+	return &NotificationService{}
+}
+
+// NotifyUser prévient l'utilisateur par notification push et par email. Les échecs d'envoi
+// sont journalisés sans interrompre le traitement en cours.
+func (s *NotificationService) NotifyUser(user *models.User, title string, message string) {
+	if user.FirebaseToken != "" {
+		if err := utils.SendNotification(user.FirebaseToken, title, message); err != nil {
+			fmt.Printf("Erreur lors de l'envoi de la notification à %s: %v\n", user.ID, err)
+		}
+	}
+
+	if user.Email != "" {
+		body := fmt.Sprintf("<p>Bonjour %s,</p><p>%s</p>", html.EscapeString(user.Name), html.EscapeString(message))
+		if err := utils.SendEmail(user.Email, title, body); err != nil {
+			fmt.Printf("Erreur lors de l'envoi de l'email à %s: %v\n", user.ID, err)
+		}
+	}
+}
+
+// NotifyEventCancelled prévient les participants de l'annulation de l'événement
+func (s *NotificationService) NotifyEventCancelled(event *models.Event, participations []models.Participation) {
+	message := fmt.Sprintf("L'événement « %s » du %s est annulé.", event.Name, formatEventDate(event.Date))
+	if event.StatusReason != "" {
+		message += " Motif : " + event.StatusReason
+	}
+	s.notifyParticipants(participations, "Événement annulé", message)
+}
+
+// NotifyEventRescheduled prévient les participants du report de l'événement, avec sa nouvelle date si elle est connue
+func (s *NotificationService) NotifyEventRescheduled(event *models.Event, participations []models.Participation) {
+	title := "Événement reprogrammé"
+	message := fmt.Sprintf("L'événement « %s » est reprogrammé au %s.", event.Name, formatEventDate(event.Date))
+	if event.Status == enums.EventPostponed {
+		title = "Événement reporté"
+		message = fmt.Sprintf("L'événement « %s » est reporté à une date ultérieure.", event.Name)
+	}
+	if event.StatusReason != "" {
+		message += " Motif : " + event.StatusReason
+	}
+	s.notifyParticipants(participations, title, message)
+}
+
+func (s *NotificationService) notifyParticipants(participations []models.Participation, title string, message string) {
+	for _, participation := range participations {
+		if participation.User == nil {
+			continue
+		}
+		s.NotifyUser(participation.User, title, message)
+	}
+}
+
+// NotifyWaitlistPromotion prévient les participants passés de la liste d'attente aux inscrits
+func (s *NotificationService) NotifyWaitlistPromotion(event *models.Event, participations []models.Participation) {
+	s.notifyParticipants(participations, "Place disponible",
+		fmt.Sprintf("Une place s'est libérée : vous êtes maintenant inscrit à l'événement « %s » du %s.", event.Name, formatEventDate(event.Date)))
+}
+
+// NotifyEventPublished prévient les utilisateurs de la publication d'un nouvel événement
+func (s *NotificationService) NotifyEventPublished(event *models.Event, users []models.User) {
+	message := fmt.Sprintf("Nouvel événement : « %s » le %s.", event.Name, formatEventDate(event.Date))
+	for i := range users {
+		s.NotifyUser(&users[i], "Nouvel événement", message)
+	}
+}
+
+// NotifyEventComment prévient un organisateur d'une nouvelle question sur l'événement
+func (s *NotificationService) NotifyEventComment(organiser *models.User, author *models.User, event *models.Event) {
+	s.NotifyUser(organiser, "Nouvelle question",
+		fmt.Sprintf("%s a posé une question sur l'événement « %s ».", author.Name, event.Name))
+}
+
+// NotifyCommentReply prévient l'auteur d'un commentaire qu'une réponse y a été apportée
+func (s *NotificationService) NotifyCommentReply(user *models.User, event *models.Event) {
+	s.NotifyUser(user, "Nouvelle réponse",
+		fmt.Sprintf("Une réponse a été apportée à votre message sur l'événement « %s ».", event.Name))
+}
+
+// NotifyCoHostInvitation prévient le responsable d'une association invitée à co-organiser l'événement
+func (s *NotificationService) NotifyCoHostInvitation(owner *models.User, event *models.Event) {
+	s.NotifyUser(owner, "Invitation à co-organiser",
+		fmt.Sprintf("Votre association est invitée à co-organiser l'événement « %s » du %s.", event.Name, formatEventDate(event.Date)))
+}
+
+// NotifyBadgeAwarded félicite l'utilisateur pour le badge obtenu
+func (s *NotificationService) NotifyBadgeAwarded(user *models.User, badge *models.Badge) {
+	message := fmt.Sprintf("Félicitations, vous avez obtenu le badge « %s ».", badge.Name)
+	if badge.Description != "" {
+		message += " " + badge.Description
+	}
+	s.NotifyUser(user, "Nouveau badge", message)
+}
+
+// NotifyRedemptionRejected prévient l'utilisateur du refus de sa demande de récompense et du remboursement
+func (s *NotificationService) NotifyRedemptionRejected(user *models.User, reward *models.Reward, redemption *models.RewardRedemption) {
+	message := fmt.Sprintf("Votre demande « %s » a été refusée, vos %d points vous ont été rendus.", reward.Name, redemption.Cost)
+	if redemption.Reason != "" {
+		message += " Motif : " + redemption.Reason
+	}
+	s.NotifyUser(user, "Récompense refusée", message)
+}
+
+// formatEventDate formate la date d'un événement dans le fuseau horaire des calendriers
+func formatEventDate(date time.Time) string {
+	return date.In(utils.CalendarLocation()).Format("02/01/2006 à 15h04")
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+// Formats d'export des participants
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// Colonnes proposées par l'export des participants
+const (
+	ExportColumnEvent     = "event"
+	ExportColumnDate      = "date"
+	ExportColumnName      = "name"
+	ExportColumnEmail     = "email"
+	ExportColumnStatus    = "status"
+	ExportColumnCheckedIn = "checked_in_at"
+	ExportColumnAnswers   = "answers"
+)
+
+// exportBatchSize est le nombre de participations chargées à la fois : l'export n'est jamais entièrement en mémoire
+const exportBatchSize = 500
+
+// exportSheet est le nom de la feuille des exports XLSX
+const exportSheet = "Participants"
+
+var exportColumnHeaders = map[string]string{
+	ExportColumnEvent:     "Événement",
+	ExportColumnDate:      "Date",
+	ExportColumnName:      "Nom",
+	ExportColumnEmail:     "Email",
+	ExportColumnStatus:    "Statut",
+	ExportColumnCheckedIn: "Pointé le",
+	ExportColumnAnswers:   "Réponses",
+}
+
+// ParticipantExportOptions décrit le fichier demandé : son format et ses colonnes, dans l'ordre
+type ParticipantExportOptions struct {
+	Format  string
+	Columns []string
+}
+
+// ParseExportOptions lit le format (csv par défaut) et la liste de colonnes séparées par des virgules ;
+// sans colonnes, defaultColumns est utilisé
+func ParseExportOptions(format string, columns string, defaultColumns []string) (ParticipantExportOptions, error) {
+	options := ParticipantExportOptions{Format: strings.ToLower(format), Columns: defaultColumns}
+	if options.Format == "" {
+		options.Format = ExportFormatCSV
+	}
+	if options.Format != ExportFormatCSV && options.Format != ExportFormatXLSX {
+		return options, coreErrors.ErrInvalidExportFormat
+	}
+
+	if columns != "" {
+		options.Columns = nil
+		for _, column := range strings.Split(columns, ",") {
+			column = strings.TrimSpace(column)
+			if _, ok := exportColumnHeaders[column]; !ok {
+				return options, fmt.Errorf("%w: %s", coreErrors.ErrInvalidExportColumn, column)
+			}
+			options.Columns = append(options.Columns, column)
+		}
+	}
+	return options, nil
+}
+
+// ContentType renvoie le type MIME du fichier exporté
+func (o ParticipantExportOptions) ContentType() string {
+	if o.Format == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// EventExportColumns sont les colonnes par défaut de l'export d'un événement
+var EventExportColumns = []string{ExportColumnDate, ExportColumnName, ExportColumnEmail, ExportColumnStatus, ExportColumnCheckedIn, ExportColumnAnswers}
+
+// AnswersExportColumns sont les colonnes de l'export des réponses au formulaire d'un événement
+var AnswersExportColumns = []string{ExportColumnDate, ExportColumnName, ExportColumnEmail, ExportColumnStatus, ExportColumnAnswers}
+
+// AssociationExportColumns sont les colonnes par défaut de l'export des événements d'une association
+var AssociationExportColumns = append([]string{ExportColumnEvent}, EventExportColumns...)
+
+type ParticipantExportService struct {
+}
+
+func NewParticipantExportService() *ParticipantExportService {
+	return &ParticipantExportService{}
+}
+
+// ExportEventParticipants écrit la liste des inscrits de l'événement, une ligne par participation, les
+// réponses au formulaire sur une colonne par question. Pour une série, toutes les occurrences sont exportées.
+func (s *ParticipantExportService) ExportEventParticipants(event *models.Event, options ParticipantExportOptions, w io.Writer) error {
+	var events []models.Event
+	err := database.CurrentDatabase.
+		Where("id = ? OR recurrence_parent_id = ?", event.ID, event.ID).
+		Order("date").
+		Find(&events).Error
+	if err != nil {
+		return err
+	}
+
+	questions, err := findQuestions(database.CurrentDatabase, event.FormEventID())
+	if err != nil {
+		return err
+	}
+
+	exporter := newParticipantExporter(options, true, questions)
+	return exporter.export(events, w)
+}
+
+// ExportAssociationParticipants écrit la liste des inscrits des événements organisés ou co-organisés par
+// l'association qui commencent entre from et to. Les événements n'ayant pas le même formulaire, les réponses
+// sont regroupées dans une seule colonne.
+func (s *ParticipantExportService) ExportAssociationParticipants(associationID string, from time.Time, to time.Time, options ParticipantExportOptions, w io.Writer) error {
+	var events []models.Event
+	err := database.CurrentDatabase.
+		Scopes(HostedByScope(associationID)).
+		Where("events.date BETWEEN ? AND ?", from, to).
+		Order("events.date").
+		Find(&events).Error
+	if err != nil {
+		return err
+	}
+
+	exporter := newParticipantExporter(options, false, nil)
+	return exporter.export(events, w)
+}
+
+// participantExporter écrit les lignes de l'export. Avec perQuestion, les réponses occupent une colonne
+// par question de questions ; sinon une seule colonne, les questions étant chargées événement par événement.
+type participantExporter struct {
+	options     ParticipantExportOptions
+	perQuestion bool
+	questions   []models.EventQuestion
+	// Questions déjà chargées, par formulaire
+	forms map[string][]models.EventQuestion
+}
+
+func newParticipantExporter(options ParticipantExportOptions, perQuestion bool, questions []models.EventQuestion) *participantExporter {
+	return &participantExporter{
+		options:     options,
+		perQuestion: perQuestion,
+		questions:   questions,
+		forms:       make(map[string][]models.EventQuestion),
+	}
+}
+
+func (e *participantExporter) export(events []models.Event, w io.Writer) error {
+	writer, err := newRowWriter(e.options.Format, w)
+	if err != nil {
+		return err
+	}
+	if err := writer.Write(e.header()); err != nil {
+		return err
+	}
+
+	for i := range events {
+		event := &events[i]
+		var batch []models.Participation
+		result := database.CurrentDatabase.
+			Preload("User").
+			Preload("Answers").
+			Where("event_id = ? AND status <> ?", event.ID, enums.ParticipationDeclined).
+			FindInBatches(&batch, exportBatchSize, func(tx *gorm.DB, _ int) error {
+				for _, participation := range batch {
+					record, err := e.record(event, &participation)
+					if err != nil {
+						return err
+					}
+					if err := writer.Write(record); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	return writer.Close()
+}
+
+func (e *participantExporter) header() []string {
+	header := make([]string, 0, len(e.options.Columns)+len(e.questions))
+	for _, column := range e.options.Columns {
+		if column == ExportColumnAnswers && e.perQuestion {
+			for _, question := range e.questions {
+				header = append(header, question.Label)
+			}
+			continue
+		}
+		header = append(header, exportColumnHeaders[column])
+	}
+	return header
+}
+
+func (e *participantExporter) record(event *models.Event, participation *models.Participation) ([]string, error) {
+	record := make([]string, 0, len(e.options.Columns)+len(e.questions))
+	for _, column := range e.options.Columns {
+		switch column {
+		case ExportColumnEvent:
+			record = append(record, event.Name)
+		case ExportColumnDate:
+			record = append(record, event.Date.Format("2006-01-02 15:04"))
+		case ExportColumnName:
+			value := ""
+			if participation.User != nil {
+				value = participation.User.Name
+			}
+			record = append(record, value)
+		case ExportColumnEmail:
+			value := ""
+			if participation.User != nil {
+				value = participation.User.Email
+			}
+			record = append(record, value)
+		case ExportColumnStatus:
+			record = append(record, participation.Status)
+		case ExportColumnCheckedIn:
+			value := ""
+			if participation.CheckedInAt != nil {
+				value = participation.CheckedInAt.Format("2006-01-02 15:04")
+			}
+			record = append(record, value)
+		case ExportColumnAnswers:
+			answers, err := e.answers(event, participation)
+			if err != nil {
+				return nil, err
+			}
+			record = append(record, answers...)
+		}
+	}
+	return record, nil
+}
+
+// answers renvoie les cellules des réponses de la participation
+func (e *participantExporter) answers(event *models.Event, participation *models.Participation) ([]string, error) {
+	byQuestion := make(map[string]models.ParticipationAnswer, len(participation.Answers))
+	for _, answer := range participation.Answers {
+		byQuestion[answer.QuestionID] = answer
+	}
+
+	if e.perQuestion {
+		cells := make([]string, 0, len(e.questions))
+		for _, question := range e.questions {
+			cells = append(cells, formatAnswer(question, byQuestion[question.ID]))
+		}
+		return cells, nil
+	}
+
+	questions, ok := e.forms[event.FormEventID()]
+	if !ok {
+		var err error
+		questions, err = findQuestions(database.CurrentDatabase, event.FormEventID())
+		if err != nil {
+			return nil, err
+		}
+		e.forms[event.FormEventID()] = questions
+	}
+
+	var parts []string
+	for _, question := range questions {
+		if answer, ok := byQuestion[question.ID]; ok {
+			parts = append(parts, fmt.Sprintf("%s : %s", question.Label, formatAnswer(question, answer)))
+		}
+	}
+	return []string{strings.Join(parts, " ; ")}, nil
+}
+
+// escapeFormulas préfixe d'une apostrophe les cellules qu'un tableur interpréterait comme une formule,
+// les valeurs venant des utilisateurs (noms, réponses) ne devant jamais être exécutées à l'ouverture du fichier
+func escapeFormulas(record []string) []string {
+	escaped := make([]string, len(record))
+	for i, value := range record {
+		if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+			value = "'" + value
+		}
+		escaped[i] = value
+	}
+	return escaped
+}
+
+// rowWriter écrit les lignes d'un export au fil de l'eau
+type rowWriter interface {
+	Write(record []string) error
+	Close() error
+}
+
+func newRowWriter(format string, w io.Writer) (rowWriter, error) {
+	if format == ExportFormatXLSX {
+		return newXLSXRowWriter(w)
+	}
+	return &csvRowWriter{writer: csv.NewWriter(w)}, nil
+}
+
+type csvRowWriter struct {
+	writer *csv.Writer
+}
+
+func (c *csvRowWriter) Write(record []string) error {
+	return c.writer.Write(escapeFormulas(record))
+}
+
+func (c *csvRowWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// xlsxRowWriter s'appuie sur le StreamWriter d'excelize, qui déborde sur disque au-delà d'un certain volume
+type xlsxRowWriter struct {
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	w      io.Writer
+	row    int
+}
+
+func newXLSXRowWriter(w io.Writer) (*xlsxRowWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", exportSheet); err != nil {
+		return nil, err
+	}
+	stream, err := file.NewStreamWriter(exportSheet)
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxRowWriter{file: file, stream: stream, w: w}, nil
+}
+
+func (x *xlsxRowWriter) Write(record []string) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(record))
+	for i, value := range escapeFormulas(record) {
+		values[i] = value
+	}
+	return x.stream.SetRow(cell, values)
+}
+
+func (x *xlsxRowWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.w)
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/utils"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// openingBalanceReason est le motif de l'écriture reprenant le solde d'un utilisateur antérieur au registre
+const openingBalanceReason = "Solde initial"
+
+type PointsService struct{}
+
+func NewPointsService() *PointsService {
+	return &PointsService{}
+}
+
+// GetHistory renvoie les mouvements de points de l'utilisateur, du plus récent au plus ancien
+func (s *PointsService) GetHistory(userID string, pagination utils.Pagination) (*utils.Pagination, error) {
+	var entries []models.PointsEntry
+
+	query := database.CurrentDatabase.Model(&models.PointsEntry{}).Where("user_id = ?", userID)
+
+	err := query.Session(&gorm.Session{}).
+		Scopes(utils.Paginate(entries, &pagination, query.Session(&gorm.Session{}))).
+		Preload("Actor").
+		Order("created_at DESC, id DESC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = entries
+	return &pagination, nil
+}
+
+// Adjust enregistre un ajustement manuel, positif ou négatif, du solde de l'utilisateur
+func (s *PointsService) Adjust(userID string, amount int, reason string, actor *models.User) (*models.PointsEntry, error) {
+	reason = strings.TrimSpace(reason)
+	if amount == 0 || reason == "" || len(reason) > 255 {
+		return nil, coreErrors.ErrInvalidPointsAdjustment
+	}
+
+	entry := &models.PointsEntry{
+		UserID:  userID,
+		Amount:  amount,
+		Source:  enums.ManualPoints,
+		Reason:  reason,
+		ActorID: actorID(actor),
+	}
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Select("id").First(&user, "id = ?", userID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return coreErrors.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return recordPoints(tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishPoints(entry, nil)
+	return entry, nil
+}
+
+// Reverse annule une écriture en enregistrant le mouvement opposé. Une écriture ne peut être annulée qu'une
+// fois, et une annulation ne peut pas elle-même être annulée. Les points dépensés en récompenses ne sont
+// remboursés qu'en refusant ou en annulant la demande.
+func (s *PointsService) Reverse(entryID string, reason string, actor *models.User) (*models.PointsEntry, error) {
+	var reversal *models.PointsEntry
+	var entry models.PointsEntry
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entry, "id = ?", entryID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return coreErrors.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if entry.Source == enums.RedemptionPoints {
+			return coreErrors.ErrPointsNotReversible
+		}
+
+		reversal, err = reverseEntry(tx, &entry, reason, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// L'annulation est décomptée de la période où les points avaient été gagnés
+	publishPoints(reversal, &entry.CreatedAt)
+	return reversal, nil
+}
+
+// ReconcileBalances aligne le solde mis en cache dans users.points_open sur la somme du registre.
+// Le solde des utilisateurs sans aucune écriture, antérieur au registre, est d'abord repris en écriture
+// manuelle afin de ne pas être perdu. Renvoie le nombre de soldes corrigés.
+func (s *PointsService) ReconcileBalances() (int64, error) {
+	var userIDs []string
+	err := database.CurrentDatabase.Model(&models.User{}).
+		Where("points_open <> COALESCE((SELECT SUM(amount) FROM points_entries WHERE points_entries.user_id = users.id), 0)").
+		Pluck("id", &userIDs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var corrected int64
+	for _, userID := range userIDs {
+		fixed, err := reconcileBalance(userID)
+		if err != nil {
+			return corrected, err
+		}
+		if fixed {
+			corrected++
+		}
+	}
+	return corrected, nil
+}
+
+// reconcileBalance aligne le solde d'un utilisateur sur son registre. L'utilisateur est verrouillé avant de
+// lire le registre : une écriture concurrente, dont le solde est mis à jour sous ce même verrou, est soit déjà
+// validée et comptée dans la somme, soit ajoutée ensuite au solde corrigé.
+func reconcileBalance(userID string) (bool, error) {
+	fixed := false
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "points_open").First(&user, "id = ?", userID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var ledger struct {
+			Entries int64
+			Balance int
+		}
+		err = tx.Model(&models.PointsEntry{}).
+			Select("COUNT(*) AS entries, COALESCE(SUM(amount), 0) AS balance").
+			Where("user_id = ?", userID).
+			Scan(&ledger).Error
+		if err != nil {
+			return err
+		}
+
+		if ledger.Entries == 0 {
+			if user.PointsOpen == 0 {
+				return nil
+			}
+			return tx.Create(&models.PointsEntry{
+				UserID: user.ID,
+				Amount: user.PointsOpen,
+				Source: enums.ManualPoints,
+				Reason: openingBalanceReason,
+			}).Error
+		}
+
+		if user.PointsOpen == ledger.Balance {
+			return nil
+		}
+		fixed = true
+		return tx.Model(&user).Update("points_open", ledger.Balance).Error
+	})
+	return fixed, err
+}
+
+// recordPoints enregistre l'écriture et met à jour le solde de l'utilisateur dans la transaction tx,
+// de sorte que le mouvement et le solde ne puissent pas diverger. Une écriture nulle est ignorée.
+func recordPoints(tx *gorm.DB, entry *models.PointsEntry) error {
+	if entry.Amount == 0 {
+		return nil
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.User{}).Where("id = ?", entry.UserID).Update("points_open", gorm.Expr("points_open + ?", entry.Amount)).Error
+}
+
+// publishPoints reporte dans les classements une écriture enregistrée ; earnedAt, s'il est renseigné,
+// remplace la date de l'écriture pour déterminer les périodes concernées
+func publishPoints(entry *models.PointsEntry, earnedAt *time.Time) {
+	if entry == nil || entry.Amount == 0 {
+		return
+	}
+	at := entry.CreatedAt
+	if earnedAt != nil {
+		at = *earnedAt
+	}
+	NewLeaderboardService().Record(entry, at)
+}
+
+// reverseEntry enregistre dans la transaction tx le mouvement opposé à l'écriture, que l'appelant a verrouillée
+func reverseEntry(tx *gorm.DB, entry *models.PointsEntry, reason string, actor *models.User) (*models.PointsEntry, error) {
+	if entry.ReversalOfID != nil {
+		return nil, coreErrors.ErrPointsAlreadyReversed
+	}
+
+	var reversals int64
+	if err := tx.Model(&models.PointsEntry{}).Where("reversal_of_id = ?", entry.ID).Count(&reversals).Error; err != nil {
+		return nil, err
+	}
+	if reversals > 0 {
+		return nil, coreErrors.ErrPointsAlreadyReversed
+	}
+
+	reversal := &models.PointsEntry{
+		UserID:        entry.UserID,
+		Amount:        -entry.Amount,
+		Source:        entry.Source,
+		Reference:     entry.Reference,
+		Reason:        strings.TrimSpace(reason),
+		ActorID:       actorID(actor),
+		ReversalOfID:  &entry.ID,
+		AssociationID: entry.AssociationID,
+		CategoryID:    entry.CategoryID,
+	}
+	if err := recordPoints(tx, reversal); err != nil {
+		return nil, err
+	}
+	return reversal, nil
+}
+
+func optionalID(id string) *string {
+	if id == "" {
+		return nil
+	}
+	return &id
+}
+
+func actorID(actor *models.User) *string {
+	if actor == nil {
+		return nil
+	}
+	return &actor.ID
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	"backend/models"
+	"backend/utils"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// RecommendationHorizon limite les recommandations aux événements des prochaines semaines
+	RecommendationHorizon = 60 * 24 * time.Hour
+
+	// Poids de chaque critère dans le score d'une recommandation
+	categoryAffinityWeight    = 3.0
+	associationActivityWeight = 2.0
+	membersAttendingWeight    = 2.0
+	recencyWeight             = 1.0
+
+	// Au-delà de ces seuils, un critère n'augmente plus le score
+	categoryAffinityCap    = 5
+	associationActivityCap = 5
+	membersAttendingCap    = 5
+
+	// soonThreshold : un événement aussi proche est signalé comme imminent
+	soonThreshold = 7 * 24 * time.Hour
+)
+
+// Recommendation est un événement recommandé, son score et les raisons à afficher à l'utilisateur
+type Recommendation struct {
+	Event   models.Event `json:"event"`
+	Score   float64      `json:"score"`
+	Reasons []string     `json:"reasons"`
+}
+
+type RecommendationService struct {
+	eventService *EventService
+}
+
+func NewRecommendationService() *RecommendationService {
+	return &RecommendationService{
+		eventService: NewEventService(),
+	}
+}
+
+// userProfile résume l'historique de l'utilisateur utilisé pour le classement
+type userProfile struct {
+	// Événements passés auxquels l'utilisateur a participé, par catégorie puis par association organisatrice
+	attendedByCategory    map[string]int64
+	categoryNames         map[string]string
+	attendedByAssociation map[string]int64
+	// Associations dont l'utilisateur est membre accepté
+	memberships map[string]bool
+}
+
+// GetRecommendations classe les événements à venir visibles par l'utilisateur auxquels il n'est pas inscrit.
+// Le score combine ses participations passées par catégorie, son activité dans l'association organisatrice,
+// le nombre de membres de ses associations déjà inscrits et la proximité de la date.
+func (s *RecommendationService) GetRecommendations(user *models.User, now time.Time, pagination utils.Pagination) (*utils.Pagination, error) {
+	candidates, err := s.findCandidates(user, now)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := loadUserProfile(user.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	membersAttending, err := countMembersAttending(user.ID, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := make([]Recommendation, 0, len(candidates))
+	for _, event := range candidates {
+		recommendations = append(recommendations, scoreEvent(event, profile, membersAttending[occurrenceKey(&event)], now))
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Event.Date.Before(recommendations[j].Event.Date)
+	})
+
+	pagination.Rows = utils.PaginateSlice(recommendations, &pagination)
+	return &pagination, nil
+}
+
+// findCandidates renvoie les occurrences publiées, non annulées et non complètes des prochaines semaines
+// auxquelles l'utilisateur n'est pas inscrit
+func (s *RecommendationService) findCandidates(user *models.User, now time.Time) ([]models.Event, error) {
+	to := now.Add(RecommendationHorizon)
+
+	var events []models.Event
+	err := database.CurrentDatabase.
+		Preload("Category").
+		Preload("Association").
+		Preload("Venue").
+		Preload("RecurrenceExceptions").
+		Scopes(VisibleEventsScope(user)).
+		Where("events.publication_status = ?", enums.PublishedPublication).
+		Where("events.recurrence_parent_id IS NULL").
+		Where("(events.date >= ? OR events.recurrence_rule <> '')", now).
+		Where("events.date <= ?", to).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	expanded, err := s.eventService.ExpandOccurrences(events, now, to)
+	if err != nil {
+		return nil, err
+	}
+
+	seatsTaken, err := countSeatsTakenByEvent(expanded)
+	if err != nil {
+		return nil, err
+	}
+	joined, err := joinedEventIDs(user.ID, expanded)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]models.Event, 0, len(expanded))
+	for _, event := range expanded {
+		key := occurrenceKey(&event)
+		if event.Date.Before(now) || event.IsCancelled() || event.IsFull(seatsTaken[key]) || joined[key] {
+			continue
+		}
+		candidates = append(candidates, event)
+	}
+	return candidates, nil
+}
+
+// scoreEvent calcule le score de l'événement et les raisons correspondantes, de la plus à la moins importante
+func scoreEvent(event models.Event, profile *userProfile, membersAttending int64, now time.Time) Recommendation {
+	recommendation := Recommendation{Event: event, Reasons: []string{}}
+
+	if count := profile.attendedByCategory[event.CategoryID]; count > 0 && event.CategoryID != "" {
+		recommendation.Score += categoryAffinityWeight * cappedRatio(count, categoryAffinityCap)
+		recommendation.Reasons = append(recommendation.Reasons,
+			fmt.Sprintf("Parce que vous avez participé à %s « %s »", pluralize(count, "événement", "événements"), profile.categoryNames[event.CategoryID]))
+	}
+
+	if count := profile.attendedByAssociation[event.AssociationID]; count > 0 {
+		recommendation.Score += associationActivityWeight * cappedRatio(count, associationActivityCap)
+		recommendation.Reasons = append(recommendation.Reasons,
+			fmt.Sprintf("Vous avez participé à %s de %s", pluralize(count, "événement", "événements"), event.Association.Name))
+	} else if profile.memberships[event.AssociationID] {
+		recommendation.Score += associationActivityWeight / associationActivityCap
+		recommendation.Reasons = append(recommendation.Reasons,
+			fmt.Sprintf("Organisé par %s, dont vous êtes membre", event.Association.Name))
+	}
+
+	if membersAttending > 0 {
+		recommendation.Score += membersAttendingWeight * cappedRatio(membersAttending, membersAttendingCap)
+		recommendation.Reasons = append(recommendation.Reasons,
+			fmt.Sprintf("%s de vos associations %s", pluralize(membersAttending, "membre", "membres"), pluralizeVerb(membersAttending, "participe", "participent")))
+	}
+
+	// Plus l'événement est proche, plus il remonte
+	until := event.Date.Sub(now)
+	recommendation.Score += recencyWeight * math.Max(0, 1-until.Hours()/RecommendationHorizon.Hours())
+	if until <= soonThreshold {
+		recommendation.Reasons = append(recommendation.Reasons, "A lieu dans les 7 prochains jours")
+	}
+
+	recommendation.Score = math.Round(recommendation.Score*100) / 100
+	return recommendation
+}
+
+// loadUserProfile charge l'historique de participation et les adhésions de l'utilisateur
+func loadUserProfile(userID string, now time.Time) (*userProfile, error) {
+	profile := &userProfile{
+		attendedByCategory:    make(map[string]int64),
+		categoryNames:         make(map[string]string),
+		attendedByAssociation: make(map[string]int64),
+		memberships:           make(map[string]bool),
+	}
+
+	attended := database.CurrentDatabase.Model(&models.Participation{}).
+		Joins("JOIN events ON events.id = participations.event_id").
+		Where("participations.user_id = ? AND participations.is_attending = ?", userID, true).
+		Where("participations.status = ? OR participations.attendance = ?", enums.ParticipationConfirmed, enums.Present).
+		Where("events.date < ?", now)
+
+	var byCategory []struct {
+		CategoryID string
+		Name       string
+		Count      int64
+	}
+	err := attended.Session(&gorm.Session{}).
+		Select("events.category_id, categories.name, COUNT(*) AS count").
+		Joins("JOIN categories ON categories.id = events.category_id").
+		Group("events.category_id, categories.name").
+		Scan(&byCategory).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range byCategory {
+		profile.attendedByCategory[row.CategoryID] = row.Count
+		profile.categoryNames[row.CategoryID] = row.Name
+	}
+
+	var byAssociation []struct {
+		AssociationID string
+		Count         int64
+	}
+	err = attended.Session(&gorm.Session{}).
+		Select("events.association_id, COUNT(*) AS count").
+		Group("events.association_id").
+		Scan(&byAssociation).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range byAssociation {
+		profile.attendedByAssociation[row.AssociationID] = row.Count
+	}
+
+	var associationIDs []string
+	err = database.CurrentDatabase.Model(&models.Membership{}).
+		Where("user_id = ? AND status = ?", userID, enums.Accepted).
+		Pluck("association_id", &associationIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range associationIDs {
+		profile.memberships[id] = true
+	}
+
+	return profile, nil
+}
+
+// countMembersAttending compte, pour chaque événement enregistré, les membres des associations de l'utilisateur
+// qui y sont inscrits
+func countMembersAttending(userID string, events []models.Event) (map[string]int64, error) {
+	ids := eventKeys(events)
+	counts := make(map[string]int64, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		EventID string
+		Count   int64
+	}
+	err := database.CurrentDatabase.Model(&models.Participation{}).
+		Select("event_id, COUNT(DISTINCT user_id) AS count").
+		Where("event_id IN ? AND is_attending = ? AND user_id <> ?", ids, true, userID).
+		Where("user_id IN (SELECT user_id FROM memberships WHERE status = ? AND deleted_at IS NULL AND association_id IN "+
+			"(SELECT association_id FROM memberships WHERE user_id = ? AND status = ? AND deleted_at IS NULL))",
+			enums.Accepted, userID, enums.Accepted).
+		Group("event_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.EventID] = row.Count
+	}
+	return counts, nil
+}
+
+// joinedEventIDs renvoie les événements enregistrés auxquels l'utilisateur est inscrit ou en liste d'attente
+func joinedEventIDs(userID string, events []models.Event) (map[string]bool, error) {
+	joined := make(map[string]bool)
+	ids := eventKeys(events)
+	if len(ids) == 0 {
+		return joined, nil
+	}
+
+	var eventIDs []string
+	err := database.CurrentDatabase.Model(&models.Participation{}).
+		Where("event_id IN ? AND user_id = ?", ids, userID).
+		Where("is_attending = ? OR status = ?", true, enums.ParticipationWaitlisted).
+		Pluck("event_id", &eventIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range eventIDs {
+		joined[id] = true
+	}
+	return joined, nil
+}
+
+func cappedRatio(count int64, limit int64) float64 {
+	return float64(min(count, limit)) / float64(limit)
+}
+
+func pluralize(count int64, singular string, plural string) string {
+	if count > 1 {
+		return fmt.Sprintf("%d %s", count, plural)
+	}
+	return fmt.Sprintf("%d %s", count, singular)
+}
+
+func pluralizeVerb(count int64, singular string, plural string) string {
+	if count > 1 {
+		return plural
+	}
+	return singular
+}
@@ -0,0 +1,386 @@
+package services
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/utils"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// cancelledRedemptionReason est le motif du remboursement d'une demande annulée par l'utilisateur
+const cancelledRedemptionReason = "Demande de récompense annulée"
+
+// RewardFilter restreint le catalogue à une association ; IncludeInactive ajoute les récompenses retirées
+type RewardFilter struct {
+	AssociationID   string
+	IncludeInactive bool
+}
+
+type RewardService struct {
+	notificationService *NotificationService
+}
+
+func NewRewardService() *RewardService {
+	return &RewardService{
+		notificationService: NewNotificationService(),
+	}
+}
+
+// GetRewards renvoie le catalogue des récompenses, les moins chères en premier
+func (s *RewardService) GetRewards(filter RewardFilter, pagination utils.Pagination) (*utils.Pagination, error) {
+	var rewards []models.Reward
+
+	query := database.CurrentDatabase.Model(&models.Reward{})
+	if filter.AssociationID != "" {
+		query = query.Where("association_id = ?", filter.AssociationID)
+	}
+	if !filter.IncludeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	err := query.Session(&gorm.Session{}).
+		Scopes(utils.Paginate(rewards, &pagination, query.Session(&gorm.Session{}))).
+		Preload("Association").
+		Order("cost, name").
+		Find(&rewards).Error
+	if err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = rewards
+	return &pagination, nil
+}
+
+// GetReward renvoie une récompense du catalogue
+func (s *RewardService) GetReward(id string) (*models.Reward, error) {
+	var reward models.Reward
+	err := database.CurrentDatabase.First(&reward, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, coreErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reward, nil
+}
+
+// CreateReward ajoute une récompense au catalogue
+func (s *RewardService) CreateReward(reward *models.Reward) error {
+	reward.IsActive = true
+	if err := validateReward(reward); err != nil {
+		return err
+	}
+	return database.CurrentDatabase.Create(reward).Error
+}
+
+// RewardChanges regroupe les champs modifiables d'une récompense ; un champ absent reste inchangé.
+// UnlimitedStock retire la limite de stock.
+type RewardChanges struct {
+	Name           *string `json:"name"`
+	Description    *string `json:"description"`
+	ImageURL       *string `json:"image_url"`
+	Cost           *int    `json:"cost"`
+	Stock          *int    `json:"stock"`
+	UnlimitedStock bool    `json:"unlimited_stock"`
+	IsActive       *bool   `json:"is_active"`
+}
+
+// UpdateReward applique les modifications à la récompense, relue sous le même verrou que Redeem : le stock
+// n'est réécrit que s'il est modifié, de sorte qu'une demande simultanée ne soit jamais perdue. Le coût des
+// demandes déjà faites n'est pas modifié.
+func (s *RewardService) UpdateReward(rewardID string, changes RewardChanges) (*models.Reward, error) {
+	var reward models.Reward
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reward, "id = ?", rewardID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return coreErrors.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		columns := []string{"updated_at"}
+		if changes.Name != nil {
+			reward.Name = *changes.Name
+			columns = append(columns, "name")
+		}
+		if changes.Description != nil {
+			reward.Description = *changes.Description
+			columns = append(columns, "description")
+		}
+		if changes.ImageURL != nil {
+			reward.ImageURL = *changes.ImageURL
+			columns = append(columns, "image_url")
+		}
+		if changes.Cost != nil {
+			reward.Cost = *changes.Cost
+			columns = append(columns, "cost")
+		}
+		if changes.UnlimitedStock {
+			reward.Stock = nil
+			columns = append(columns, "stock")
+		} else if changes.Stock != nil {
+			reward.Stock = changes.Stock
+			columns = append(columns, "stock")
+		}
+		if changes.IsActive != nil {
+			reward.IsActive = *changes.IsActive
+			columns = append(columns, "is_active")
+		}
+
+		if err := validateReward(&reward); err != nil {
+			return err
+		}
+		return tx.Select(columns).Updates(&reward).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reward, nil
+}
+
+// DeleteReward supprime une récompense jamais demandée ; une récompense déjà demandée est seulement retirée
+// du catalogue afin de conserver l'historique des demandes
+func (s *RewardService) DeleteReward(reward *models.Reward) error {
+	var redemptions int64
+	if err := database.CurrentDatabase.Model(&models.RewardRedemption{}).Where("reward_id = ?", reward.ID).Count(&redemptions).Error; err != nil {
+		return err
+	}
+	if redemptions > 0 {
+		reward.IsActive = false
+		return database.CurrentDatabase.Model(reward).Update("is_active", false).Error
+	}
+	return database.CurrentDatabase.Delete(reward).Error
+}
+
+// Redeem débite le coût de la récompense du solde de l'utilisateur et crée une demande à retirer avec son
+// code. Le solde, le stock et le débit sont contrôlés et modifiés dans une même transaction.
+func (s *RewardService) Redeem(rewardID string, user *models.User) (*models.RewardRedemption, error) {
+	var redemption *models.RewardRedemption
+	err := database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		// Verrouiller l'utilisateur sérialise ses dépenses, et donc le contrôle du solde ;
+		// verrouiller la récompense sérialise le contrôle du stock
+		var current models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "points_open").First(&current, "id = ?", user.ID).Error; err != nil {
+			return err
+		}
+		var reward models.Reward
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reward, "id = ?", rewardID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return coreErrors.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if !reward.IsActive {
+			return coreErrors.ErrRewardUnavailable
+		}
+		if reward.Stock != nil && *reward.Stock <= 0 {
+			return coreErrors.ErrRewardOutOfStock
+		}
+		if current.PointsOpen < reward.Cost {
+			return coreErrors.ErrInsufficientPoints
+		}
+
+		if reward.Stock != nil {
+			if err := tx.Model(&reward).UpdateColumn("stock", gorm.Expr("stock - 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		redemption = &models.RewardRedemption{
+			Status:     enums.PendingRedemption,
+			Cost:       reward.Cost,
+			PickupCode: utils.GeneratePickupCode(),
+			RewardID:   reward.ID,
+			UserID:     user.ID,
+		}
+		if err := tx.Create(redemption).Error; err != nil {
+			return err
+		}
+		redemption.Reward = &reward
+
+		// Les points dépensés ne sont pas reportés dans les classements
+		return recordPoints(tx, &models.PointsEntry{
+			UserID:        user.ID,
+			Amount:        -reward.Cost,
+			Source:        enums.RedemptionPoints,
+			Reference:     &redemption.ID,
+			Reason:        "Récompense : " + reward.Name,
+			ActorID:       &user.ID,
+			AssociationID: reward.AssociationID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return redemption, nil
+}
+
+// GetUserRedemptions renvoie les demandes de récompenses de l'utilisateur, de la plus récente à la plus ancienne
+func (s *RewardService) GetUserRedemptions(userID string, pagination utils.Pagination) (*utils.Pagination, error) {
+	var redemptions []models.RewardRedemption
+
+	query := database.CurrentDatabase.Model(&models.RewardRedemption{}).Where("user_id = ?", userID)
+
+	err := query.Session(&gorm.Session{}).
+		Scopes(utils.Paginate(redemptions, &pagination, query.Session(&gorm.Session{}))).
+		Preload("Reward").
+		Order("created_at DESC, id DESC").
+		Find(&redemptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	pagination.Rows = redemptions
+	return &pagination, nil
+}
+
+// GetRewardRedemptions renvoie les demandes d'une récompense, les plus anciennes en premier, éventuellement
+// restreintes à un statut. Les codes de retrait, que seuls les demandeurs doivent connaître, sont masqués.
+func (s *RewardService) GetRewardRedemptions(reward *models.Reward, status enums.RedemptionStatus, pagination utils.Pagination) (*utils.Pagination, error) {
+	var redemptions []models.RewardRedemption
+
+	query := database.CurrentDatabase.Model(&models.RewardRedemption{}).Where("reward_id = ?", reward.ID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Session(&gorm.Session{}).
+		Scopes(utils.Paginate(redemptions, &pagination, query.Session(&gorm.Session{}))).
+		Preload("User").
+		Order("created_at, id").
+		Find(&redemptions).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range redemptions {
+		redemptions[i].PickupCode = ""
+	}
+
+	pagination.Rows = redemptions
+	return &pagination, nil
+}
+
+// GetRedemption renvoie une demande de récompense avec sa récompense
+func (s *RewardService) GetRedemption(id string) (*models.RewardRedemption, error) {
+	var redemption models.RewardRedemption
+	err := database.CurrentDatabase.Preload("Reward").First(&redemption, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, coreErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &redemption, nil
+}
+
+// GetRedemptionByCode renvoie la demande correspondant au code de retrait présenté par l'utilisateur
+func (s *RewardService) GetRedemptionByCode(code string) (*models.RewardRedemption, error) {
+	var redemption models.RewardRedemption
+	err := database.CurrentDatabase.Preload("Reward").Preload("User").
+		First(&redemption, "pickup_code = ?", strings.ToUpper(strings.TrimSpace(code))).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, coreErrors.ErrInvalidPickupCode
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &redemption, nil
+}
+
+// Fulfil enregistre la remise de la récompense par le responsable actor
+func (s *RewardService) Fulfil(redemption *models.RewardRedemption, actor *models.User, now time.Time) error {
+	return database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := lockPendingRedemption(tx, redemption); err != nil {
+			return err
+		}
+		return updateRedemptionStatus(tx, redemption, enums.FulfilledRedemption, "", actor, now)
+	})
+}
+
+// Reject refuse la demande : les points sont rendus à l'utilisateur, qui en est prévenu, et la récompense
+// est remise en stock
+func (s *RewardService) Reject(redemption *models.RewardRedemption, reason string, actor *models.User, now time.Time) error {
+	if err := s.refund(redemption, enums.RejectedRedemption, strings.TrimSpace(reason), actor, now); err != nil {
+		return err
+	}
+
+	var user models.User
+	if err := database.CurrentDatabase.First(&user, "id = ?", redemption.UserID).Error; err == nil && redemption.Reward != nil {
+		go s.notificationService.NotifyRedemptionRejected(&user, redemption.Reward, redemption)
+	}
+	return nil
+}
+
+// Cancel annule la demande de l'utilisateur tant qu'elle n'a pas été traitée, en lui rendant ses points
+func (s *RewardService) Cancel(redemption *models.RewardRedemption, user *models.User, now time.Time) error {
+	return s.refund(redemption, enums.CancelledRedemption, "", user, now)
+}
+
+// refund clôt la demande en attente avec le statut donné, annule son débit dans le registre et rend
+// l'exemplaire au stock, dans une même transaction
+func (s *RewardService) refund(redemption *models.RewardRedemption, status enums.RedemptionStatus, reason string, actor *models.User, now time.Time) error {
+	return database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := lockPendingRedemption(tx, redemption); err != nil {
+			return err
+		}
+
+		var debit models.PointsEntry
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&debit, "source = ? AND reference = ? AND reversal_of_id IS NULL", enums.RedemptionPoints, redemption.ID).Error
+		if err != nil {
+			return err
+		}
+		refundReason := reason
+		if refundReason == "" {
+			refundReason = cancelledRedemptionReason
+		}
+		if _, err := reverseEntry(tx, &debit, refundReason, actor); err != nil {
+			return err
+		}
+
+		err = tx.Model(&models.Reward{}).
+			Where("id = ? AND stock IS NOT NULL", redemption.RewardID).
+			UpdateColumn("stock", gorm.Expr("stock + 1")).Error
+		if err != nil {
+			return err
+		}
+
+		return updateRedemptionStatus(tx, redemption, status, reason, actor, now)
+	})
+}
+
+// lockPendingRedemption verrouille la demande et vérifie qu'elle n'a pas déjà été traitée
+func lockPendingRedemption(tx *gorm.DB, redemption *models.RewardRedemption) error {
+	var current models.RewardRedemption
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, "id = ?", redemption.ID).Error; err != nil {
+		return err
+	}
+	if current.Status != enums.PendingRedemption {
+		return coreErrors.ErrRedemptionAlreadyHandled
+	}
+	return nil
+}
+
+func updateRedemptionStatus(tx *gorm.DB, redemption *models.RewardRedemption, status enums.RedemptionStatus, reason string, actor *models.User, now time.Time) error {
+	redemption.Status = status
+	redemption.Reason = reason
+	redemption.HandledAt = &now
+	redemption.HandledByID = actorID(actor)
+	return tx.Select("status", "reason", "handled_at", "handled_by_id", "updated_at").Updates(redemption).Error
+}
+
+func validateReward(reward *models.Reward) error {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	return validate.Struct(reward)
+}
@@ -13,17 +13,20 @@ import (
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
 )
 
 // services/user_service.go
 
 type UserService struct {
-	authService *AuthService
+	authService  *AuthService
+	badgeService *BadgeService
 }
 
 func NewUserService() *UserService {
 	return &UserService{
-		authService: NewAuthService(),
+		authService:  NewAuthService(),
+		badgeService: NewBadgeService(),
 	}
 }
 
@@ -116,6 +119,10 @@ func (s *UserService) FindByID(id string) (*models.User, error) {
 		Preload("AssociationsOwned").
 		Preload("Memberships").
 		Preload("Associations").
+		Preload("Badges", func(db *gorm.DB) *gorm.DB {
+			return db.Order("awarded_at DESC")
+		}).
+		Preload("Badges.Badge").
 		First(&user, "id = ?", id)
 	if result.Error != nil {
 		return nil, errors.ErrNotFound
@@ -211,62 +218,83 @@ func (s *UserService) JoinAssociation(userID, associationID, code string) (bool,
 	if err := database.CurrentDatabase.Create(&newMembership).Error; err != nil {
 		return false, err
 	}
+	s.badgeService.Refresh(userID, enums.JoinedAssociationsCriterion)
 
 	return true, nil
 }
 
+// PrivacySettings regroupe les paramètres de confidentialité modifiables par l'utilisateur
+type PrivacySettings struct {
+	HideFromLeaderboards bool `json:"hide_from_leaderboards"`
+}
+
+// UpdatePrivacySettings enregistre les paramètres de confidentialité de l'utilisateur ; ils s'appliquent
+// immédiatement aux classements
+func (s *UserService) UpdatePrivacySettings(userID string, settings PrivacySettings) error {
+	return database.CurrentDatabase.Model(&models.User{}).Where("id = ?", userID).
+		Update("hide_from_leaderboards", settings.HideFromLeaderboards).Error
+}
+
 type UserFilter struct {
 	database.Filter
 	Column string `json:"column" validate:"required,oneof=name email"`
 }
 
 func (s *UserService) GetUserEvents(userID string, pagination utils.Pagination) (*utils.Pagination, error) {
-	var events []models.Event
+	from := utils.StartOfDay(time.Now())
 
-	query := database.CurrentDatabase.
-		Joins("JOIN participations ON participations.event_id = events.id").
-		Where("participations.user_id = ?", userID).
-		Where("date >= ?", time.Now().Format(models.DateFormat)).
-		Preload("Participants").
-		Preload("Category").
-		Preload("Association").
-		Order("date")
-
-	query.Scopes(utils.Paginate(events, &pagination, query)).Find(&events)
+	events, err := s.FindParticipatedEvents(userID, from)
+	if err != nil {
+		return nil, err
+	}
 
-	pagination.Rows = events
+	pagination.Rows = utils.PaginateSlice(events, &pagination)
 
 	return &pagination, nil
 }
 
-func (s *UserService) GetAssociationsEvents(userID string, pagination utils.Pagination) (*utils.Pagination, error) {
-	var memberships []models.Membership
-	query := database.CurrentDatabase.
-		Where("user_id = ?", userID).
-		Preload("Association.Events")
+// FindParticipatedEvents renvoie les événements auxquels l'utilisateur est inscrit depuis from.
+// Les participations se font par occurrence matérialisée : une participation rattachée à la série
+// elle-même ne désigne aucune date et n'est pas renvoyée.
+func (s *UserService) FindParticipatedEvents(userID string, from time.Time) ([]models.Event, error) {
+	var events []models.Event
 
-	err := query.Find(&memberships).Error
+	err := database.CurrentDatabase.
+		Joins("JOIN participations ON participations.event_id = events.id").
+		Where("participations.user_id = ?", userID).
+		Where("events.recurrence_rule = ''").
+		Where(eventEndsAtSQL+" >= ?", from).
+		Preload("Participations").
+		Preload("Category").
+		Preload("Association").
+		Preload("Venue").
+		Preload("RecurrenceExceptions").
+		Order("events.date").
+		Find(&events).Error
 	if err != nil {
 		return nil, err
 	}
 
-	var events []models.Event
-	for _, membership := range memberships {
-		for _, event := range membership.Association.Events {
-			events = append(events, event)
-		}
-	}
+	return events, nil
+}
 
-	eventIDs := getEventIDs(events)
+// GetAssociationsEvents renvoie les événements des associations de l'utilisateur auxquels il ne participe pas
+// encore, limités à ceux qu'il peut consulter
+func (s *UserService) GetAssociationsEvents(user *models.User, pagination utils.Pagination) (*utils.Pagination, error) {
+	memberships := database.CurrentDatabase.
+		Model(&models.Membership{}).
+		Select("association_id").
+		Where("user_id = ?", user.ID)
 
 	var enrichedEvents []models.Event
-	err = database.CurrentDatabase.
+	err := database.CurrentDatabase.
 		Model(&models.Event{}).
 		Preload("Category").
 		Preload("Association").
 		Preload("Participations").
-		Joins("LEFT JOIN participations ON participations.event_id = events.id AND participations.user_id = ?", userID).
-		Where("events.id IN ? AND participations.id IS NULL", eventIDs).
+		Joins("LEFT JOIN participations ON participations.event_id = events.id AND participations.user_id = ?", user.ID).
+		Scopes(VisibleEventsScope(user), HostedByScope(memberships)).
+		Where("participations.id IS NULL").
 		Find(&enrichedEvents).Error
 	if err != nil {
 		return nil, err
@@ -275,12 +303,3 @@ func (s *UserService) GetAssociationsEvents(userID string, pagination utils.Pagi
 	pagination.Rows = enrichedEvents
 	return &pagination, nil
 }
-
-func getEventIDs(events []models.Event) []string {
-	ids := make([]string, len(events))
-	for i, event := range events {
-		ids[i] = event.ID
-		fmt.Printf("Adding ID: %s\n", event.ID) // Débuggons chaque ID
-	}
-	return ids
-}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"backend/database"
+	coreErrors "backend/errors"
+	"backend/models"
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+type VenueService struct {
+}
+
+func NewVenueService() *VenueService {
+	return &VenueService{}
+}
+
+// GetAssociationVenues renvoie les lieux enregistrés par l'association
+func (s *VenueService) GetAssociationVenues(associationID string) ([]models.Venue, error) {
+	var venues []models.Venue
+	err := database.CurrentDatabase.Where("association_id = ?", associationID).Order("name").Find(&venues).Error
+	return venues, err
+}
+
+// GetVenue renvoie un lieu de l'association
+func (s *VenueService) GetVenue(associationID string, venueID string) (*models.Venue, error) {
+	var venue models.Venue
+	err := database.CurrentDatabase.First(&venue, "id = ? AND association_id = ?", venueID, associationID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, coreErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+func (s *VenueService) CreateVenue(venue *models.Venue) error {
+	if err := validateVenue(venue); err != nil {
+		return err
+	}
+	return database.CurrentDatabase.Create(venue).Error
+}
+
+// UpdateVenue enregistre le lieu et reporte son nom sur les événements qui s'y déroulent
+func (s *VenueService) UpdateVenue(venue *models.Venue) error {
+	if err := validateVenue(venue); err != nil {
+		return err
+	}
+
+	return database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Venue{ID: venue.ID}).Updates(map[string]interface{}{
+			"name":        venue.Name,
+			"address":     venue.Address,
+			"postal_code": venue.PostalCode,
+			"city":        venue.City,
+			"latitude":    venue.Latitude,
+			"longitude":   venue.Longitude,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Event{}).Where("venue_id = ?", venue.ID).Update("location", venue.Name).Error
+	})
+}
+
+// DeleteVenue supprime le lieu ; les événements et modèles qui s'y déroulent gardent son nom comme lieu
+func (s *VenueService) DeleteVenue(venue *models.Venue) error {
+	return database.CurrentDatabase.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Event{}).Where("venue_id = ?", venue.ID).Update("venue_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.EventTemplate{}).Where("venue_id = ?", venue.ID).Update("venue_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Venue{}, "id = ?", venue.ID).Error
+	})
+}
+
+func validateVenue(venue *models.Venue) error {
+	venue.Name = strings.TrimSpace(venue.Name)
+	venue.Address = strings.TrimSpace(venue.Address)
+	venue.PostalCode = strings.TrimSpace(venue.PostalCode)
+	venue.City = strings.TrimSpace(venue.City)
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	return validate.Struct(venue)
+}
+
+// applyVenue vérifie que le lieu choisi appartient à l'association de l'événement et reprend son nom
+// comme lieu de l'événement
+func applyVenue(db *gorm.DB, event *models.Event) error {
+	if event.VenueID == nil || *event.VenueID == "" {
+		event.VenueID = nil
+		event.Venue = nil
+		return nil
+	}
+
+	var venue models.Venue
+	err := db.First(&venue, "id = ? AND association_id = ?", *event.VenueID, event.AssociationID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return coreErrors.ErrInvalidVenue
+	}
+	if err != nil {
+		return err
+	}
+
+	event.Venue = &venue
+	event.Location = venue.Name
+	return nil
+}
@@ -3,6 +3,7 @@ package swagger
 import (
 	"backend/controllers"
 	"backend/models"
+	"backend/services"
 	"net/http"
 
 	"github.com/zc2638/swag"
@@ -11,6 +12,11 @@ import (
 
 func SetupAssociationSwagger(api *swag.API) {
 	associationController := controllers.NewAssociationController()
+	eventImportController := controllers.NewEventImportController()
+	venueController := controllers.NewVenueController()
+	eventFeedbackController := controllers.NewEventFeedbackController()
+	eventCoHostController := controllers.NewEventCoHostController()
+	participantExportController := controllers.NewParticipantExportController()
 
 	// Endpoint: Get All Associations
 	api.AddEndpoint(
@@ -62,4 +68,174 @@ func SetupAssociationSwagger(api *swag.API) {
 			endpoint.Tags("Associations"),
 		),
 	)
+
+	// Endpoint: Preview Events Import
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/associations/{associationId}/events/import/preview",
+			endpoint.Handler(eventImportController.PreviewImport),
+			endpoint.Summary("Preview an iCalendar import"),
+			endpoint.Description("Lists the events an iCalendar file or URL would create, update or skip, without saving anything. Reserved to the association owner and administrators"),
+			endpoint.Response(http.StatusForbidden, "User is not the owner of the association"),
+			endpoint.Path("associationId", "string", "ID of the association", true),
+			endpoint.FormData("file", "file", "iCalendar (.ics) file to import", false),
+			endpoint.FormData("url", "string", "URL of the calendar to fetch when no file is sent (http, https or webcal)", false),
+			endpoint.FormData("category_id", "string", "Category assigned to created events without a matching CATEGORIES value", true),
+			endpoint.Response(http.StatusOK, "Import preview", endpoint.SchemaResponseOption(services.EventImportReport{})),
+			endpoint.Response(http.StatusBadRequest, "Missing file or invalid URL"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid iCalendar data or unknown category"),
+			endpoint.Response(http.StatusRequestEntityTooLarge, "Calendar too large"),
+			endpoint.Response(http.StatusBadGateway, "Remote calendar could not be fetched"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Associations"),
+		),
+	)
+
+	// Endpoint: Import Events
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/associations/{associationId}/events/import",
+			endpoint.Handler(eventImportController.ImportEvents),
+			endpoint.Summary("Import events from an iCalendar file or URL"),
+			endpoint.Description("Creates or updates the association's events from an iCalendar source. Events are matched on their source UID, so importing the same calendar again does not create duplicates. Reserved to the association owner and administrators"),
+			endpoint.Response(http.StatusForbidden, "User is not the owner of the association"),
+			endpoint.Path("associationId", "string", "ID of the association", true),
+			endpoint.FormData("file", "file", "iCalendar (.ics) file to import", false),
+			endpoint.FormData("url", "string", "URL of the calendar to fetch when no file is sent (http, https or webcal)", false),
+			endpoint.FormData("category_id", "string", "Category assigned to created events without a matching CATEGORIES value", true),
+			endpoint.Response(http.StatusOK, "Import report", endpoint.SchemaResponseOption(services.EventImportReport{})),
+			endpoint.Response(http.StatusBadRequest, "Missing file or invalid URL"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid iCalendar data or unknown category"),
+			endpoint.Response(http.StatusRequestEntityTooLarge, "Calendar too large"),
+			endpoint.Response(http.StatusBadGateway, "Remote calendar could not be fetched"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Associations"),
+		),
+	)
+
+	// Endpoint: Get Association Venues
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/associations/{associationId}/venues",
+			endpoint.Handler(venueController.GetAssociationVenues),
+			endpoint.Summary("List the venues of an association"),
+			endpoint.Description("Fetches the geolocated venues shared by the association's events"),
+			endpoint.Path("associationId", "string", "ID of the association", true),
+			endpoint.Response(http.StatusOK, "List of venues", endpoint.SchemaResponseOption([]models.Venue{})),
+			endpoint.Response(http.StatusForbidden, "User is not a member of the association"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Associations"),
+		),
+	)
+
+	// Endpoint: Create Venue
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/associations/{associationId}/venues",
+			endpoint.Handler(venueController.CreateVenue),
+			endpoint.Summary("Create a venue"),
+			endpoint.Description("Adds a venue with its address and coordinates to the association. Reserved to the association owner and administrators"),
+			endpoint.Response(http.StatusForbidden, "User is not the owner of the association"),
+			endpoint.Path("associationId", "string", "ID of the association", true),
+			endpoint.Body(models.Venue{}, "Venue to create", true),
+			endpoint.Response(http.StatusCreated, "Created venue", endpoint.SchemaResponseOption(models.Venue{})),
+			endpoint.Response(http.StatusBadRequest, "Invalid request body"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Validation error"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Associations"),
+		),
+	)
+
+	// Endpoint: Update Venue
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPut, "/associations/{associationId}/venues/{venueId}",
+			endpoint.Handler(venueController.UpdateVenue),
+			endpoint.Summary("Update a venue"),
+			endpoint.Description("Updates the venue; the events held there take its new name as location. Reserved to the association owner and administrators"),
+			endpoint.Response(http.StatusForbidden, "User is not the owner of the association"),
+			endpoint.Path("associationId", "string", "ID of the association", true),
+			endpoint.Path("venueId", "string", "ID of the venue", true),
+			endpoint.Body(models.Venue{}, "Updated venue", true),
+			endpoint.Response(http.StatusOK, "Updated venue", endpoint.SchemaResponseOption(models.Venue{})),
+			endpoint.Response(http.StatusNotFound, "Venue not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Validation error"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Associations"),
+		),
+	)
+
+	// Endpoint: Delete Venue
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodDelete, "/associations/{associationId}/venues/{venueId}",
+			endpoint.Handler(venueController.DeleteVenue),
+			endpoint.Summary("Delete a venue"),
+			endpoint.Description("Deletes the venue; the events held there keep its name as location. Reserved to the association owner and administrators"),
+			endpoint.Response(http.StatusForbidden, "User is not the owner of the association"),
+			endpoint.Path("associationId", "string", "ID of the association", true),
+			endpoint.Path("venueId", "string", "ID of the venue", true),
+			endpoint.Response(http.StatusNoContent, "Venue deleted"),
+			endpoint.Response(http.StatusNotFound, "Venue not found"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Associations"),
+		),
+	)
+
+	// Endpoint: Get Association Feedback
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/associations/{associationId}/feedback",
+			endpoint.Handler(eventFeedbackController.GetAssociationFeedback),
+			endpoint.Summary("Retrieve the feedback of an association"),
+			endpoint.Description("Returns the ratings left on all the association's events, overall and per event"),
+			endpoint.Path("associationId", "string", "ID of the association", true),
+			endpoint.Response(http.StatusOK, "Association feedback", endpoint.SchemaResponseOption(services.AssociationFeedbackReport{})),
+			endpoint.Response(http.StatusForbidden, "User is not the leader of the association"),
+			endpoint.Response(http.StatusNotFound, "Association not found"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Associations"),
+		),
+	)
+
+	// Endpoint: Get Co-host Invitations
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/associations/{associationId}/co-host-invitations",
+			endpoint.Handler(eventCoHostController.GetPendingInvitations),
+			endpoint.Summary("Retrieve the pending co-host invitations of an association"),
+			endpoint.Description("Lists the events other associations invited this association to co-host, awaiting an answer"),
+			endpoint.Path("associationId", "string", "ID of the association", true),
+			endpoint.Response(http.StatusOK, "Pending invitations", endpoint.SchemaResponseOption([]models.EventCoHost{})),
+			endpoint.Response(http.StatusForbidden, "User is not the leader of the association"),
+			endpoint.Response(http.StatusNotFound, "Association not found"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Associations"),
+		),
+	)
+
+	// Endpoint: Export Association Participants
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/associations/{associationId}/participants/export",
+			endpoint.Handler(participantExportController.ExportAssociationParticipants),
+			endpoint.Summary("Export the participants of an association's events"),
+			endpoint.Description("Streams a CSV or XLSX file with one line per registration (declined ones excluded) to the events hosted or co-hosted by the association starting between from and to. Registration answers are grouped in a single column"),
+			endpoint.Path("associationId", "string", "ID of the association", true),
+			endpoint.Query("from", "string", "RFC 3339 start of the range", true),
+			endpoint.Query("to", "string", "RFC 3339 end of the range", true),
+			endpoint.Query("format", "string", "csv (default) or xlsx", false),
+			endpoint.Query("columns", "string", "Comma-separated columns among event, date, name, email, status, checked_in_at and answers (defaults to all)", false),
+			endpoint.Response(http.StatusOK, "CSV or XLSX file"),
+			endpoint.Response(http.StatusBadRequest, "Invalid dates, format or column"),
+			endpoint.Response(http.StatusForbidden, "User is not the leader of the association"),
+			endpoint.Response(http.StatusNotFound, "Association not found"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Associations"),
+		),
+	)
 }
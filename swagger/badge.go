@@ -0,0 +1,91 @@
+package swagger
+
+import (
+	"backend/controllers"
+	"backend/models"
+	"backend/resources"
+	"net/http"
+
+	"github.com/zc2638/swag"
+	"github.com/zc2638/swag/endpoint"
+)
+
+func SetupBadgeSwagger(api *swag.API) {
+	badgeController := controllers.NewBadgeController()
+
+	// Endpoint: Get Badges
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/badges",
+			endpoint.Handler(badgeController.GetBadges),
+			endpoint.Summary("Retrieve badge definitions"),
+			endpoint.Description("Lists the badges members can earn, with the rule awarding each of them"),
+			endpoint.Response(http.StatusOK, "Badge definitions", endpoint.SchemaResponseOption([]models.Badge{})),
+			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Badges"),
+		),
+	)
+
+	// Endpoint: Create Badge
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/badges",
+			endpoint.Handler(badgeController.CreateBadge),
+			endpoint.Summary("Create a badge"),
+			endpoint.Description("Creates a badge awarded once the user's count for the criterion reaches the threshold. Criteria: attended_events (confirmed or checked-in participations, optionally restricted to category_id), joined_associations (accepted memberships), organised_events (published events of the associations the user leads) and completed_shifts (volunteer shifts completed). Users who already meet the rule receive the badge immediately. Admin only"),
+			endpoint.Body(models.Badge{}, "Badge definition", true),
+			endpoint.Response(http.StatusCreated, "Created badge", endpoint.SchemaResponseOption(models.Badge{})),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid rule or unknown category"),
+			endpoint.Response(http.StatusForbidden, "User is not an admin"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Badges"),
+		),
+	)
+
+	// Endpoint: Update Badge
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPut, "/badges/{id}",
+			endpoint.Handler(badgeController.UpdateBadge),
+			endpoint.Summary("Update a badge"),
+			endpoint.Description("Replaces the badge definition. Badges already awarded are kept; users meeting the new rule receive it. Admin only"),
+			endpoint.Path("id", "string", "ID of the badge", true),
+			endpoint.Body(models.Badge{}, "Badge definition", true),
+			endpoint.Response(http.StatusOK, "Updated badge", endpoint.SchemaResponseOption(models.Badge{})),
+			endpoint.Response(http.StatusNotFound, "Badge not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid rule or unknown category"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Badges"),
+		),
+	)
+
+	// Endpoint: Delete Badge
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodDelete, "/badges/{id}",
+			endpoint.Handler(badgeController.DeleteBadge),
+			endpoint.Summary("Delete a badge"),
+			endpoint.Description("Deletes the badge definition and removes it from the users who earned it. Admin only"),
+			endpoint.Path("id", "string", "ID of the badge", true),
+			endpoint.Response(http.StatusNoContent, "Badge deleted"),
+			endpoint.Response(http.StatusNotFound, "Badge not found"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Badges"),
+		),
+	)
+
+	// Endpoint: Get My Badges
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/me/badges",
+			endpoint.Handler(badgeController.GetMyBadges),
+			endpoint.Summary("Retrieve my badges"),
+			endpoint.Description("Lists the badges earned by the authenticated user, most recent first. The same list is returned in the badges field of GET /users/{id}"),
+			endpoint.Response(http.StatusOK, "Earned badges", endpoint.SchemaResponseOption([]resources.BadgeResource{})),
+			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Badges"),
+		),
+	)
+}
@@ -0,0 +1,115 @@
+package swagger
+
+import (
+	"backend/controllers"
+	"net/http"
+
+	"github.com/zc2638/swag"
+	"github.com/zc2638/swag/endpoint"
+)
+
+func SetupCalendarSwagger(api *swag.API) {
+	calendarController := controllers.NewCalendarController()
+
+	calendarFeedSchema := endpoint.SchemaResponseOption(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"token": map[string]interface{}{"type": "string"},
+			"url":   map[string]interface{}{"type": "string"},
+		},
+	})
+
+	// Endpoint: User Calendar Feed
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/calendars/feeds/users/{token}/events.ics",
+			endpoint.Handler(calendarController.GetUserFeed),
+			endpoint.Summary("iCalendar feed of a user"),
+			endpoint.Description("Returns the events the user participates in as an iCalendar (RFC 5545) feed. The token in the URL acts as authentication for calendar applications"),
+			endpoint.Path("token", "string", "Calendar token of the user", true),
+			endpoint.Response(http.StatusOK, "iCalendar feed (text/calendar)"),
+			endpoint.Response(http.StatusNotFound, "Unknown or revoked token"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Tags("Calendars"),
+		),
+	)
+
+	// Endpoint: Association Calendar Feed
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/calendars/feeds/associations/{token}/events.ics",
+			endpoint.Handler(calendarController.GetAssociationFeed),
+			endpoint.Summary("iCalendar feed of an association"),
+			endpoint.Description("Returns the upcoming events of the association as an iCalendar (RFC 5545) feed, recurring events included with their RRULE"),
+			endpoint.Path("token", "string", "Calendar token of the association", true),
+			endpoint.Response(http.StatusOK, "iCalendar feed (text/calendar)"),
+			endpoint.Response(http.StatusNotFound, "Unknown or revoked token"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Tags("Calendars"),
+		),
+	)
+
+	// Endpoint: Get User Calendar
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/calendars/me",
+			endpoint.Handler(calendarController.GetUserCalendar),
+			endpoint.Summary("Get the calendar feed URL of the current user"),
+			endpoint.Description("Returns the subscription URL of the user's calendar feed, creating the token on first call"),
+			endpoint.Response(http.StatusOK, "Calendar token and feed URL", calendarFeedSchema),
+			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Calendars"),
+		),
+	)
+
+	// Endpoint: Regenerate User Calendar Token
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/calendars/me/token",
+			endpoint.Handler(calendarController.RegenerateUserCalendarToken),
+			endpoint.Summary("Regenerate the calendar token of the current user"),
+			endpoint.Description("Replaces the calendar token: the previous feed URL stops working immediately"),
+			endpoint.Response(http.StatusOK, "New calendar token and feed URL", calendarFeedSchema),
+			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Calendars"),
+		),
+	)
+
+	// Endpoint: Get Association Calendar
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/calendars/associations/{associationId}",
+			endpoint.Handler(calendarController.GetAssociationCalendar),
+			endpoint.Summary("Get the calendar feed URL of an association"),
+			endpoint.Description("Returns the subscription URL of the association's calendar feed. Only members can access it"),
+			endpoint.Path("associationId", "string", "ID of the association", true),
+			endpoint.Response(http.StatusOK, "Calendar token and feed URL", calendarFeedSchema),
+			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
+			endpoint.Response(http.StatusForbidden, "User is not a member of the association"),
+			endpoint.Response(http.StatusNotFound, "Association not found"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Calendars"),
+		),
+	)
+
+	// Endpoint: Regenerate Association Calendar Token
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/calendars/associations/{associationId}/token",
+			endpoint.Handler(calendarController.RegenerateAssociationCalendarToken),
+			endpoint.Summary("Regenerate the calendar token of an association"),
+			endpoint.Description("Replaces the association's calendar token. Reserved to the association owner and administrators"),
+			endpoint.Path("associationId", "string", "ID of the association", true),
+			endpoint.Response(http.StatusOK, "New calendar token and feed URL", calendarFeedSchema),
+			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Association not found"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Calendars"),
+		),
+	)
+}
@@ -3,6 +3,7 @@ package swagger
 import (
 	"backend/controllers"
 	"backend/models"
+	"backend/services"
 	"net/http"
 
 	"github.com/zc2638/swag"
@@ -11,6 +12,15 @@ import (
 
 func SetupEventSwagger(api *swag.API) {
 	eventController := controllers.NewEventController()
+	eventFormController := controllers.NewEventFormController()
+	eventInvitationController := controllers.NewEventInvitationController()
+	eventFeedbackController := controllers.NewEventFeedbackController()
+	eventPhotoController := controllers.NewEventPhotoController()
+	eventCoHostController := controllers.NewEventCoHostController()
+	eventCommentController := controllers.NewEventCommentController()
+	participantExportController := controllers.NewParticipantExportController()
+	eventShiftController := controllers.NewEventShiftController()
+	eventTemplateController := controllers.NewEventTemplateController()
 
 	// Endpoint: Create Event
 	api.AddEndpoint(
@@ -18,12 +28,13 @@ func SetupEventSwagger(api *swag.API) {
 			http.MethodPost, "/events",
 			endpoint.Handler(eventController.CreateEvent),
 			endpoint.Summary("Create a new event"),
-			endpoint.Description("Allows an authorized user to create a new event"),
+			endpoint.Description("Allows an authorized user to create a new event. end_date (defaults to two hours after the start) and time_zone (IANA name) are optional. venue_id links the event to one of the association's venues, whose name becomes the event location. publication_status (draft, scheduled with a future publish_at, or published by default) controls when members see the event and are notified. Other events booked at the same location on an overlapping slot are returned in location_conflicts as a warning"),
 			endpoint.Body(models.Event{}, "Event object to create", true),
 			endpoint.Response(http.StatusCreated, "Successfully created event", endpoint.SchemaResponseOption(models.Event{})),
 			endpoint.Response(http.StatusBadRequest, "Invalid event data"),
 			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
 			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid event data, end date before the start, venue of another association or past publication date"),
 			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
 			endpoint.Tags("Events"),
 		),
@@ -34,11 +45,19 @@ func SetupEventSwagger(api *swag.API) {
 		endpoint.New(
 			http.MethodGet, "/events",
 			endpoint.Handler(eventController.GetEvents),
-			endpoint.Summary("Retrieve all events"),
-			endpoint.Description("Fetches a paginated list of all events"),
-			endpoint.Query("search", "string", "Optional search query", false),
+			endpoint.Summary("Search events"),
+			endpoint.Description("Fetches a paginated list of the events visible to the caller, from the start of the day unless from is given. Without a token, only public events are listed; members-only events are listed to accepted members of the association and invite-only events to their guests. Recurring series are expanded into their occurrences"),
+			endpoint.Query("search", "string", "Text searched in the name and description", false),
+			endpoint.Query("category_id", "string", "ID of the category", false),
+			endpoint.Query("association_id", "string", "ID of a hosting or co-hosting association", false),
+			endpoint.Query("from", "string", "RFC 3339 date; events ending before are excluded (defaults to the start of the day)", false),
+			endpoint.Query("to", "string", "RFC 3339 date; events starting after are excluded", false),
+			endpoint.Query("location", "string", "Text searched in the location, venue name, city and postal code", false),
+			endpoint.Query("has_free_seats", "boolean", "Only events that can still be joined", false),
+			endpoint.Query("attending", "boolean", "Only events the caller is registered to (requires a token)", false),
+			endpoint.Query("sort", "string", "date (default), -date or popularity (number of participants)", false),
 			endpoint.Query("page", "integer", "Page number for pagination", false),
-			endpoint.Query("pageSize", "integer", "Number of items per page", false),
+			endpoint.Query("limit", "integer", "Number of items per page", false),
 			endpoint.Response(
 				http.StatusOK,
 				"List of events",
@@ -49,6 +68,27 @@ func SetupEventSwagger(api *swag.API) {
 					},
 				}),
 			),
+			endpoint.Response(http.StatusBadRequest, "Invalid date or sort"),
+			endpoint.Response(http.StatusUnauthorized, "attending requires a token"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Get Nearby Events
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/nearby",
+			endpoint.Handler(eventController.GetNearbyEvents),
+			endpoint.Summary("Retrieve upcoming events near a location"),
+			endpoint.Description("Fetches the upcoming events visible to the caller whose venue lies within the radius, closest first. Each event carries its distance in kilometers; recurring events are listed once per upcoming occurrence"),
+			endpoint.Query("lat", "number", "Latitude of the search point", true),
+			endpoint.Query("lng", "number", "Longitude of the search point", true),
+			endpoint.Query("radius", "number", "Search radius in kilometers (default 10, max 200)", false),
+			endpoint.Query("page", "integer", "Page number for pagination", false),
+			endpoint.Query("pageSize", "integer", "Number of items per page", false),
+			endpoint.Response(http.StatusOK, "List of nearby events", endpoint.SchemaResponseOption([]services.NearbyEvent{})),
+			endpoint.Response(http.StatusBadRequest, "Invalid coordinates or radius"),
 			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
 			endpoint.Tags("Events"),
 		),
@@ -60,7 +100,7 @@ func SetupEventSwagger(api *swag.API) {
 			http.MethodGet, "/events/{id}",
 			endpoint.Handler(eventController.GetEventById),
 			endpoint.Summary("Retrieve an event by ID"),
-			endpoint.Description("Fetches the details of a specific event using its ID"),
+			endpoint.Description("Fetches the details of a specific event using its ID. Public events can be read without a token; events the caller cannot see are reported as not found"),
 			endpoint.Path("id", "string", "ID of the event to retrieve", true),
 			endpoint.Response(http.StatusOK, "Details of the event", endpoint.SchemaResponseOption(models.Event{})),
 			endpoint.Response(http.StatusNotFound, "Event not found"),
@@ -75,12 +115,16 @@ func SetupEventSwagger(api *swag.API) {
 			http.MethodPut, "/events/{id}",
 			endpoint.Handler(eventController.UpdateEvent),
 			endpoint.Summary("Update an event"),
-			endpoint.Description("Allows an authorized user to update an existing event"),
+			endpoint.Description("Allows an authorized user to update an existing event. Moving the date keeps the duration unless end_date is sent; an empty end_date restores the default duration and an empty venue_id detaches the event from its venue. Other events booked at the same location on an overlapping slot are returned in location_conflicts as a warning"),
 			endpoint.Path("id", "string", "ID of the event to update", true),
+			endpoint.Query("scope", "string", "For recurring events: all (default), occurrence or following", false),
+			endpoint.Query("occurrence_date", "string", "RFC 3339 date of the targeted occurrence, required when scope is occurrence or following", false),
 			endpoint.Body(models.Event{}, "Updated event data", true),
 			endpoint.Response(http.StatusOK, "Successfully updated event", endpoint.SchemaResponseOption(models.Event{})),
 			endpoint.Response(http.StatusBadRequest, "Invalid event data"),
+			endpoint.Response(http.StatusForbidden, "User is not a leader of the event or of the target association"),
 			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid recurrence rule, end date before the start or venue of another association"),
 			endpoint.Response(http.StatusConflict, "Event update conflict"),
 			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
 			endpoint.Tags("Events"),
@@ -95,6 +139,8 @@ func SetupEventSwagger(api *swag.API) {
 			endpoint.Summary("Delete an event"),
 			endpoint.Description("Allows an authorized user to delete an event by ID"),
 			endpoint.Path("id", "string", "ID of the event to delete", true),
+			endpoint.Query("scope", "string", "For recurring events: all (default), occurrence or following", false),
+			endpoint.Query("occurrence_date", "string", "RFC 3339 date of the targeted occurrence, required when scope is occurrence or following", false),
 			endpoint.Response(http.StatusNoContent, "Successfully deleted event"),
 			endpoint.Response(http.StatusBadRequest, "Invalid ID"),
 			endpoint.Response(http.StatusNotFound, "Event not found"),
@@ -104,6 +150,80 @@ func SetupEventSwagger(api *swag.API) {
 		),
 	)
 
+	// Endpoint: Cancel Event
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/cancel",
+			endpoint.Handler(eventController.CancelEvent),
+			endpoint.Summary("Cancel an event"),
+			endpoint.Description("Marks the event as cancelled instead of deleting it and notifies its participants by push and email. Cancelling a recurring series cancels its upcoming occurrences; scope=occurrence cancels a single one"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Query("scope", "string", "For recurring events: all (default) or occurrence", false),
+			endpoint.Query("occurrence_date", "string", "RFC 3339 date of the targeted occurrence, required when scope is occurrence", false),
+			endpoint.Body(map[string]interface{}{"reason": "string"}, "Reason shown to the participants", false),
+			endpoint.Response(http.StatusOK, "Cancelled event", endpoint.SchemaResponseOption(models.Event{})),
+			endpoint.Response(http.StatusBadRequest, "Invalid request"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Response(http.StatusConflict, "Event already cancelled"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Reschedule Event
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/reschedule",
+			endpoint.Handler(eventController.RescheduleEvent),
+			endpoint.Summary("Reschedule or postpone an event"),
+			endpoint.Description("Moves the event to a new date (status scheduled) or, without a date, marks it as postponed, and notifies its participants by push and email. A recurring series can only be postponed; its occurrences are rescheduled one by one with scope=occurrence"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Query("scope", "string", "For recurring events: all (default) or occurrence", false),
+			endpoint.Query("occurrence_date", "string", "RFC 3339 date of the targeted occurrence, required when scope is occurrence", false),
+			endpoint.Body(map[string]interface{}{"date": "2024-01-15T18:00:00Z", "reason": "string"}, "New date (omit to postpone) and reason shown to the participants", true),
+			endpoint.Response(http.StatusOK, "Rescheduled event", endpoint.SchemaResponseOption(models.Event{})),
+			endpoint.Response(http.StatusBadRequest, "Invalid request"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Publish Event
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/publish",
+			endpoint.Handler(eventController.PublishEvent),
+			endpoint.Summary("Publish a draft event or schedule its publication"),
+			endpoint.Description("Without publish_at, publishes the event immediately and notifies the members who can see it. With a future publish_at, the event is published at that time by a background job. A recurring series is published as a whole"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Body(map[string]interface{}{"publish_at": "2024-01-15T08:00:00Z"}, "Optional publication date", false),
+			endpoint.Response(http.StatusOK, "Published or scheduled event", endpoint.SchemaResponseOption(models.Event{})),
+			endpoint.Response(http.StatusBadRequest, "Invalid request"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Response(http.StatusConflict, "Event already published"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Publication date is not in the future"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Revert Event To Draft
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/draft",
+			endpoint.Handler(eventController.RevertToDraft),
+			endpoint.Summary("Revert an unpublished event to draft"),
+			endpoint.Description("Cancels the scheduled publication of an event; drafts are only visible to the leaders of its hosts"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Response(http.StatusOK, "Draft event", endpoint.SchemaResponseOption(models.Event{})),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Response(http.StatusConflict, "Event already published"),
+			endpoint.Tags("Events"),
+		),
+	)
+
 	// Endpoint: Get Event Participations
 	api.AddEndpoint(
 		endpoint.New(
@@ -134,14 +254,705 @@ func SetupEventSwagger(api *swag.API) {
 			http.MethodPost, "/events/{id}/user-event-participation",
 			endpoint.Handler(eventController.ChangeAttend),
 			endpoint.Summary("Change user attendance for an event"),
-			endpoint.Description("Allows a user to mark their attendance for an event"),
+			endpoint.Description("Allows a user to mark their attendance for an event. When the event is full, the participation is put on the waitlist (status waitlisted, with its waitlist_position); unregistering promotes the next person on the waitlist. Events the user already attends on an overlapping slot are returned in schedule_conflicts as a warning"),
 			endpoint.Path("id", "string", "ID of the event", true),
-			endpoint.Body(map[string]bool{"is_attending": true}, "Attendance status", true),
+			endpoint.Body(map[string]interface{}{"is_attending": true, "occurrence_date": "2024-01-15T18:00:00Z", "answers": []map[string]interface{}{{"question_id": "string", "value": "string"}}}, "Attendance status, with the occurrence date for recurring events and the answers to the registration form", true),
 			endpoint.Response(http.StatusOK, "Attendance updated successfully", endpoint.SchemaResponseOption(models.Participation{})),
 			endpoint.Response(http.StatusBadRequest, "Invalid request"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid answers to the registration form"),
+			endpoint.Response(http.StatusConflict, "Event cancelled"),
 			endpoint.Response(http.StatusUnauthorized, "User not authorized"),
 			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
 			endpoint.Tags("Events"),
 		),
 	)
+
+	// Endpoint: Get Check-in QR Code
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/check-in/qr-code",
+			endpoint.Handler(eventController.GetCheckInQRCode),
+			endpoint.Summary("Get the check-in QR code of the current user"),
+			endpoint.Description("Returns a PNG QR code containing a signed check-in token for the user's participation"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Query("occurrence_date", "string", "RFC 3339 date of the occurrence for recurring events", false),
+			endpoint.Response(http.StatusOK, "QR code (image/png)"),
+			endpoint.Response(http.StatusNotFound, "Event not found or user not registered"),
+			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Check In
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/check-in",
+			endpoint.Handler(eventController.CheckIn),
+			endpoint.Summary("Check a participant in"),
+			endpoint.Description("Marks the participant whose QR code was scanned as present and awards the event points. Reserved to the association owner and administrators"),
+			endpoint.Path("id", "string", "ID of the event (or of its recurring series)", true),
+			endpoint.Body(map[string]interface{}{"token": "string"}, "Check-in token read from the QR code", true),
+			endpoint.Response(http.StatusOK, "Participant checked in", endpoint.SchemaResponseOption(models.Participation{})),
+			endpoint.Response(http.StatusBadRequest, "Invalid or expired QR code, or QR code of another event"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Participant not registered"),
+			endpoint.Response(http.StatusConflict, "Participant already checked in", endpoint.SchemaResponseOption(models.Participation{})),
+			endpoint.Response(http.StatusUnprocessableEntity, "Check-in not open yet"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Bulk Attendance
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/attendance",
+			endpoint.Handler(eventController.BulkAttendance),
+			endpoint.Summary("Update attendance in bulk"),
+			endpoint.Description("Applies one action to several participations of the event in a single transaction. action is confirm or decline (pending participations; confirming awards the event points, declining frees seats for the waitlist), present (check-in without QR code, awarding the points if not confirmed yet) or absent (registered participants not checked in). Give either participation_ids (at most 500) or all_pending=true to target every participation still to handle for the action. Each participation gets its own result; those not registered, already handled or already checked in are reported and skipped. Reserved to the association owner and administrators"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Body(map[string]interface{}{"action": "confirm", "participation_ids": []string{"string"}, "all_pending": false}, "Action and targeted participations", true),
+			endpoint.Response(http.StatusOK, "Per-participation report", endpoint.SchemaResponseOption(map[string]interface{}{
+				"action":    "confirm",
+				"processed": 0,
+				"succeeded": 0,
+				"failed":    0,
+				"results":   []map[string]interface{}{{"participation_id": "string", "user_id": "string", "success": true, "error": "string"}},
+			})),
+			endpoint.Response(http.StatusBadRequest, "Invalid action, or neither or both of participation_ids and all_pending"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Response(http.StatusConflict, "Event cancelled (present or absent)"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Check-in not open yet (present or absent)"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Get Event Questions
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/questions",
+			endpoint.Handler(eventFormController.GetEventQuestions),
+			endpoint.Summary("Get the registration form of an event"),
+			endpoint.Description("Returns the questions asked at sign-up, in display order. Occurrences of a recurring event share the form of their series"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Response(http.StatusOK, "Registration questions", endpoint.SchemaResponseOption([]models.EventQuestion{})),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Update Event Questions
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPut, "/events/{id}/questions",
+			endpoint.Handler(eventFormController.UpdateEventQuestions),
+			endpoint.Summary("Replace the registration form of an event"),
+			endpoint.Description("Questions sent with their id are updated, the others are created; questions left out are deleted with their answers. Types: text, number, single_choice, multiple_choice (choice questions need options)"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Body([]models.EventQuestion{}, "Ordered list of questions", true),
+			endpoint.Response(http.StatusOK, "Updated registration questions", endpoint.SchemaResponseOption([]models.EventQuestion{})),
+			endpoint.Response(http.StatusBadRequest, "Invalid form"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid question"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Export Event Answers
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/answers/export",
+			endpoint.Handler(eventFormController.ExportEventAnswers),
+			endpoint.Summary("Export the registration answers"),
+			endpoint.Description("Returns a CSV file with the date, name, email and status of each registration (declined ones excluded) and one column per question. Equivalent to the participants export with the answers columns. For a recurring event, the participations of every occurrence are exported"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Response(http.StatusOK, "CSV file (text/csv)"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Export Event Participants
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/participants/export",
+			endpoint.Handler(participantExportController.ExportEventParticipants),
+			endpoint.Summary("Export the participants of an event"),
+			endpoint.Description("Streams a CSV or XLSX file with one line per registration (declined ones excluded) and one column per registration question. For a recurring event, the participations of every occurrence are exported"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Query("format", "string", "csv (default) or xlsx", false),
+			endpoint.Query("columns", "string", "Comma-separated columns among event, date, name, email, status, checked_in_at and answers (defaults to all but event)", false),
+			endpoint.Response(http.StatusOK, "CSV or XLSX file"),
+			endpoint.Response(http.StatusBadRequest, "Invalid format or column"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Get Event Invitations
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/invitations",
+			endpoint.Handler(eventInvitationController.GetEventInvitations),
+			endpoint.Summary("Get the guest list of an event"),
+			endpoint.Description("Returns the users invited to an invite-only event. Occurrences of a recurring event share the guest list of their series"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Response(http.StatusOK, "Guest list", endpoint.SchemaResponseOption([]models.EventInvitation{})),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Invite Users
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/invitations",
+			endpoint.Handler(eventInvitationController.InviteUsers),
+			endpoint.Summary("Invite users to an event"),
+			endpoint.Description("Adds users to the guest list of the event. Users already invited and unknown IDs are ignored"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Body(map[string]interface{}{"user_ids": []string{"string"}}, "IDs of the users to invite", true),
+			endpoint.Response(http.StatusOK, "Updated guest list", endpoint.SchemaResponseOption([]models.EventInvitation{})),
+			endpoint.Response(http.StatusBadRequest, "Invalid user list"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Revoke Invitation
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodDelete, "/events/{id}/invitations/{userId}",
+			endpoint.Handler(eventInvitationController.RevokeInvitation),
+			endpoint.Summary("Revoke an invitation"),
+			endpoint.Description("Removes a user from the guest list of the event"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("userId", "string", "ID of the invited user", true),
+			endpoint.Response(http.StatusNoContent, "Invitation revoked"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event or invitation not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Submit Event Feedback
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/feedback",
+			endpoint.Handler(eventFeedbackController.SubmitFeedback),
+			endpoint.Summary("Rate an event"),
+			endpoint.Description("Lets a confirmed or present participant rate the event from 1 to 5 with an optional comment, from its end until 14 days later. Sending a new rating replaces the previous one"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Query("occurrence_date", "string", "RFC 3339 date of the occurrence, for recurring events", false),
+			endpoint.Body(map[string]interface{}{"rating": 5, "comment": "string"}, "Rating and optional comment", true),
+			endpoint.Response(http.StatusOK, "Saved feedback", endpoint.SchemaResponseOption(models.EventFeedback{})),
+			endpoint.Response(http.StatusForbidden, "User was not a confirmed or present participant"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Response(http.StatusConflict, "Event cancelled"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid rating or feedback not open"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Get User Event Feedback
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/feedback/me",
+			endpoint.Handler(eventFeedbackController.GetUserFeedback),
+			endpoint.Summary("Retrieve my feedback on an event"),
+			endpoint.Description("Returns the rating the authenticated user left on the event"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Query("occurrence_date", "string", "RFC 3339 date of the occurrence, for recurring events", false),
+			endpoint.Response(http.StatusOK, "User feedback", endpoint.SchemaResponseOption(models.EventFeedback{})),
+			endpoint.Response(http.StatusNotFound, "Event or feedback not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Get Event Feedback
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/feedback",
+			endpoint.Handler(eventFeedbackController.GetEventFeedback),
+			endpoint.Summary("Retrieve the feedback of an event"),
+			endpoint.Description("Returns the rating count, average and distribution of the event along with the participants' comments. For a recurring event, the feedback of all occurrences is aggregated unless occurrence_date is given"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Query("occurrence_date", "string", "RFC 3339 date of a single occurrence", false),
+			endpoint.Response(http.StatusOK, "Event feedback", endpoint.SchemaResponseOption(services.EventFeedbackReport{})),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Get Event Photos
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/photos",
+			endpoint.Handler(eventPhotoController.GetEventPhotos),
+			endpoint.Summary("Retrieve the photo gallery of an event"),
+			endpoint.Description("Lists the photos shared on the event. Hidden photos are only returned to the association leader and to their uploader, with URLs pointing to the photo file endpoint since their files are no longer served under /public"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Response(http.StatusOK, "Event photos", endpoint.SchemaResponseOption([]models.EventPhoto{})),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Get Event Photo File
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/photos/{photoId}/file",
+			endpoint.Handler(eventPhotoController.GetPhotoFile),
+			endpoint.Summary("Download a photo of the gallery"),
+			endpoint.Description("Returns the image file of the photo, or its thumbnail. Hidden photos are only served to the association leader and to their uploader"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("photoId", "string", "ID of the photo", true),
+			endpoint.Query("thumbnail", "boolean", "Return the thumbnail instead of the full-size photo", false),
+			endpoint.Response(http.StatusOK, "Image file"),
+			endpoint.Response(http.StatusNotFound, "Event or photo not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Upload Event Photo
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/photos",
+			endpoint.Handler(eventPhotoController.UploadPhoto),
+			endpoint.Summary("Upload a photo to an event gallery"),
+			endpoint.Description("Adds a JPEG, PNG or GIF photo (10 MB max) to the gallery and generates its thumbnail. Confirmed or present participants can upload once the event has started, up to 30 photos per event and 200 MB in total"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.FormData("photo", "file", "Photo to upload", true),
+			endpoint.Response(http.StatusCreated, "Uploaded photo", endpoint.SchemaResponseOption(models.EventPhoto{})),
+			endpoint.Response(http.StatusBadRequest, "Missing photo"),
+			endpoint.Response(http.StatusForbidden, "User did not attend the event or quota exceeded"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Response(http.StatusConflict, "Event cancelled"),
+			endpoint.Response(http.StatusRequestEntityTooLarge, "Photo too large"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Unsupported image or gallery not open yet"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Hide Event Photo
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPut, "/events/{id}/photos/{photoId}/hidden",
+			endpoint.Handler(eventPhotoController.SetPhotoHidden),
+			endpoint.Summary("Hide or restore a photo"),
+			endpoint.Description("Lets the association leader hide a photo from the gallery, or restore it. A hidden photo stops being the cover photo"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("photoId", "string", "ID of the photo", true),
+			endpoint.Body(map[string]bool{"hidden": true}, "New visibility of the photo", true),
+			endpoint.Response(http.StatusOK, "Updated photo", endpoint.SchemaResponseOption(models.EventPhoto{})),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event or photo not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Delete Event Photo
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodDelete, "/events/{id}/photos/{photoId}",
+			endpoint.Handler(eventPhotoController.DeletePhoto),
+			endpoint.Summary("Delete a photo"),
+			endpoint.Description("Deletes a photo and its files. The association leader can delete any photo, members only their own"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("photoId", "string", "ID of the photo", true),
+			endpoint.Response(http.StatusNoContent, "Photo deleted"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event or photo not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Set Event Cover Photo
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPut, "/events/{id}/cover-photo",
+			endpoint.Handler(eventPhotoController.SetCoverPhoto),
+			endpoint.Summary("Choose the cover photo of an event"),
+			endpoint.Description("Sets a visible photo of the gallery as the event cover; an empty photo_id removes the cover"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Body(map[string]string{"photo_id": "string"}, "Photo to use as cover", true),
+			endpoint.Response(http.StatusOK, "Updated event", endpoint.SchemaResponseOption(models.Event{})),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event or photo not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Get Event Co-hosts
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/co-hosts",
+			endpoint.Handler(eventCoHostController.GetCoHosts),
+			endpoint.Summary("Retrieve the co-hosts of an event"),
+			endpoint.Description("Lists the associations invited to co-host the event with their answer (pending, accepted or rejected)"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Response(http.StatusOK, "Event co-hosts", endpoint.SchemaResponseOption([]models.EventCoHost{})),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Invite Event Co-host
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/co-hosts",
+			endpoint.Handler(eventCoHostController.InviteCoHost),
+			endpoint.Summary("Invite an association to co-host an event"),
+			endpoint.Description("Lets the leader of the primary host invite another association. Once accepted, the event appears in the co-host's listings and its leader can manage participations"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Body(map[string]string{"association_id": "string"}, "Association to invite", true),
+			endpoint.Response(http.StatusOK, "Co-host invitation", endpoint.SchemaResponseOption(models.EventCoHost{})),
+			endpoint.Response(http.StatusBadRequest, "Missing association"),
+			endpoint.Response(http.StatusForbidden, "User is not the leader of the primary host"),
+			endpoint.Response(http.StatusNotFound, "Event or association not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Association cannot co-host this event"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Remove Event Co-host
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodDelete, "/events/{id}/co-hosts/{associationId}",
+			endpoint.Handler(eventCoHostController.RemoveCoHost),
+			endpoint.Summary("Remove a co-host from an event"),
+			endpoint.Description("The leader of the primary host can remove any co-host; the leader of a co-host can withdraw their association"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("associationId", "string", "ID of the co-host association", true),
+			endpoint.Response(http.StatusNoContent, "Co-host removed"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event or co-host not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Accept Co-host Invitation
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/co-hosts/{associationId}/accept",
+			endpoint.Handler(eventCoHostController.AcceptInvitation),
+			endpoint.Summary("Accept a co-host invitation"),
+			endpoint.Description("Lets the leader of the invited association accept to co-host the event"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("associationId", "string", "ID of the invited association", true),
+			endpoint.Response(http.StatusOK, "Updated invitation", endpoint.SchemaResponseOption(models.EventCoHost{})),
+			endpoint.Response(http.StatusForbidden, "User is not the leader of the invited association"),
+			endpoint.Response(http.StatusNotFound, "Event or invitation not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Decline Co-host Invitation
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/co-hosts/{associationId}/decline",
+			endpoint.Handler(eventCoHostController.DeclineInvitation),
+			endpoint.Summary("Decline a co-host invitation"),
+			endpoint.Description("Lets the leader of the invited association decline to co-host the event, or step back after accepting"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("associationId", "string", "ID of the invited association", true),
+			endpoint.Response(http.StatusOK, "Updated invitation", endpoint.SchemaResponseOption(models.EventCoHost{})),
+			endpoint.Response(http.StatusForbidden, "User is not the leader of the invited association"),
+			endpoint.Response(http.StatusNotFound, "Event or invitation not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Get Event Comments
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/comments",
+			endpoint.Handler(eventCommentController.GetComments),
+			endpoint.Summary("Retrieve the Q&A thread of an event"),
+			endpoint.Description("Fetches a paginated list of the top-level comments of the event, oldest first, each with its replies. Available to anyone who can view the event; comments on an occurrence belong to its series"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Query("page", "integer", "Page number for pagination", false),
+			endpoint.Query("limit", "integer", "Number of items per page", false),
+			endpoint.Response(http.StatusOK, "Event comments", endpoint.SchemaResponseOption([]models.EventComment{})),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Add Event Comment
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/comments",
+			endpoint.Handler(eventCommentController.AddComment),
+			endpoint.Summary("Ask a question or reply on an event"),
+			endpoint.Description("Posts a comment on the event, or a reply when parent_id is given. Replies can only answer a top-level comment. The event organisers are notified of new questions and authors of the replies they receive"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Body(map[string]string{"content": "string", "parent_id": "string"}, "Comment to post", true),
+			endpoint.Response(http.StatusCreated, "Posted comment", endpoint.SchemaResponseOption(models.EventComment{})),
+			endpoint.Response(http.StatusBadRequest, "Invalid body"),
+			endpoint.Response(http.StatusNotFound, "Event or parent comment not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Empty or too long content, or reply to a reply"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Mark Event Comment As Answered
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPut, "/events/{id}/comments/{commentId}/answered",
+			endpoint.Handler(eventCommentController.SetAnswered),
+			endpoint.Summary("Mark a question as answered"),
+			endpoint.Description("Lets the leaders of the hosting associations mark a top-level comment as answered, or reopen it"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("commentId", "string", "ID of the comment", true),
+			endpoint.Body(map[string]bool{"answered": true}, "New state of the question", true),
+			endpoint.Response(http.StatusOK, "Updated comment", endpoint.SchemaResponseOption(models.EventComment{})),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event or comment not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Comment is a reply"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Delete Event Comment
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodDelete, "/events/{id}/comments/{commentId}",
+			endpoint.Handler(eventCommentController.DeleteComment),
+			endpoint.Summary("Delete a comment"),
+			endpoint.Description("Deletes a comment and its replies. The leaders of the hosting associations can delete any comment, members only their own"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("commentId", "string", "ID of the comment", true),
+			endpoint.Response(http.StatusNoContent, "Comment deleted"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event or comment not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Get Event Shifts
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/shifts",
+			endpoint.Handler(eventShiftController.GetShifts),
+			endpoint.Summary("Retrieve the volunteer shifts of an event"),
+			endpoint.Description("Lists the volunteer shifts of the event in chronological order with their number of volunteers. Available to anyone who can view the event"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Response(http.StatusOK, "Event shifts", endpoint.SchemaResponseOption([]models.EventShift{})),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Get Event Shift Roster
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/{id}/shifts/roster",
+			endpoint.Handler(eventShiftController.GetRoster),
+			endpoint.Summary("Retrieve the volunteer roster of an event"),
+			endpoint.Description("Lists the shifts of the event with their volunteers and whether each shift was completed"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Response(http.StatusOK, "Event shifts with their signups", endpoint.SchemaResponseOption([]models.EventShift{})),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Create Event Shift
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/shifts",
+			endpoint.Handler(eventShiftController.CreateShift),
+			endpoint.Summary("Create a volunteer shift"),
+			endpoint.Description("Adds a shift to the event. When required_role is set, only users with at least this role (user < association_leader < admin) can sign up"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Body(map[string]interface{}{"name": "Bar", "description": "string", "starts_at": "2024-01-15T18:00:00Z", "ends_at": "2024-01-15T20:00:00Z", "capacity": 4, "required_role": "user"}, "Shift to create", true),
+			endpoint.Response(http.StatusCreated, "Created shift", endpoint.SchemaResponseOption(models.EventShift{})),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid shift"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Update Event Shift
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPut, "/events/{id}/shifts/{shiftId}",
+			endpoint.Handler(eventShiftController.UpdateShift),
+			endpoint.Summary("Update a volunteer shift"),
+			endpoint.Description("Updates a shift. The capacity cannot go below the number of volunteers, and a moved shift cannot overlap another shift of one of its volunteers"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("shiftId", "string", "ID of the shift", true),
+			endpoint.Body(map[string]interface{}{"name": "Bar", "description": "string", "starts_at": "2024-01-15T18:00:00Z", "ends_at": "2024-01-15T20:00:00Z", "capacity": 4, "required_role": "user"}, "New values of the shift", true),
+			endpoint.Response(http.StatusOK, "Updated shift", endpoint.SchemaResponseOption(models.EventShift{})),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event or shift not found"),
+			endpoint.Response(http.StatusConflict, "Capacity below the number of volunteers or overlapping shift"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid shift"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Delete Event Shift
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodDelete, "/events/{id}/shifts/{shiftId}",
+			endpoint.Handler(eventShiftController.DeleteShift),
+			endpoint.Summary("Delete a volunteer shift"),
+			endpoint.Description("Deletes a shift and the signups of its volunteers"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("shiftId", "string", "ID of the shift", true),
+			endpoint.Response(http.StatusNoContent, "Shift deleted"),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event or shift not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Sign Up For Event Shift
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/shifts/{shiftId}/signup",
+			endpoint.Handler(eventShiftController.SignUp),
+			endpoint.Summary("Volunteer for a shift"),
+			endpoint.Description("Signs the caller up for the shift. Fails when the shift is full, requires another role, or overlaps another shift the caller volunteers for, whatever its event"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("shiftId", "string", "ID of the shift", true),
+			endpoint.Response(http.StatusCreated, "Shift signup", endpoint.SchemaResponseOption(models.ShiftSignup{})),
+			endpoint.Response(http.StatusForbidden, "Shift requires another role"),
+			endpoint.Response(http.StatusNotFound, "Event or shift not found"),
+			endpoint.Response(http.StatusConflict, "Shift full, overlapping shift, already signed up or event cancelled"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Withdraw From Event Shift
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodDelete, "/events/{id}/shifts/{shiftId}/signup",
+			endpoint.Handler(eventShiftController.Withdraw),
+			endpoint.Summary("Withdraw from a shift"),
+			endpoint.Description("Cancels the caller's signup to the shift, unless the shift was already completed"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("shiftId", "string", "ID of the shift", true),
+			endpoint.Response(http.StatusNoContent, "Signup cancelled"),
+			endpoint.Response(http.StatusNotFound, "Event, shift or signup not found"),
+			endpoint.Response(http.StatusConflict, "Shift already completed"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Complete Event Shift
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/shifts/{shiftId}/signups/{userId}/complete",
+			endpoint.Handler(eventShiftController.CompleteSignup),
+			endpoint.Summary("Mark a volunteer's shift as completed"),
+			endpoint.Description("Records that the volunteer completed the shift and awards them the points of the event category, as a confirmed participation does"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Path("shiftId", "string", "ID of the shift", true),
+			endpoint.Path("userId", "string", "ID of the volunteer", true),
+			endpoint.Response(http.StatusOK, "Completed signup", endpoint.SchemaResponseOption(models.ShiftSignup{})),
+			endpoint.Response(http.StatusForbidden, "User not authorized"),
+			endpoint.Response(http.StatusNotFound, "Event, shift or signup not found"),
+			endpoint.Response(http.StatusConflict, "Shift already completed"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Get Event Templates
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/events/templates",
+			endpoint.Handler(eventTemplateController.GetTemplates),
+			endpoint.Summary("Retrieve the event templates of an association"),
+			endpoint.Description("Lists the templates saved by the association, sorted by name"),
+			endpoint.Query("association_id", "string", "ID of the association", true),
+			endpoint.Response(http.StatusOK, "Event templates", endpoint.SchemaResponseOption([]models.EventTemplate{})),
+			endpoint.Response(http.StatusBadRequest, "Missing association"),
+			endpoint.Response(http.StatusForbidden, "User is not the leader of the association"),
+			endpoint.Response(http.StatusNotFound, "Association not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Save Event As Template
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/template",
+			endpoint.Handler(eventTemplateController.SaveAsTemplate),
+			endpoint.Summary("Save an event as a template"),
+			endpoint.Description("Saves the description, category, location, capacity, visibility, duration, registration questions and volunteer shifts of the event as a template of its hosting association"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Body(map[string]string{"name": "string"}, "Name of the template", true),
+			endpoint.Response(http.StatusCreated, "Created template", endpoint.SchemaResponseOption(models.EventTemplate{})),
+			endpoint.Response(http.StatusForbidden, "User is not the leader of the hosting association"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid template name"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Create Event From Template
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/templates/{templateId}/events",
+			endpoint.Handler(eventTemplateController.CreateEventFromTemplate),
+			endpoint.Summary("Create an event from a template"),
+			endpoint.Description("Creates a draft event starting at the given date with the template's details, registration questions and shifts. Shifts keep their position relative to the start of the event"),
+			endpoint.Path("templateId", "string", "ID of the template", true),
+			endpoint.Body(map[string]string{"date": "2024-01-15T18:00:00Z", "name": "string"}, "Start date of the event and optional name replacing the template's", true),
+			endpoint.Response(http.StatusCreated, "Created draft event", endpoint.SchemaResponseOption(models.Event{})),
+			endpoint.Response(http.StatusBadRequest, "Missing date"),
+			endpoint.Response(http.StatusForbidden, "User is not the leader of the association"),
+			endpoint.Response(http.StatusNotFound, "Template not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid event"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Delete Event Template
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodDelete, "/events/templates/{templateId}",
+			endpoint.Handler(eventTemplateController.DeleteTemplate),
+			endpoint.Summary("Delete an event template"),
+			endpoint.Description("Deletes the template; events created from it are left unchanged"),
+			endpoint.Path("templateId", "string", "ID of the template", true),
+			endpoint.Response(http.StatusNoContent, "Template deleted"),
+			endpoint.Response(http.StatusForbidden, "User is not the leader of the association"),
+			endpoint.Response(http.StatusNotFound, "Template not found"),
+			endpoint.Tags("Events"),
+		),
+	)
+
+	// Endpoint: Duplicate Event
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/events/{id}/duplicate",
+			endpoint.Handler(eventTemplateController.DuplicateEvent),
+			endpoint.Summary("Duplicate an event to a new date"),
+			endpoint.Description("Creates a draft copy of the event starting at the given date, with its registration questions and shifts. Participants, invitations and co-hosts are not copied"),
+			endpoint.Path("id", "string", "ID of the event", true),
+			endpoint.Body(map[string]string{"date": "2024-01-15T18:00:00Z", "name": "string"}, "Start date of the copy and optional new name", true),
+			endpoint.Response(http.StatusCreated, "Created draft event", endpoint.SchemaResponseOption(models.Event{})),
+			endpoint.Response(http.StatusBadRequest, "Missing date"),
+			endpoint.Response(http.StatusForbidden, "User is not the leader of the hosting association"),
+			endpoint.Response(http.StatusNotFound, "Event not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid event"),
+			endpoint.Tags("Events"),
+		),
+	)
 }
@@ -2,6 +2,8 @@ package swagger
 
 import (
 	"backend/controllers"
+	"backend/models"
+	"backend/services"
 	"net/http"
 
 	"github.com/zc2638/swag"
@@ -10,6 +12,10 @@ import (
 
 func SetupHomeSwagger(api *swag.API) {
 	homeController := controllers.NewHomeController()
+	recommendationController := controllers.NewRecommendationController()
+	pointsController := controllers.NewPointsController()
+	leaderboardController := controllers.NewLeaderboardController()
+	userController := controllers.NewUserController()
 
 	// Endpoint: Get Statistics
 	api.AddEndpoint(
@@ -36,15 +42,42 @@ func SetupHomeSwagger(api *swag.API) {
 			http.MethodGet, "/top-associations",
 			endpoint.Handler(homeController.GetTopAssociations),
 			endpoint.Summary("Retrieve top associations"),
-			endpoint.Description("Fetches the top associations based on activity"),
-			endpoint.Response(http.StatusOK, "List of top associations", endpoint.SchemaResponseOption([]map[string]interface{}{
-				{
-					"id":          "string",
-					"name":        "string",
-					"description": "string",
-					"score":       "integer",
-				},
-			})),
+			endpoint.Description("Fetches the three associations with the most members, weighted by the quality score derived from the ratings left on their events. Associations with few ratings are scored close to a neutral 3/5"),
+			endpoint.Response(http.StatusOK, "List of top associations", endpoint.SchemaResponseOption([]services.TopAssociation{})),
+			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Home"),
+		),
+	)
+
+	// Endpoint: Get Recommendations
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/me/recommendations",
+			endpoint.Handler(recommendationController.GetRecommendations),
+			endpoint.Summary("Retrieve personalised event recommendations"),
+			endpoint.Description("Ranks the upcoming events of the next 60 days the user can see and has not joined. The score combines the user's past participations in the event category, their activity in the hosting association, the members of their associations already attending and how soon the event takes place. Each recommendation comes with the reasons to display"),
+			endpoint.Query("page", "integer", "Page number for pagination", false),
+			endpoint.Query("limit", "integer", "Number of items per page", false),
+			endpoint.Response(http.StatusOK, "Recommended events", endpoint.SchemaResponseOption([]services.Recommendation{})),
+			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
+			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Home"),
+		),
+	)
+
+	// Endpoint: Get Points History
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/me/points/history",
+			endpoint.Handler(pointsController.GetMyHistory),
+			endpoint.Summary("Retrieve my points history"),
+			endpoint.Description("Lists the entries of the user's points ledger, newest first. Each entry gives its source (participation, shift, manual adjustment or reward redemption), the participation or shift signup it refers to, the amount and the leader or admin who recorded it. A reversal is a negative entry referencing the reversed one. The points_open balance of the user is the sum of these entries"),
+			endpoint.Query("page", "integer", "Page number for pagination", false),
+			endpoint.Query("limit", "integer", "Number of items per page", false),
+			endpoint.Response(http.StatusOK, "Paginated points entries", endpoint.SchemaResponseOption([]models.PointsEntry{})),
 			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
 			endpoint.Response(http.StatusInternalServerError, "Internal server error"),
 			endpoint.Security("bearer_auth"),
@@ -52,4 +85,38 @@ func SetupHomeSwagger(api *swag.API) {
 		),
 	)
 
+	// Endpoint: Update Privacy Settings
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPut, "/me/privacy",
+			endpoint.Handler(userController.UpdatePrivacySettings),
+			endpoint.Summary("Update my privacy settings"),
+			endpoint.Description("Sets whether the user appears in the public leaderboards. The change applies immediately, and points keep being counted so the user can opt back in at any time"),
+			endpoint.Body(services.PrivacySettings{}, "Privacy settings", true),
+			endpoint.Response(http.StatusOK, "Saved settings", endpoint.SchemaResponseOption(services.PrivacySettings{})),
+			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Home"),
+		),
+	)
+
+	// Endpoint: Get Leaderboard
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/leaderboards",
+			endpoint.Handler(leaderboardController.GetLeaderboard),
+			endpoint.Summary("Retrieve a leaderboard"),
+			endpoint.Description("Ranks users by the points earned during the current week (from Monday), month, academic semester (September to January, February to August) or all time. The leaderboard is global, or restricted to the events of an association or of a category. Users who opted out in their privacy settings are left out; tied users share the same rank"),
+			endpoint.Query("period", "string", "week, month, semester or all (default)", false),
+			endpoint.Query("association_id", "string", "Restrict to the points earned at the association's events", false),
+			endpoint.Query("category_id", "string", "Restrict to the points earned at events of the category", false),
+			endpoint.Query("page", "integer", "Page number for pagination", false),
+			endpoint.Query("limit", "integer", "Number of items per page", false),
+			endpoint.Response(http.StatusOK, "Paginated leaderboard", endpoint.SchemaResponseOption([]services.LeaderboardEntry{})),
+			endpoint.Response(http.StatusBadRequest, "Invalid period, or both association and category given"),
+			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Home"),
+		),
+	)
 }
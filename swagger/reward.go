@@ -0,0 +1,184 @@
+package swagger
+
+import (
+	"backend/controllers"
+	"backend/models"
+	"backend/services"
+	"net/http"
+
+	"github.com/zc2638/swag"
+	"github.com/zc2638/swag/endpoint"
+)
+
+func SetupRewardSwagger(api *swag.API) {
+	rewardController := controllers.NewRewardController()
+
+	// Endpoint: Get Rewards
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/rewards",
+			endpoint.Handler(rewardController.GetRewards),
+			endpoint.Summary("Retrieve the rewards catalogue"),
+			endpoint.Description("Lists the active rewards, cheapest first. Rewards without association_id are published by the admins. include_inactive=true also lists withdrawn rewards and is reserved to the leader of the association, or to admins"),
+			endpoint.Query("association_id", "string", "Only list the rewards of this association", false),
+			endpoint.Query("include_inactive", "boolean", "Also list withdrawn rewards", false),
+			endpoint.Query("page", "integer", "Page number for pagination", false),
+			endpoint.Query("limit", "integer", "Number of items per page", false),
+			endpoint.Response(http.StatusOK, "Paginated rewards", endpoint.SchemaResponseOption([]models.Reward{})),
+			endpoint.Response(http.StatusForbidden, "User cannot manage these rewards"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Rewards"),
+		),
+	)
+
+	// Endpoint: Create Reward
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/rewards",
+			endpoint.Handler(rewardController.CreateReward),
+			endpoint.Summary("Create a reward"),
+			endpoint.Description("Adds a reward to the catalogue of association_id, or to the global catalogue when it is omitted. A missing stock means the reward is unlimited. Association leader of the association or admin; global rewards are admin only"),
+			endpoint.Body(models.Reward{}, "Reward", true),
+			endpoint.Response(http.StatusCreated, "Created reward", endpoint.SchemaResponseOption(models.Reward{})),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid reward"),
+			endpoint.Response(http.StatusForbidden, "User cannot manage these rewards"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Rewards"),
+		),
+	)
+
+	// Endpoint: Update Reward
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPut, "/rewards/{id}",
+			endpoint.Handler(rewardController.UpdateReward),
+			endpoint.Summary("Update a reward"),
+			endpoint.Description("Updates the name, description, image, cost, remaining stock and activation of the reward. Omitted fields are left unchanged; set unlimited_stock to true to remove the stock limit. Pending redemptions keep the cost paid"),
+			endpoint.Path("id", "string", "ID of the reward", true),
+			endpoint.Body(services.RewardChanges{}, "Fields to update", true),
+			endpoint.Response(http.StatusOK, "Updated reward", endpoint.SchemaResponseOption(models.Reward{})),
+			endpoint.Response(http.StatusNotFound, "Reward not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Invalid reward"),
+			endpoint.Response(http.StatusForbidden, "User cannot manage this reward"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Rewards"),
+		),
+	)
+
+	// Endpoint: Delete Reward
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodDelete, "/rewards/{id}",
+			endpoint.Handler(rewardController.DeleteReward),
+			endpoint.Summary("Delete a reward"),
+			endpoint.Description("Deletes a reward that was never redeemed. A reward with redemptions is only withdrawn from the catalogue so their history is kept"),
+			endpoint.Path("id", "string", "ID of the reward", true),
+			endpoint.Response(http.StatusNoContent, "Reward deleted or withdrawn"),
+			endpoint.Response(http.StatusNotFound, "Reward not found"),
+			endpoint.Response(http.StatusForbidden, "User cannot manage this reward"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Rewards"),
+		),
+	)
+
+	// Endpoint: Redeem Reward
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/rewards/{id}/redeem",
+			endpoint.Handler(rewardController.Redeem),
+			endpoint.Summary("Redeem a reward"),
+			endpoint.Description("Debits the cost of the reward from the user's points and takes one item from the stock in a single transaction. The returned redemption is pending and carries the pickup code to show to a leader. Spent points are not removed from the leaderboards"),
+			endpoint.Path("id", "string", "ID of the reward", true),
+			endpoint.Response(http.StatusCreated, "Pending redemption with its pickup code", endpoint.SchemaResponseOption(models.RewardRedemption{})),
+			endpoint.Response(http.StatusNotFound, "Reward not found"),
+			endpoint.Response(http.StatusConflict, "Reward withdrawn or out of stock, or not enough points"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Rewards"),
+		),
+	)
+
+	// Endpoint: Get Reward Redemptions
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/rewards/{id}/redemptions",
+			endpoint.Handler(rewardController.GetRewardRedemptions),
+			endpoint.Summary("Retrieve the redemptions of a reward"),
+			endpoint.Description("Lists the redemptions of the reward, oldest first, with the requesting users. Pickup codes are hidden: the user shows theirs at pickup"),
+			endpoint.Path("id", "string", "ID of the reward", true),
+			endpoint.Query("status", "string", "pending, fulfilled, rejected or cancelled", false),
+			endpoint.Query("page", "integer", "Page number for pagination", false),
+			endpoint.Query("limit", "integer", "Number of items per page", false),
+			endpoint.Response(http.StatusOK, "Paginated redemptions", endpoint.SchemaResponseOption([]models.RewardRedemption{})),
+			endpoint.Response(http.StatusNotFound, "Reward not found"),
+			endpoint.Response(http.StatusForbidden, "User cannot manage this reward"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Rewards"),
+		),
+	)
+
+	// Endpoint: Fulfil Redemption
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/rewards/redemptions/fulfil",
+			endpoint.Handler(rewardController.FulfilRedemption),
+			endpoint.Summary("Fulfil a redemption"),
+			endpoint.Description("Marks as handed over the pending redemption matching the pickup code shown by the user. Body: {\"code\": \"...\"}. Reserved to the leader of the reward's association, or to admins"),
+			endpoint.Response(http.StatusOK, "Fulfilled redemption", endpoint.SchemaResponseOption(models.RewardRedemption{})),
+			endpoint.Response(http.StatusBadRequest, "Missing pickup code"),
+			endpoint.Response(http.StatusNotFound, "Invalid pickup code"),
+			endpoint.Response(http.StatusConflict, "Redemption already handled"),
+			endpoint.Response(http.StatusForbidden, "User cannot manage this reward"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Rewards"),
+		),
+	)
+
+	// Endpoint: Reject Redemption
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/rewards/redemptions/{redemptionId}/reject",
+			endpoint.Handler(rewardController.RejectRedemption),
+			endpoint.Summary("Reject a redemption"),
+			endpoint.Description("Rejects a pending redemption. Body: {\"reason\": \"...\"}. The points are refunded through a reversal entry, the item goes back to the stock and the user is notified"),
+			endpoint.Path("redemptionId", "string", "ID of the redemption", true),
+			endpoint.Response(http.StatusOK, "Rejected redemption", endpoint.SchemaResponseOption(models.RewardRedemption{})),
+			endpoint.Response(http.StatusNotFound, "Redemption not found"),
+			endpoint.Response(http.StatusConflict, "Redemption already handled"),
+			endpoint.Response(http.StatusForbidden, "User cannot manage this reward"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Rewards"),
+		),
+	)
+
+	// Endpoint: Cancel Redemption
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/rewards/redemptions/{redemptionId}/cancel",
+			endpoint.Handler(rewardController.CancelRedemption),
+			endpoint.Summary("Cancel my redemption"),
+			endpoint.Description("Cancels a pending redemption of the authenticated user, refunds the points and puts the item back in the stock"),
+			endpoint.Path("redemptionId", "string", "ID of the redemption", true),
+			endpoint.Response(http.StatusOK, "Cancelled redemption", endpoint.SchemaResponseOption(models.RewardRedemption{})),
+			endpoint.Response(http.StatusNotFound, "Redemption not found"),
+			endpoint.Response(http.StatusConflict, "Redemption already handled"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Rewards"),
+		),
+	)
+
+	// Endpoint: Get My Redemptions
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/me/redemptions",
+			endpoint.Handler(rewardController.GetMyRedemptions),
+			endpoint.Summary("Retrieve my redemptions"),
+			endpoint.Description("Lists the redemptions of the authenticated user, newest first, with their rewards and pickup codes"),
+			endpoint.Query("page", "integer", "Page number for pagination", false),
+			endpoint.Query("limit", "integer", "Number of items per page", false),
+			endpoint.Response(http.StatusOK, "Paginated redemptions", endpoint.SchemaResponseOption([]models.RewardRedemption{})),
+			endpoint.Response(http.StatusUnauthorized, "User not authenticated"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Rewards"),
+		),
+	)
+}
@@ -25,6 +25,9 @@ func SetupSwagger() *swag.API {
 	SetupAuthSwagger(api)
 	SetupChatbotSwagger(api)
 	SetupHomeSwagger(api)
+	SetupCalendarSwagger(api)
+	SetupBadgeSwagger(api)
+	SetupRewardSwagger(api)
 	// Ajouter d'autres endpoints ici pour d'autres modèles
 
 	return api
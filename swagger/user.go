@@ -11,6 +11,7 @@ import (
 
 func SetupUserSwagger(api *swag.API) {
 	userController := controllers.NewUserController()
+	pointsController := controllers.NewPointsController()
 
 	// Endpoint: Create User
 	api.AddEndpoint(
@@ -156,4 +157,55 @@ func SetupUserSwagger(api *swag.API) {
 			endpoint.Tags("Users"),
 		),
 	)
+
+	// Endpoint: Get User Points History
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodGet, "/users/{id}/points/history",
+			endpoint.Handler(pointsController.GetUserHistory),
+			endpoint.Summary("Retrieve the points history of a user"),
+			endpoint.Description("Lists the entries of the user's points ledger, newest first. Admin only"),
+			endpoint.Path("id", "string", "ID of the user", true),
+			endpoint.Query("page", "integer", "Page number for pagination", false),
+			endpoint.Query("limit", "integer", "Number of items per page", false),
+			endpoint.Response(http.StatusOK, "Paginated points entries", endpoint.SchemaResponseOption([]models.PointsEntry{})),
+			endpoint.Response(http.StatusForbidden, "User is not an admin"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Users"),
+		),
+	)
+
+	// Endpoint: Adjust User Points
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/users/{id}/points",
+			endpoint.Handler(pointsController.AdjustPoints),
+			endpoint.Summary("Adjust the points of a user"),
+			endpoint.Description("Records a manual ledger entry adding (positive amount) or removing (negative amount) points, and updates the user's balance in the same transaction. Admin only"),
+			endpoint.Path("id", "string", "ID of the user", true),
+			endpoint.Body(map[string]interface{}{"amount": 10, "reason": "string"}, "Non-zero amount and reason of the adjustment", true),
+			endpoint.Response(http.StatusCreated, "Recorded entry", endpoint.SchemaResponseOption(models.PointsEntry{})),
+			endpoint.Response(http.StatusNotFound, "User not found"),
+			endpoint.Response(http.StatusUnprocessableEntity, "Zero amount or missing reason"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Users"),
+		),
+	)
+
+	// Endpoint: Reverse Points Entry
+	api.AddEndpoint(
+		endpoint.New(
+			http.MethodPost, "/users/points/{entryId}/reverse",
+			endpoint.Handler(pointsController.ReverseEntry),
+			endpoint.Summary("Reverse a points entry"),
+			endpoint.Description("Records the opposite entry and updates the user's balance. An entry can only be reversed once and a reversal cannot itself be reversed. Admin only"),
+			endpoint.Path("entryId", "string", "ID of the ledger entry", true),
+			endpoint.Body(map[string]string{"reason": "string"}, "Optional reason of the reversal", false),
+			endpoint.Response(http.StatusCreated, "Reversal entry", endpoint.SchemaResponseOption(models.PointsEntry{})),
+			endpoint.Response(http.StatusNotFound, "Entry not found"),
+			endpoint.Response(http.StatusConflict, "Entry already reversed or is a reversal"),
+			endpoint.Security("bearer_auth"),
+			endpoint.Tags("Users"),
+		),
+	)
 }
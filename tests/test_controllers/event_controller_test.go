@@ -3,6 +3,7 @@ package controllers_test
 import (
 	"backend/controllers"
 	"backend/database"
+	"backend/enums"
 	"backend/models"
 	"backend/tests/test_utils"
 	"fmt"
@@ -50,3 +51,42 @@ func TestCreateEvent_Integration(t *testing.T) {
 		assert.Equal(t, http.StatusCreated, rec.Code)
 	})
 }
+
+func TestUpdateEvent_Integration(t *testing.T) {
+	if err := test_utils.SetupTestDB(); err != nil {
+		t.Fatalf("Échec configuration BD test: %v", err)
+	}
+
+	e := echo.New()
+	controller := controllers.NewEventController()
+
+	t.Run("ForbiddenForNonLeader", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := test_utils.GetValidEvent(association.ID)
+		event.Visibility = enums.InviteOnlyVisibility
+		if err := database.CurrentDatabase.Create(&event).Error; err != nil {
+			t.Fatalf("Failed to create event: %v", err)
+		}
+
+		// Responsable d'une autre association
+		otherLeader, _ := test_utils.CreateUserAndAssociation()
+
+		req := httptest.NewRequest(http.MethodPut, "/events/"+event.ID, strings.NewReader(`{"visibility": "public"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(event.ID)
+		c.Set("user", *otherLeader)
+
+		err := controller.UpdateEvent(c)
+		var httpErr *echo.HTTPError
+		if assert.ErrorAs(t, err, &httpErr) {
+			assert.Equal(t, http.StatusForbidden, httpErr.Code)
+		}
+
+		var stored models.Event
+		assert.NoError(t, database.CurrentDatabase.First(&stored, "id = ?", event.ID).Error)
+		assert.Equal(t, enums.InviteOnlyVisibility, stored.Visibility)
+	})
+}
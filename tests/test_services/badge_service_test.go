@@ -0,0 +1,128 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBadgeService(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	badgeService := services.NewBadgeService()
+	checkInService := services.NewCheckInService()
+
+	// createConfirmableParticipation inscrit l'utilisateur à un nouvel événement de la catégorie
+	createConfirmableParticipation := func(t *testing.T, userID string, categoryID string) *models.Participation {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := test_utils.GetValidEvent(association.ID)
+		event.CategoryID = categoryID
+		assert.NoError(t, database.CurrentDatabase.Create(&event).Error)
+
+		participation := test_utils.GetValidParticipation(userID, event.ID)
+		assert.NoError(t, database.CurrentDatabase.Create(&participation).Error)
+		assert.NoError(t, database.CurrentDatabase.Preload("Event.Category").First(&participation, "id = ?", participation.ID).Error)
+		return &participation
+	}
+
+	userBadgeIDs := func(t *testing.T, userID string) []string {
+		badges, err := badgeService.GetUserBadges(userID)
+		assert.NoError(t, err)
+		ids := make([]string, len(badges))
+		for i, badge := range badges {
+			ids[i] = badge.BadgeID
+		}
+		return ids
+	}
+
+	t.Run("CreateBadge_Validation", func(t *testing.T) {
+		assert.Error(t, badgeService.CreateBadge(&models.Badge{Name: "Sans règle", Threshold: 1}))
+
+		unknown := "inconnue"
+		err := badgeService.CreateBadge(&models.Badge{Name: "Catégorie inconnue", Criterion: enums.AttendedEventsCriterion, Threshold: 1, CategoryID: &unknown})
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidBadgeCategory)
+
+		sport := models.Category{Name: "Sport"}
+		assert.NoError(t, database.CurrentDatabase.Create(&sport).Error)
+		// La catégorie ne s'applique qu'aux événements suivis
+		assert.Error(t, badgeService.CreateBadge(&models.Badge{Name: "Adhérent sportif", Criterion: enums.JoinedAssociationsCriterion, Threshold: 1, CategoryID: &sport.ID}))
+	})
+
+	t.Run("AttendedEvents_InCategory", func(t *testing.T) {
+		sport := models.Category{Name: "Sport"}
+		culture := models.Category{Name: "Culture"}
+		assert.NoError(t, database.CurrentDatabase.Create(&sport).Error)
+		assert.NoError(t, database.CurrentDatabase.Create(&culture).Error)
+
+		badge := models.Badge{Name: "Sportif", Criterion: enums.AttendedEventsCriterion, Threshold: 2, CategoryID: &sport.ID}
+		assert.NoError(t, badgeService.CreateBadge(&badge))
+
+		user := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(user).Error)
+
+		assert.NoError(t, checkInService.ConfirmParticipation(createConfirmableParticipation(t, user.ID, sport.ID), nil))
+		assert.NoError(t, checkInService.ConfirmParticipation(createConfirmableParticipation(t, user.ID, culture.ID), nil))
+		assert.Empty(t, userBadgeIDs(t, user.ID))
+
+		assert.NoError(t, checkInService.ConfirmParticipation(createConfirmableParticipation(t, user.ID, sport.ID), nil))
+		assert.Equal(t, []string{badge.ID}, userBadgeIDs(t, user.ID))
+
+		// Le badge n'est attribué qu'une fois
+		awarded, err := badgeService.Evaluate(user.ID, enums.AttendedEventsCriterion)
+		assert.NoError(t, err)
+		assert.Empty(t, awarded)
+
+		found, err := services.NewUserService().FindByID(user.ID)
+		assert.NoError(t, err)
+		assert.Len(t, found.Badges, 1)
+		assert.Equal(t, "Sportif", found.Badges[0].Badge.Name)
+	})
+
+	t.Run("JoinedAssociations", func(t *testing.T) {
+		badge := models.Badge{Name: "Curieux", Criterion: enums.JoinedAssociationsCriterion, Threshold: 2}
+		assert.NoError(t, badgeService.CreateBadge(&badge))
+
+		user := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(user).Error)
+		userService := services.NewUserService()
+
+		for i := 0; i < 2; i++ {
+			_, association := test_utils.CreateUserAndAssociation()
+			_, err := userService.JoinAssociation(user.ID, association.ID, association.Code)
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, []string{badge.ID}, userBadgeIDs(t, user.ID))
+	})
+
+	t.Run("CreateBadge_AwardsRetroactively", func(t *testing.T) {
+		shiftService := services.NewEventShiftService()
+		_, association := test_utils.CreateUserAndAssociation()
+		event := test_utils.GetValidEvent(association.ID)
+		assert.NoError(t, database.CurrentDatabase.Create(&event).Error)
+		shift := &models.EventShift{Name: "Accueil", StartsAt: event.Date, EndsAt: event.Date.Add(time.Hour), Capacity: 2}
+		assert.NoError(t, shiftService.CreateShift(&event, shift))
+
+		volunteer := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(volunteer).Error)
+		_, err := shiftService.SignUp(shift, volunteer)
+		assert.NoError(t, err)
+		_, err = shiftService.CompleteSignup(&event, shift, volunteer.ID, nil, time.Now())
+		assert.NoError(t, err)
+
+		badge := models.Badge{Name: "Premier coup de main", Criterion: enums.CompletedShiftsCriterion, Threshold: 1}
+		assert.NoError(t, badgeService.CreateBadge(&badge))
+		assert.Equal(t, []string{badge.ID}, userBadgeIDs(t, volunteer.ID))
+
+		// Supprimer la définition retire le badge
+		assert.NoError(t, badgeService.DeleteBadge(&badge))
+		assert.Empty(t, userBadgeIDs(t, volunteer.ID))
+	})
+}
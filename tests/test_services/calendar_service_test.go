@@ -0,0 +1,77 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/services"
+	"backend/tests/test_utils"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarService(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	service := services.NewCalendarService()
+
+	t.Run("RegenerateUserCalendarToken_RevokesPreviousToken", func(t *testing.T) {
+		user, _ := test_utils.CreateUserAndAssociation()
+
+		firstToken, err := service.GetUserCalendarToken(user)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, firstToken)
+
+		secondToken, err := service.RegenerateUserCalendarToken(user)
+		assert.NoError(t, err)
+		assert.NotEqual(t, firstToken, secondToken)
+
+		_, err = service.GetUserByCalendarToken(firstToken)
+		assert.Error(t, err)
+
+		found, err := service.GetUserByCalendarToken(secondToken)
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, found.ID)
+	})
+
+	t.Run("GetUserByCalendarToken_EmptyToken", func(t *testing.T) {
+		test_utils.CreateUserAndAssociation()
+
+		_, err := service.GetUserByCalendarToken("")
+		assert.Error(t, err)
+	})
+
+	t.Run("BuildUserFeed", func(t *testing.T) {
+		user, association := test_utils.CreateUserAndAssociation()
+
+		event := test_utils.GetValidEvent(association.ID)
+		assert.NoError(t, database.CurrentDatabase.Create(&event).Error)
+
+		participation := test_utils.GetValidParticipation(user.ID, event.ID)
+		assert.NoError(t, database.CurrentDatabase.Create(&participation).Error)
+
+		calendar, err := service.BuildUserFeed(user)
+		assert.NoError(t, err)
+
+		feed := calendar.Serialize()
+		assert.Contains(t, feed, "UID:"+event.ID+"@challenge-s4")
+		assert.Contains(t, feed, "SUMMARY:"+event.Name)
+		assert.Contains(t, feed, "BEGIN:VTIMEZONE")
+	})
+
+	t.Run("BuildAssociationFeed_RecurringEvent", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+
+		event := test_utils.GetValidEvent(association.ID)
+		event.RecurrenceRule = "FREQ=WEEKLY;COUNT=4"
+		assert.NoError(t, database.CurrentDatabase.Create(&event).Error)
+
+		calendar, err := service.BuildAssociationFeed(association)
+		assert.NoError(t, err)
+
+		feed := calendar.Serialize()
+		assert.Contains(t, feed, "RRULE:FREQ=WEEKLY;COUNT=4")
+		assert.Equal(t, 1, strings.Count(feed, "BEGIN:VEVENT"))
+	})
+}
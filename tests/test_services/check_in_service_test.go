@@ -0,0 +1,255 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckInService(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+	t.Setenv("JWT_KEY", "test-check-in-secret")
+
+	service := services.NewCheckInService()
+
+	createParticipation := func(t *testing.T, date time.Time) (*models.User, *models.Participation) {
+		user, association := test_utils.CreateUserAndAssociation()
+
+		category := models.Category{Name: "Sport"}
+		assert.NoError(t, database.CurrentDatabase.Create(&category).Error)
+
+		event := test_utils.GetValidEvent(association.ID)
+		event.Date = date
+		event.CategoryID = category.ID
+		assert.NoError(t, database.CurrentDatabase.Create(&event).Error)
+
+		participation := test_utils.GetValidParticipation(user.ID, event.ID)
+		assert.NoError(t, database.CurrentDatabase.Create(&participation).Error)
+		return user, &participation
+	}
+
+	t.Run("CheckInToken_RoundTrip", func(t *testing.T) {
+		_, participation := createParticipation(t, time.Now())
+
+		var event models.Event
+		assert.NoError(t, database.CurrentDatabase.First(&event, "id = ?", participation.EventID).Error)
+
+		token, err := service.GenerateCheckInToken(participation, &event)
+		assert.NoError(t, err)
+
+		found, err := service.GetCheckInParticipation(token)
+		assert.NoError(t, err)
+		assert.Equal(t, participation.ID, found.ID)
+
+		_, err = service.GetCheckInParticipation(token + "x")
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidCheckInToken)
+	})
+
+	t.Run("CheckIn_AwardsPointsOnce", func(t *testing.T) {
+		user, participation := createParticipation(t, time.Now())
+
+		var event models.Event
+		assert.NoError(t, database.CurrentDatabase.First(&event, "id = ?", participation.EventID).Error)
+		token, err := service.GenerateCheckInToken(participation, &event)
+		assert.NoError(t, err)
+
+		scanned, err := service.GetCheckInParticipation(token)
+		assert.NoError(t, err)
+		assert.NoError(t, service.CheckIn(scanned, nil, time.Now()))
+		assert.Equal(t, enums.Present, scanned.Attendance)
+		assert.NotNil(t, scanned.CheckedInAt)
+
+		scanned, err = service.GetCheckInParticipation(token)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, service.CheckIn(scanned, nil, time.Now()), coreErrors.ErrAlreadyCheckedIn)
+
+		var updatedUser models.User
+		assert.NoError(t, database.CurrentDatabase.First(&updatedUser, "id = ?", user.ID).Error)
+		assert.Equal(t, user.PointsOpen+scanned.Event.Category.Note, updatedUser.PointsOpen)
+	})
+
+	t.Run("CheckIn_NotOpenYet", func(t *testing.T) {
+		_, participation := createParticipation(t, time.Now().Add(48*time.Hour))
+
+		var event models.Event
+		assert.NoError(t, database.CurrentDatabase.First(&event, "id = ?", participation.EventID).Error)
+		token, err := service.GenerateCheckInToken(participation, &event)
+		assert.NoError(t, err)
+
+		scanned, err := service.GetCheckInParticipation(token)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, service.CheckIn(scanned, nil, time.Now()), coreErrors.ErrCheckInNotOpen)
+	})
+
+	t.Run("CheckIn_RejectsDeclinedAndWaitlisted", func(t *testing.T) {
+		for _, status := range []string{enums.ParticipationDeclined, enums.ParticipationWaitlisted} {
+			user, participation := createParticipation(t, time.Now())
+
+			var event models.Event
+			assert.NoError(t, database.CurrentDatabase.First(&event, "id = ?", participation.EventID).Error)
+			token, err := service.GenerateCheckInToken(participation, &event)
+			assert.NoError(t, err)
+
+			// Le QR code a été généré avant que la participation ne soit refusée ou mise en attente
+			assert.NoError(t, database.CurrentDatabase.Model(participation).Update("status", status).Error)
+
+			scanned, err := service.GetCheckInParticipation(token)
+			assert.NoError(t, err)
+			assert.ErrorIs(t, service.CheckIn(scanned, nil, time.Now()), coreErrors.ErrNotRegistered)
+
+			var stored models.Participation
+			assert.NoError(t, database.CurrentDatabase.First(&stored, "id = ?", participation.ID).Error)
+			assert.Equal(t, status, stored.Status)
+			assert.Nil(t, stored.CheckedInAt)
+
+			var updatedUser models.User
+			assert.NoError(t, database.CurrentDatabase.First(&updatedUser, "id = ?", user.ID).Error)
+			assert.Equal(t, user.PointsOpen, updatedUser.PointsOpen)
+		}
+	})
+
+	t.Run("MarkAbsentees", func(t *testing.T) {
+		_, participation := createParticipation(t, time.Now().Add(-24*time.Hour))
+
+		_, err := service.MarkAbsentees(time.Now())
+		assert.NoError(t, err)
+
+		var updated models.Participation
+		assert.NoError(t, database.CurrentDatabase.First(&updated, "id = ?", participation.ID).Error)
+		assert.Equal(t, enums.Absent, updated.Attendance)
+	})
+
+	// createBulkEvent crée un événement de la catégorie avec des participations en attente
+	createBulkEvent := func(t *testing.T, date time.Time, participants int) (*models.Event, []models.Participation) {
+		_, association := test_utils.CreateUserAndAssociation()
+		category := models.Category{Name: "Solidarité"}
+		assert.NoError(t, database.CurrentDatabase.Create(&category).Error)
+		assert.NoError(t, database.CurrentDatabase.Model(&category).Update("note", 5).Error)
+
+		event := test_utils.GetValidEvent(association.ID)
+		event.Date = date
+		event.CategoryID = category.ID
+		assert.NoError(t, database.CurrentDatabase.Create(&event).Error)
+		assert.NoError(t, database.CurrentDatabase.Preload("Category").First(&event, "id = ?", event.ID).Error)
+
+		participations := make([]models.Participation, participants)
+		for i := range participations {
+			user := test_utils.GetAuthenticatedUser()
+			assert.NoError(t, database.CurrentDatabase.Create(user).Error)
+			participations[i] = test_utils.GetValidParticipation(user.ID, event.ID)
+			assert.NoError(t, database.CurrentDatabase.Create(&participations[i]).Error)
+		}
+		return &event, participations
+	}
+
+	findParticipation := func(t *testing.T, id string) models.Participation {
+		var participation models.Participation
+		assert.NoError(t, database.CurrentDatabase.First(&participation, "id = ?", id).Error)
+		return participation
+	}
+
+	t.Run("BulkAttendance_InvalidInput", func(t *testing.T) {
+		event, participations := createBulkEvent(t, time.Now(), 1)
+
+		_, err := service.BulkAttendance(event, services.BulkAttendanceInput{Action: "maybe", AllPending: true}, nil, time.Now())
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidBulkAttendance)
+		_, err = service.BulkAttendance(event, services.BulkAttendanceInput{Action: enums.ConfirmAttendance}, nil, time.Now())
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidBulkAttendance)
+		_, err = service.BulkAttendance(event, services.BulkAttendanceInput{Action: enums.ConfirmAttendance, AllPending: true, ParticipationIDs: []string{participations[0].ID}}, nil, time.Now())
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidBulkAttendance)
+	})
+
+	t.Run("BulkAttendance_ConfirmReportsEachParticipation", func(t *testing.T) {
+		event, participations := createBulkEvent(t, time.Now(), 3)
+		_, other := createBulkEvent(t, time.Now(), 1)
+
+		// La deuxième participation est déjà confirmée ; celle d'un autre événement est ignorée
+		assert.NoError(t, database.CurrentDatabase.Model(&participations[1]).Update("status", enums.ParticipationConfirmed).Error)
+		ids := []string{participations[0].ID, participations[1].ID, other[0].ID, participations[0].ID}
+
+		results, err := service.BulkAttendance(event, services.BulkAttendanceInput{Action: enums.ConfirmAttendance, ParticipationIDs: ids}, nil, time.Now())
+		assert.NoError(t, err)
+		if assert.Len(t, results, 3) {
+			assert.Equal(t, participations[0].ID, results[0].ParticipationID)
+			assert.NoError(t, results[0].Err)
+			assert.ErrorIs(t, results[1].Err, coreErrors.ErrParticipationAlreadyHandled)
+			assert.Equal(t, other[0].ID, results[2].ParticipationID)
+			assert.ErrorIs(t, results[2].Err, coreErrors.ErrNotRegistered)
+		}
+		assert.Equal(t, enums.ParticipationConfirmed, findParticipation(t, participations[0].ID).Status)
+		assert.Equal(t, enums.ParticipationPending, findParticipation(t, participations[2].ID).Status)
+
+		var user models.User
+		assert.NoError(t, database.CurrentDatabase.First(&user, "id = ?", participations[0].UserID).Error)
+		assert.Equal(t, 5, user.PointsOpen)
+	})
+
+	t.Run("BulkAttendance_DeclineAllPending", func(t *testing.T) {
+		event, participations := createBulkEvent(t, time.Now(), 2)
+
+		results, err := service.BulkAttendance(event, services.BulkAttendanceInput{Action: enums.DeclineAttendance, AllPending: true}, nil, time.Now())
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		for _, participation := range participations {
+			declined := findParticipation(t, participation.ID)
+			assert.Equal(t, enums.ParticipationDeclined, declined.Status)
+			assert.False(t, declined.IsAttending)
+		}
+	})
+
+	t.Run("BulkAttendance_PresentAndAbsent", func(t *testing.T) {
+		event, participations := createBulkEvent(t, time.Now(), 2)
+
+		results, err := service.BulkAttendance(event, services.BulkAttendanceInput{Action: enums.MarkPresent, ParticipationIDs: []string{participations[0].ID}}, nil, time.Now())
+		assert.NoError(t, err)
+		assert.NoError(t, results[0].Err)
+
+		// Les participants non pointés restants sont marqués absents
+		results, err = service.BulkAttendance(event, services.BulkAttendanceInput{Action: enums.MarkAbsent, AllPending: true}, nil, time.Now())
+		assert.NoError(t, err)
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, participations[1].ID, results[0].ParticipationID)
+		}
+
+		present := findParticipation(t, participations[0].ID)
+		assert.Equal(t, enums.Present, present.Attendance)
+		assert.Equal(t, enums.ParticipationConfirmed, present.Status)
+		assert.NotNil(t, present.CheckedInAt)
+		assert.Equal(t, enums.Absent, findParticipation(t, participations[1].ID).Attendance)
+
+		// Le pointage n'est pas ouvert avant l'événement
+		future, _ := createBulkEvent(t, time.Now().Add(48*time.Hour), 1)
+		_, err = service.BulkAttendance(future, services.BulkAttendanceInput{Action: enums.MarkPresent, AllPending: true}, nil, time.Now())
+		assert.ErrorIs(t, err, coreErrors.ErrCheckInNotOpen)
+	})
+
+	t.Run("BulkAttendance_SkipsWaitlisted", func(t *testing.T) {
+		event, participations := createBulkEvent(t, time.Now(), 2)
+		assert.NoError(t, database.CurrentDatabase.Model(&participations[1]).Update("status", enums.ParticipationWaitlisted).Error)
+
+		results, err := service.BulkAttendance(event, services.BulkAttendanceInput{Action: enums.MarkPresent, ParticipationIDs: []string{participations[0].ID, participations[1].ID}}, nil, time.Now())
+		assert.NoError(t, err)
+		if assert.Len(t, results, 2) {
+			assert.NoError(t, results[0].Err)
+			assert.ErrorIs(t, results[1].Err, coreErrors.ErrNotRegistered)
+		}
+
+		waitlisted := findParticipation(t, participations[1].ID)
+		assert.Equal(t, enums.ParticipationWaitlisted, waitlisted.Status)
+		assert.Nil(t, waitlisted.CheckedInAt)
+		assert.Zero(t, test_utils.GetPointsBalance(participations[1].UserID))
+
+		// Un participant en liste d'attente n'est pas non plus compté dans les absents
+		results, err = service.BulkAttendance(event, services.BulkAttendanceInput{Action: enums.MarkAbsent, AllPending: true}, nil, time.Now())
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
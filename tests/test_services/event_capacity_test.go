@@ -0,0 +1,114 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventCapacity(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	service := services.NewEventService()
+
+	createEvent := func(t *testing.T, capacity int) *models.Event {
+		_, association := test_utils.CreateUserAndAssociation()
+
+		event := test_utils.GetValidEvent(association.ID)
+		event.Capacity = &capacity
+		createdEvent, err := service.AddEvent(&event)
+		assert.NoError(t, err)
+		return createdEvent
+	}
+
+	t.Run("ChangeUserEventAttend_Waitlist", func(t *testing.T) {
+		event := createEvent(t, 1)
+		first, second, third := test_utils.CreateUser(), test_utils.CreateUser(), test_utils.CreateUser()
+
+		participation, err := service.ChangeUserEventAttend(true, event.ID, first.ID)
+		assert.NoError(t, err)
+		assert.True(t, participation.IsAttending)
+
+		participation, err = service.ChangeUserEventAttend(true, event.ID, second.ID)
+		assert.NoError(t, err)
+		assert.False(t, participation.IsAttending)
+		assert.Equal(t, enums.ParticipationWaitlisted, participation.Status)
+		assert.Equal(t, 1, participation.WaitlistPosition)
+
+		participation, err = service.ChangeUserEventAttend(true, event.ID, third.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, participation.WaitlistPosition)
+	})
+
+	t.Run("ChangeUserEventAttend_PromotesNextOnUnregister", func(t *testing.T) {
+		event := createEvent(t, 1)
+		first, second, third := test_utils.CreateUser(), test_utils.CreateUser(), test_utils.CreateUser()
+
+		_, err := service.ChangeUserEventAttend(true, event.ID, first.ID)
+		assert.NoError(t, err)
+		_, err = service.ChangeUserEventAttend(true, event.ID, second.ID)
+		assert.NoError(t, err)
+		_, err = service.ChangeUserEventAttend(true, event.ID, third.ID)
+		assert.NoError(t, err)
+
+		_, err = service.ChangeUserEventAttend(false, event.ID, first.ID)
+		assert.NoError(t, err)
+
+		assert.True(t, service.IsUserAttendingEvent(event.ID, second.ID))
+		assert.False(t, service.IsUserAttendingEvent(event.ID, third.ID))
+
+		participation := service.GetUserEventParticipation(event.ID, third.ID)
+		assert.NotNil(t, participation)
+		assert.Equal(t, 1, participation.WaitlistPosition)
+	})
+
+	t.Run("UpdateEvent_IncreasedCapacityPromotes", func(t *testing.T) {
+		event := createEvent(t, 1)
+		first, second := test_utils.CreateUser(), test_utils.CreateUser()
+
+		_, err := service.ChangeUserEventAttend(true, event.ID, first.ID)
+		assert.NoError(t, err)
+		_, err = service.ChangeUserEventAttend(true, event.ID, second.ID)
+		assert.NoError(t, err)
+
+		capacity := 2
+		event.Capacity = &capacity
+		assert.NoError(t, service.UpdateEvent(event))
+
+		assert.True(t, service.IsUserAttendingEvent(event.ID, second.ID))
+	})
+
+	t.Run("ChangeUserEventAttend_ConcurrentSignUps", func(t *testing.T) {
+		const capacity = 3
+		event := createEvent(t, capacity)
+
+		users := make([]*models.User, 10)
+		for i := range users {
+			users[i] = test_utils.CreateUser()
+		}
+
+		var wg sync.WaitGroup
+		for _, user := range users {
+			wg.Add(1)
+			go func(userID string) {
+				defer wg.Done()
+				_, err := service.ChangeUserEventAttend(true, event.ID, userID)
+				assert.NoError(t, err)
+			}(user.ID)
+		}
+		wg.Wait()
+
+		var attending, waitlisted int64
+		database.CurrentDatabase.Model(&models.Participation{}).Where("event_id = ? AND is_attending = ?", event.ID, true).Count(&attending)
+		database.CurrentDatabase.Model(&models.Participation{}).Where("event_id = ? AND status = ?", event.ID, enums.ParticipationWaitlisted).Count(&waitlisted)
+		assert.Equal(t, int64(capacity), attending)
+		assert.Equal(t, int64(len(users)-capacity), waitlisted)
+	})
+}
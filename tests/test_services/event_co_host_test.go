@@ -0,0 +1,121 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"backend/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventCoHost(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	coHostService := services.NewEventCoHostService()
+	eventService := services.NewEventService()
+	associationService := services.NewAssociationService()
+	visibilityService := services.NewEventVisibilityService()
+
+	createEvent := func(associationID string) *models.Event {
+		return test_utils.CreateEvent(associationID, func(event *models.Event) {
+			event.Visibility = enums.MembersVisibility
+		})
+	}
+
+	associationEventIDs := func(t *testing.T, associationID string, viewer *models.User) []string {
+		pagination, err := associationService.GetAssociationEvents(associationID, viewer, utils.Pagination{})
+		assert.NoError(t, err)
+		var ids []string
+		for _, event := range pagination.Rows.([]models.Event) {
+			ids = append(ids, event.ID)
+		}
+		return ids
+	}
+
+	t.Run("InviteCoHost_RejectsPrimaryHost", func(t *testing.T) {
+		owner, association := test_utils.CreateUserAndAssociation()
+		event := createEvent(association.ID)
+
+		_, err := coHostService.InviteCoHost(event, association.ID, owner.ID)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidCoHost)
+
+		_, err = coHostService.InviteCoHost(event, "unknown", owner.ID)
+		assert.ErrorIs(t, err, coreErrors.ErrAssociationNotFound)
+	})
+
+	t.Run("AcceptedCoHost_SharesListingsAndLeadership", func(t *testing.T) {
+		owner, association := test_utils.CreateUserAndAssociation()
+		coHostOwner, coHostAssociation := test_utils.CreateUserAndAssociation()
+		event := createEvent(association.ID)
+
+		member := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(member).Error)
+		assert.NoError(t, database.CurrentDatabase.Create(&models.Membership{
+			UserID:        member.ID,
+			AssociationID: coHostAssociation.ID,
+			Status:        enums.Accepted,
+			JoinedAt:      time.Now(),
+		}).Error)
+
+		coHost, err := coHostService.InviteCoHost(event, coHostAssociation.ID, owner.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, enums.Pending, coHost.Status)
+
+		// Tant que l'invitation n'est pas acceptée, l'événement reste propre à l'association organisatrice
+		assert.NotContains(t, associationEventIDs(t, coHostAssociation.ID, coHostOwner), event.ID)
+		isLeader, err := eventService.IsEventLeader(coHostOwner, event)
+		assert.NoError(t, err)
+		assert.False(t, isLeader)
+
+		_, err = coHostService.RespondToInvitation(event, coHostAssociation.ID, true)
+		assert.NoError(t, err)
+
+		assert.Contains(t, associationEventIDs(t, coHostAssociation.ID, coHostOwner), event.ID)
+		assert.Contains(t, associationEventIDs(t, association.ID, owner), event.ID)
+		isLeader, err = eventService.IsEventLeader(coHostOwner, event)
+		assert.NoError(t, err)
+		assert.True(t, isLeader)
+		canView, err := visibilityService.CanViewEvent(member, event)
+		assert.NoError(t, err)
+		assert.True(t, canView)
+
+		stored, err := eventService.GetEventById(event.ID)
+		assert.NoError(t, err)
+		assert.Len(t, stored.CoHosts, 1)
+
+		assert.NoError(t, coHostService.RemoveCoHost(event, coHostAssociation.ID))
+		canView, err = visibilityService.CanViewEvent(member, event)
+		assert.NoError(t, err)
+		assert.False(t, canView)
+	})
+
+	t.Run("RejectedInvitation_CanBeRenewed", func(t *testing.T) {
+		owner, association := test_utils.CreateUserAndAssociation()
+		_, coHostAssociation := test_utils.CreateUserAndAssociation()
+		event := createEvent(association.ID)
+
+		_, err := coHostService.InviteCoHost(event, coHostAssociation.ID, owner.ID)
+		assert.NoError(t, err)
+		_, err = coHostService.RespondToInvitation(event, coHostAssociation.ID, false)
+		assert.NoError(t, err)
+
+		pending, err := coHostService.GetPendingInvitations(coHostAssociation.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, pending)
+
+		coHost, err := coHostService.InviteCoHost(event, coHostAssociation.ID, owner.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, enums.Pending, coHost.Status)
+
+		pending, err = coHostService.GetPendingInvitations(coHostAssociation.ID)
+		assert.NoError(t, err)
+		assert.Len(t, pending, 1)
+	})
+}
@@ -0,0 +1,113 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"backend/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventComment(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	commentService := services.NewEventCommentService()
+	eventService := services.NewEventService()
+
+	createEvent := func(associationID string) *models.Event {
+		return test_utils.CreateEvent(associationID, func(event *models.Event) {
+			event.Visibility = enums.PublicVisibility
+		})
+	}
+
+	t.Run("AddComment_RepliesOneLevelDeep", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := createEvent(association.ID)
+		author := test_utils.CreateUser()
+
+		question, err := commentService.AddComment(event, author, "Y a-t-il un parking ?", nil)
+		assert.NoError(t, err)
+
+		reply, err := commentService.AddComment(event, author, "Oui, derrière la salle", &question.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, question.ID, *reply.ParentID)
+
+		_, err = commentService.AddComment(event, author, "Merci !", &reply.ID)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidCommentReply)
+
+		unknown := "unknown"
+		_, err = commentService.AddComment(event, author, "Merci !", &unknown)
+		assert.ErrorIs(t, err, coreErrors.ErrNotFound)
+
+		_, err = commentService.AddComment(event, author, "   ", nil)
+		assert.Error(t, err)
+
+		pagination, err := commentService.GetComments(event, utils.Pagination{})
+		assert.NoError(t, err)
+		comments := pagination.Rows.([]models.EventComment)
+		assert.Len(t, comments, 1)
+		assert.Len(t, comments[0].Replies, 1)
+		assert.Equal(t, reply.ID, comments[0].Replies[0].ID)
+	})
+
+	t.Run("SetAnswered_OnlyTopLevelComments", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := createEvent(association.ID)
+		author := test_utils.CreateUser()
+
+		question, err := commentService.AddComment(event, author, "À quelle heure ouvrent les portes ?", nil)
+		assert.NoError(t, err)
+		reply, err := commentService.AddComment(event, author, "19h", &question.ID)
+		assert.NoError(t, err)
+
+		assert.NoError(t, commentService.SetAnswered(question, true))
+		stored, err := commentService.GetComment(event, question.ID)
+		assert.NoError(t, err)
+		assert.True(t, stored.Answered)
+
+		assert.ErrorIs(t, commentService.SetAnswered(reply, true), coreErrors.ErrInvalidCommentReply)
+	})
+
+	t.Run("DeleteComment_RemovesReplies", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := createEvent(association.ID)
+		author := test_utils.CreateUser()
+
+		question, err := commentService.AddComment(event, author, "Faut-il apporter à manger ?", nil)
+		assert.NoError(t, err)
+		_, err = commentService.AddComment(event, author, "Non, tout est prévu", &question.ID)
+		assert.NoError(t, err)
+
+		assert.NoError(t, commentService.DeleteComment(question))
+
+		var count int64
+		database.CurrentDatabase.Model(&models.EventComment{}).Where("event_id = ?", event.ID).Count(&count)
+		assert.Zero(t, count)
+	})
+
+	t.Run("AddComment_OccurrenceBelongsToSeries", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		series := test_utils.GetValidEvent(association.ID)
+		series.Visibility = enums.PublicVisibility
+		series.RecurrenceRule = "FREQ=WEEKLY;COUNT=3"
+		_, err := eventService.AddEvent(&series)
+		assert.NoError(t, err)
+
+		occurrence, err := eventService.GetOrCreateOccurrence(series.ID, series.Date.AddDate(0, 0, 7))
+		assert.NoError(t, err)
+
+		author := test_utils.CreateUser()
+		_, err = commentService.AddComment(occurrence, author, "Même lieu que la semaine dernière ?", nil)
+		assert.NoError(t, err)
+
+		pagination, err := commentService.GetComments(&series, utils.Pagination{})
+		assert.NoError(t, err)
+		assert.Len(t, pagination.Rows.([]models.EventComment), 1)
+	})
+}
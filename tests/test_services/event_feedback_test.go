@@ -0,0 +1,117 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventFeedbackService(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	service := services.NewEventFeedbackService()
+	eventService := services.NewEventService()
+	now := time.Now()
+
+	createPastEvent := func(t *testing.T, associationID string) *models.Event {
+		event := test_utils.GetValidEvent(associationID)
+		event.Date = now.Add(-3 * time.Hour)
+		_, err := eventService.AddEvent(&event)
+		assert.NoError(t, err)
+		return &event
+	}
+
+	createParticipant := func(t *testing.T, eventID string, status string, attendance enums.Status) *models.User {
+		user := test_utils.CreateUser()
+		participation := test_utils.GetValidParticipation(user.ID, eventID)
+		participation.Status = status
+		participation.Attendance = attendance
+		assert.NoError(t, database.CurrentDatabase.Create(&participation).Error)
+		return user
+	}
+
+	t.Run("SubmitFeedback_ConfirmedParticipant", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := createPastEvent(t, association.ID)
+		user := createParticipant(t, event.ID, enums.ParticipationConfirmed, enums.Absent)
+
+		feedback := models.EventFeedback{Rating: 4, Comment: "  Très bien  "}
+		assert.NoError(t, service.SubmitFeedback(event, user.ID, &feedback, now))
+		assert.Equal(t, "Très bien", feedback.Comment)
+
+		// Un second avis remplace le premier
+		update := models.EventFeedback{Rating: 2}
+		assert.NoError(t, service.SubmitFeedback(event, user.ID, &update, now))
+		assert.Equal(t, feedback.ID, update.ID)
+
+		stored, err := service.GetUserFeedback(event.ID, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, stored.Rating)
+	})
+
+	t.Run("SubmitFeedback_PendingParticipantRefused", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := createPastEvent(t, association.ID)
+		pending := createParticipant(t, event.ID, enums.ParticipationPending, "")
+		present := createParticipant(t, event.ID, enums.ParticipationPending, enums.Present)
+
+		err := service.SubmitFeedback(event, pending.ID, &models.EventFeedback{Rating: 5}, now)
+		assert.ErrorIs(t, err, coreErrors.ErrNotRegistered)
+		assert.NoError(t, service.SubmitFeedback(event, present.ID, &models.EventFeedback{Rating: 5}, now))
+	})
+
+	t.Run("SubmitFeedback_OutsideWindow", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := createPastEvent(t, association.ID)
+		user := createParticipant(t, event.ID, enums.ParticipationConfirmed, "")
+
+		err := service.SubmitFeedback(event, user.ID, &models.EventFeedback{Rating: 3}, event.Date.Add(time.Minute))
+		assert.ErrorIs(t, err, coreErrors.ErrFeedbackClosed)
+
+		err = service.SubmitFeedback(event, user.ID, &models.EventFeedback{Rating: 3}, event.EndsAt().Add(services.FeedbackWindow+time.Hour))
+		assert.ErrorIs(t, err, coreErrors.ErrFeedbackClosed)
+	})
+
+	t.Run("SubmitFeedback_InvalidRating", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := createPastEvent(t, association.ID)
+		user := createParticipant(t, event.ID, enums.ParticipationConfirmed, "")
+
+		assert.Error(t, service.SubmitFeedback(event, user.ID, &models.EventFeedback{Rating: 6}, now))
+	})
+
+	t.Run("GetAssociationFeedback", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		first := createPastEvent(t, association.ID)
+		second := createPastEvent(t, association.ID)
+
+		for _, rating := range []int{5, 3} {
+			user := createParticipant(t, first.ID, enums.ParticipationConfirmed, "")
+			assert.NoError(t, service.SubmitFeedback(first, user.ID, &models.EventFeedback{Rating: rating}, now))
+		}
+		user := createParticipant(t, second.ID, enums.ParticipationConfirmed, "")
+		assert.NoError(t, service.SubmitFeedback(second, user.ID, &models.EventFeedback{Rating: 1}, now))
+
+		eventReport, err := service.GetEventFeedback(first)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), eventReport.Count)
+		assert.InDelta(t, 4, eventReport.AverageRating, 0.001)
+		assert.Len(t, eventReport.Feedback, 2)
+
+		report, err := service.GetAssociationFeedback(association.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), report.Count)
+		assert.InDelta(t, 3, report.AverageRating, 0.001)
+		assert.Equal(t, int64(1), report.Distribution[5])
+		assert.Equal(t, int64(0), report.Distribution[4])
+		assert.Len(t, report.Events, 2)
+	})
+}
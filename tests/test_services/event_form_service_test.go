@@ -0,0 +1,139 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventFormService(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	formService := services.NewEventFormService()
+	eventService := services.NewEventService()
+
+	createEventWithForm := func(t *testing.T) (*models.User, *models.Event, []models.EventQuestion) {
+		user, association := test_utils.CreateUserAndAssociation()
+
+		event := test_utils.GetValidEvent(association.ID)
+		assert.NoError(t, database.CurrentDatabase.Create(&event).Error)
+
+		questions, err := formService.SetQuestions(&event, []models.EventQuestion{
+			{Label: "Régime alimentaire", Type: enums.SingleChoiceQuestion, Options: models.StringList{"Aucun", "Végétarien", "Vegan"}, Required: true},
+			{Label: "Taille de T-shirt", Type: enums.MultipleChoiceQuestion, Options: models.StringList{"S", "M", "L"}},
+			{Label: "Âge", Type: enums.NumberQuestion},
+			{Label: "Motivation", Type: enums.TextQuestion},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, questions, 4)
+		return user, &event, questions
+	}
+
+	t.Run("SetQuestions_ChoiceWithoutOptions", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := test_utils.GetValidEvent(association.ID)
+		assert.NoError(t, database.CurrentDatabase.Create(&event).Error)
+
+		_, err := formService.SetQuestions(&event, []models.EventQuestion{
+			{Label: "Choix", Type: enums.SingleChoiceQuestion},
+		})
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidQuestion)
+	})
+
+	t.Run("SetQuestions_UpdatesAndDeletes", func(t *testing.T) {
+		_, event, questions := createEventWithForm(t)
+
+		questions[0].Label = "Régime"
+		updated, err := formService.SetQuestions(event, []models.EventQuestion{questions[0]})
+		assert.NoError(t, err)
+		assert.Len(t, updated, 1)
+		assert.Equal(t, questions[0].ID, updated[0].ID)
+		assert.Equal(t, "Régime", updated[0].Label)
+	})
+
+	t.Run("SetQuestions_ForeignIDCreatesQuestion", func(t *testing.T) {
+		_, otherEvent, otherQuestions := createEventWithForm(t)
+		_, event, _ := createEventWithForm(t)
+
+		foreign := otherQuestions[3]
+		updated, err := formService.SetQuestions(event, []models.EventQuestion{foreign})
+		assert.NoError(t, err)
+		assert.Len(t, updated, 1)
+		assert.NotEqual(t, foreign.ID, updated[0].ID)
+
+		unchanged, err := formService.GetQuestions(otherEvent)
+		assert.NoError(t, err)
+		assert.Len(t, unchanged, 4)
+	})
+
+	t.Run("SetQuestions_TypeChangeDeletesAnswers", func(t *testing.T) {
+		user, event, questions := createEventWithForm(t)
+
+		participation, err := eventService.ChangeUserEventAttendWithAnswers(true, event.ID, user.ID, []services.ParticipationAnswerInput{
+			{QuestionID: questions[0].ID, Value: "Vegan"},
+			{QuestionID: questions[3].ID, Value: "Pour aider"},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, participation.Answers, 2)
+
+		questions[3].Type = enums.NumberQuestion
+		_, err = formService.SetQuestions(event, questions)
+		assert.NoError(t, err)
+
+		var answers []models.ParticipationAnswer
+		assert.NoError(t, database.CurrentDatabase.Where("participation_id = ?", participation.ID).Find(&answers).Error)
+		assert.Len(t, answers, 1)
+		assert.Equal(t, questions[0].ID, answers[0].QuestionID)
+	})
+
+	t.Run("ChangeUserEventAttendWithAnswers_RequiredAnswer", func(t *testing.T) {
+		user, event, _ := createEventWithForm(t)
+
+		_, err := eventService.ChangeUserEventAttendWithAnswers(true, event.ID, user.ID, nil)
+		var answersErr *services.AnswersValidationError
+		assert.True(t, errors.As(err, &answersErr))
+		assert.Nil(t, eventService.GetUserEventParticipation(event.ID, user.ID))
+	})
+
+	t.Run("ChangeUserEventAttendWithAnswers_InvalidChoice", func(t *testing.T) {
+		user, event, questions := createEventWithForm(t)
+
+		_, err := eventService.ChangeUserEventAttendWithAnswers(true, event.ID, user.ID, []services.ParticipationAnswerInput{
+			{QuestionID: questions[0].ID, Value: "Carnivore"},
+		})
+		var answersErr *services.AnswersValidationError
+		assert.True(t, errors.As(err, &answersErr))
+		assert.Contains(t, answersErr.Details, questions[0].ID)
+	})
+
+	t.Run("ChangeUserEventAttendWithAnswers_StoresAndExports", func(t *testing.T) {
+		user, event, questions := createEventWithForm(t)
+
+		participation, err := eventService.ChangeUserEventAttendWithAnswers(true, event.ID, user.ID, []services.ParticipationAnswerInput{
+			{QuestionID: questions[0].ID, Value: "Vegan"},
+			{QuestionID: questions[1].ID, Value: []interface{}{"S", "M"}},
+			{QuestionID: questions[2].ID, Value: float64(21)},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, participation.Answers, 3)
+
+		options := services.ParticipantExportOptions{Format: services.ExportFormatCSV, Columns: services.AnswersExportColumns}
+		var buffer bytes.Buffer
+		assert.NoError(t, services.NewParticipantExportService().ExportEventParticipants(event, options, &buffer))
+
+		records, err := csv.NewReader(&buffer).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, []string{"Vegan", "S, M", "21", ""}, records[1][4:])
+	})
+}
@@ -0,0 +1,195 @@
+package services_test
+
+import (
+	"backend/database"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const importCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Test//FR
+BEGIN:VEVENT
+UID:weekly-meeting@example.com
+DTSTART;TZID=Europe/Paris:20300107T180000
+SUMMARY:Réunion hebdomadaire
+LOCATION:Salle B12
+RRULE:FREQ=WEEKLY;COUNT=5
+EXDATE;TZID=Europe/Paris:20300121T180000
+END:VEVENT
+BEGIN:VEVENT
+UID:weekly-meeting@example.com
+RECURRENCE-ID;TZID=Europe/Paris:20300114T180000
+DTSTART;TZID=Europe/Paris:20300114T190000
+SUMMARY:Réunion hebdomadaire (décalée)
+END:VEVENT
+BEGIN:VEVENT
+UID:gala@example.com
+DTSTART:20300301T190000Z
+SUMMARY:Gala
+CATEGORIES:Soirée
+END:VEVENT
+BEGIN:VEVENT
+UID:cancelled@example.com
+DTSTART:20300401T190000Z
+SUMMARY:Annulé
+STATUS:CANCELLED
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestEventImportService(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	service := services.NewEventImportService()
+
+	defaultCategory := models.Category{Name: "Général"}
+	assert.NoError(t, database.CurrentDatabase.Create(&defaultCategory).Error)
+	partyCategory := models.Category{Name: "Soirée"}
+	assert.NoError(t, database.CurrentDatabase.Create(&partyCategory).Error)
+
+	t.Run("PreviewImport_DoesNotSave", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+
+		calendar, err := service.ParseCalendar(strings.NewReader(importCalendar))
+		assert.NoError(t, err)
+
+		report, err := service.PreviewImport(calendar, association.ID, defaultCategory.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, report.Created)
+		assert.Equal(t, 1, report.Skipped)
+
+		var count int64
+		database.CurrentDatabase.Model(&models.Event{}).Where("association_id = ?", association.ID).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("ImportCalendar_Idempotent", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+
+		calendar, err := service.ParseCalendar(strings.NewReader(importCalendar))
+		assert.NoError(t, err)
+
+		report, err := service.ImportCalendar(calendar, association.ID, defaultCategory.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, report.Created)
+
+		var series models.Event
+		err = database.CurrentDatabase.Preload("RecurrenceExceptions").
+			Where("association_id = ? AND source_uid = ? AND recurrence_parent_id IS NULL", association.ID, "weekly-meeting@example.com").
+			First(&series).Error
+		assert.NoError(t, err)
+		assert.Equal(t, "FREQ=WEEKLY;COUNT=5", series.RecurrenceRule)
+		assert.Equal(t, defaultCategory.ID, series.CategoryID)
+		assert.Len(t, series.RecurrenceExceptions, 1)
+
+		var override models.Event
+		err = database.CurrentDatabase.Where("recurrence_parent_id = ?", series.ID).First(&override).Error
+		assert.NoError(t, err)
+		assert.Equal(t, "Réunion hebdomadaire (décalée)", override.Name)
+
+		var gala models.Event
+		err = database.CurrentDatabase.Where("association_id = ? AND source_uid = ?", association.ID, "gala@example.com").First(&gala).Error
+		assert.NoError(t, err)
+		assert.Equal(t, partyCategory.ID, gala.CategoryID)
+
+		calendar, err = service.ParseCalendar(strings.NewReader(importCalendar))
+		assert.NoError(t, err)
+
+		report, err = service.ImportCalendar(calendar, association.ID, defaultCategory.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, report.Created)
+		assert.Equal(t, 3, report.Unchanged)
+
+		var count int64
+		database.CurrentDatabase.Model(&models.Event{}).Where("association_id = ?", association.ID).Count(&count)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("ImportCalendar_UpdatesChangedEvent", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+
+		calendar, err := service.ParseCalendar(strings.NewReader(importCalendar))
+		assert.NoError(t, err)
+		_, err = service.ImportCalendar(calendar, association.ID, defaultCategory.ID)
+		assert.NoError(t, err)
+
+		updated := strings.Replace(importCalendar, "SUMMARY:Gala", "SUMMARY:Gala de printemps", 1)
+		calendar, err = service.ParseCalendar(strings.NewReader(updated))
+		assert.NoError(t, err)
+
+		report, err := service.ImportCalendar(calendar, association.ID, defaultCategory.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Updated)
+
+		var gala models.Event
+		err = database.CurrentDatabase.Where("association_id = ? AND source_uid = ?", association.ID, "gala@example.com").First(&gala).Error
+		assert.NoError(t, err)
+		assert.Equal(t, "Gala de printemps", gala.Name)
+	})
+
+	t.Run("ImportCalendar_ReportsInvalidEvent", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+
+		invalid := strings.Replace(importCalendar, "SUMMARY:Gala", "DTEND:20300301T180000Z\nSUMMARY:Gala", 1)
+		calendar, err := service.ParseCalendar(strings.NewReader(invalid))
+		assert.NoError(t, err)
+
+		report, err := service.ImportCalendar(calendar, association.ID, defaultCategory.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 2, report.Skipped)
+
+		for _, item := range report.Items {
+			if item.UID == "gala@example.com" {
+				assert.Equal(t, services.ImportActionSkip, item.Action)
+				assert.Equal(t, "Date de fin antérieure au début", item.Reason)
+			}
+		}
+
+		var count int64
+		database.CurrentDatabase.Model(&models.Event{}).Where("association_id = ? AND source_uid = ?", association.ID, "gala@example.com").Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("FetchCalendar", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/calendar")
+			_, _ = w.Write([]byte(importCalendar))
+		}))
+		defer server.Close()
+
+		service.HTTPClient = server.Client()
+		calendar, err := service.FetchCalendar(server.URL)
+		assert.NoError(t, err)
+		assert.Len(t, calendar.Events(), 4)
+	})
+
+	t.Run("FetchCalendar_RefusesInternalHosts", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(importCalendar))
+		}))
+		defer server.Close()
+
+		// Le client par défaut refuse les adresses de bouclage, privées et link-local
+		guarded := services.NewEventImportService()
+		for _, target := range []string{server.URL, "http://169.254.169.254/latest/meta-data", "http://10.0.0.1/calendar.ics"} {
+			_, err := guarded.FetchCalendar(target)
+			assert.ErrorIs(t, err, coreErrors.ErrInvalidCalendarURL, target)
+		}
+	})
+
+	t.Run("FetchCalendar_InvalidScheme", func(t *testing.T) {
+		_, err := service.FetchCalendar("file:///etc/passwd")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,183 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"bytes"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newUploadedFile construit le fichier reçu par le serveur pour un envoi multipart
+func newUploadedFile(t *testing.T, name string, content []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("photo", name)
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	request := httptest.NewRequest("POST", "/", &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	assert.NoError(t, request.ParseMultipartForm(32<<20))
+	return request.MultipartForm.File["photo"][0]
+}
+
+func newPNG(t *testing.T, width, height int) []byte {
+	var buffer bytes.Buffer
+	assert.NoError(t, png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return buffer.Bytes()
+}
+
+func TestEventPhotoService(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	service := services.NewEventPhotoService()
+	service.StorageDir = t.TempDir()
+	service.HiddenDir = t.TempDir()
+	eventService := services.NewEventService()
+
+	createEventWithAttendee := func(t *testing.T) (*models.Event, *models.User) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := test_utils.GetValidEvent(association.ID)
+		event.Date = time.Now().Add(-time.Hour)
+		_, err := eventService.AddEvent(&event)
+		assert.NoError(t, err)
+
+		user := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(user).Error)
+		participation := test_utils.GetValidParticipation(user.ID, event.ID)
+		participation.Status = enums.ParticipationConfirmed
+		assert.NoError(t, database.CurrentDatabase.Create(&participation).Error)
+		return &event, user
+	}
+
+	t.Run("UploadPhoto_StoresPhotoAndThumbnail", func(t *testing.T) {
+		event, user := createEventWithAttendee(t)
+
+		photo, err := service.UploadPhoto(event, user.ID, newUploadedFile(t, "photo.png", newPNG(t, 1200, 600)), false)
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", photo.ContentType)
+		assert.Equal(t, 1200, photo.Width)
+
+		thumbnailPath := filepath.Join(service.StorageDir, strings.TrimPrefix(photo.ThumbnailURL, "public/"))
+		data, err := os.ReadFile(thumbnailPath)
+		assert.NoError(t, err)
+		thumbnail, _, err := image.DecodeConfig(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, 400, thumbnail.Width)
+		assert.Equal(t, 200, thumbnail.Height)
+	})
+
+	t.Run("UploadPhoto_RejectsNonAttendeesAndInvalidFiles", func(t *testing.T) {
+		event, user := createEventWithAttendee(t)
+		other := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(other).Error)
+
+		_, err := service.UploadPhoto(event, other.ID, newUploadedFile(t, "photo.png", newPNG(t, 10, 10)), false)
+		assert.ErrorIs(t, err, coreErrors.ErrNotRegistered)
+
+		_, err = service.UploadPhoto(event, user.ID, newUploadedFile(t, "photo.png", []byte("pas une image")), false)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidImage)
+
+		// Le responsable peut alimenter la galerie sans y avoir participé
+		_, err = service.UploadPhoto(event, other.ID, newUploadedFile(t, "photo.png", newPNG(t, 10, 10)), true)
+		assert.NoError(t, err)
+	})
+
+	t.Run("UploadPhoto_PerEventQuota", func(t *testing.T) {
+		event, user := createEventWithAttendee(t)
+		content := newPNG(t, 10, 10)
+
+		for i := 0; i < services.MaxPhotosPerEvent; i++ {
+			_, err := service.UploadPhoto(event, user.ID, newUploadedFile(t, "photo.png", content), false)
+			assert.NoError(t, err)
+		}
+
+		_, err := service.UploadPhoto(event, user.ID, newUploadedFile(t, "photo.png", content), false)
+		assert.ErrorIs(t, err, coreErrors.ErrPhotoQuotaExceeded)
+	})
+
+	t.Run("HiddenPhoto_LosesCoverAndIsFiltered", func(t *testing.T) {
+		event, user := createEventWithAttendee(t)
+		photo, err := service.UploadPhoto(event, user.ID, newUploadedFile(t, "photo.png", newPNG(t, 10, 10)), false)
+		assert.NoError(t, err)
+
+		assert.NoError(t, service.SetCoverPhoto(event, photo.ID))
+		stored, err := eventService.GetEventById(event.ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, stored.CoverPhoto)
+
+		assert.NoError(t, service.SetPhotoHidden(photo, true))
+		assert.True(t, photo.Hidden)
+		stored, err = eventService.GetEventById(event.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, stored.CoverPhotoID)
+		assert.ErrorIs(t, service.SetCoverPhoto(event, photo.ID), coreErrors.ErrNotFound)
+
+		photos, err := service.GetEventPhotos(event.ID, "", false)
+		assert.NoError(t, err)
+		assert.Empty(t, photos)
+
+		// L'auteur et le responsable voient toujours la photo masquée
+		photos, err = service.GetEventPhotos(event.ID, user.ID, false)
+		assert.NoError(t, err)
+		assert.Len(t, photos, 1)
+		photos, err = service.GetEventPhotos(event.ID, "", true)
+		assert.NoError(t, err)
+		assert.Len(t, photos, 1)
+	})
+
+	t.Run("HiddenPhoto_FilesLeaveThePublicDirectory", func(t *testing.T) {
+		event, user := createEventWithAttendee(t)
+		photo, err := service.UploadPhoto(event, user.ID, newUploadedFile(t, "photo.png", newPNG(t, 10, 10)), false)
+		assert.NoError(t, err)
+		publicPath := service.PhotoFile(photo, false)
+
+		assert.NoError(t, service.SetPhotoHidden(photo, true))
+		_, err = os.Stat(publicPath)
+		assert.True(t, os.IsNotExist(err))
+		assert.True(t, strings.HasPrefix(service.PhotoFile(photo, true), service.HiddenDir))
+		_, err = os.Stat(service.PhotoFile(photo, true))
+		assert.NoError(t, err)
+
+		// Rétablie, la photo est de nouveau servie sous /public
+		assert.NoError(t, service.SetPhotoHidden(photo, false))
+		assert.Equal(t, publicPath, service.PhotoFile(photo, false))
+		_, err = os.Stat(publicPath)
+		assert.NoError(t, err)
+
+		assert.NoError(t, service.SetPhotoHidden(photo, true))
+		assert.NoError(t, service.DeletePhoto(photo))
+		_, err = os.Stat(service.PhotoFile(photo, false))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("DeletePhoto_RemovesFiles", func(t *testing.T) {
+		event, user := createEventWithAttendee(t)
+		photo, err := service.UploadPhoto(event, user.ID, newUploadedFile(t, "photo.png", newPNG(t, 10, 10)), false)
+		assert.NoError(t, err)
+
+		assert.NoError(t, service.DeletePhoto(photo))
+
+		_, err = os.Stat(filepath.Join(service.StorageDir, strings.TrimPrefix(photo.URL, "public/")))
+		assert.True(t, os.IsNotExist(err))
+		_, err = service.GetPhoto(event.ID, photo.ID)
+		assert.ErrorIs(t, err, coreErrors.ErrNotFound)
+	})
+}
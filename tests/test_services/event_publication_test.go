@@ -0,0 +1,108 @@
+package services_test
+
+import (
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventPublication(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	eventService := services.NewEventService()
+	visibilityService := services.NewEventVisibilityService()
+
+	canView := func(t *testing.T, viewer *models.User, event *models.Event) bool {
+		visible, err := visibilityService.CanViewEvent(viewer, event)
+		assert.NoError(t, err)
+		return visible
+	}
+
+	t.Run("AddEvent_PublishesByDefault", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := test_utils.GetValidEvent(association.ID)
+
+		_, err := eventService.AddEvent(&event)
+		assert.NoError(t, err)
+		assert.Equal(t, enums.PublishedPublication, event.PublicationStatus)
+		assert.NotNil(t, event.PublishedAt)
+	})
+
+	t.Run("Draft_VisibleToLeadersOnly", func(t *testing.T) {
+		owner, association := test_utils.CreateUserAndAssociation()
+		member := test_utils.CreateMember(association.ID, enums.Accepted)
+		event := test_utils.GetValidEvent(association.ID)
+		event.Visibility = enums.PublicVisibility
+		event.PublicationStatus = enums.DraftPublication
+
+		_, err := eventService.AddEvent(&event)
+		assert.NoError(t, err)
+		assert.Nil(t, event.PublishedAt)
+
+		assert.False(t, canView(t, nil, &event))
+		assert.False(t, canView(t, member, &event))
+		assert.True(t, canView(t, owner, &event))
+
+		_, err = eventService.ChangeUserEventAttend(true, event.ID, member.ID)
+		assert.ErrorIs(t, err, coreErrors.ErrEventNotPublished)
+
+		assert.NoError(t, eventService.PublishEvent(&event, time.Now()))
+		assert.True(t, canView(t, member, &event))
+		assert.ErrorIs(t, eventService.PublishEvent(&event, time.Now()), coreErrors.ErrEventAlreadyPublished)
+		assert.ErrorIs(t, eventService.RevertToDraft(&event), coreErrors.ErrEventAlreadyPublished)
+	})
+
+	t.Run("Scheduled_RequiresFuturePublishAt", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := test_utils.GetValidEvent(association.ID)
+		event.PublicationStatus = enums.ScheduledPublication
+
+		_, err := eventService.AddEvent(&event)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidPublishAt)
+
+		past := time.Now().Add(-time.Hour)
+		event.PublishAt = &past
+		_, err = eventService.AddEvent(&event)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidPublishAt)
+	})
+
+	t.Run("PublishDueEvents_PublishesSeriesAndOccurrences", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		member := test_utils.CreateMember(association.ID, enums.Accepted)
+		now := time.Now()
+
+		series := test_utils.GetValidEvent(association.ID)
+		series.RecurrenceRule = "FREQ=WEEKLY;COUNT=4"
+		series.PublicationStatus = enums.DraftPublication
+		_, err := eventService.AddEvent(&series)
+		assert.NoError(t, err)
+		occurrence, err := eventService.GetOrCreateOccurrence(series.ID, series.Date.AddDate(0, 0, 7))
+		assert.NoError(t, err)
+		assert.Equal(t, enums.DraftPublication, occurrence.PublicationStatus)
+
+		assert.ErrorIs(t, eventService.PublishEvent(occurrence, now), coreErrors.ErrInvalidOccurrence)
+		assert.NoError(t, eventService.SchedulePublication(&series, now.Add(time.Hour), now))
+
+		count, err := eventService.PublishDueEvents(now.Add(30 * time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+		assert.False(t, canView(t, member, occurrence))
+
+		count, err = eventService.PublishDueEvents(now.Add(2 * time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		stored, err := eventService.GetEventById(occurrence.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, enums.PublishedPublication, stored.PublicationStatus)
+		assert.Nil(t, stored.PublishAt)
+		assert.True(t, canView(t, member, stored))
+	})
+}
@@ -0,0 +1,150 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"backend/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventRecurrence(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	service := services.NewEventService()
+
+	createWeeklySeries := func(t *testing.T, rule string) *models.Event {
+		_, association := test_utils.CreateUserAndAssociation()
+
+		event := test_utils.GetValidEvent(association.ID)
+		event.Date = time.Now().Add(24 * time.Hour).Truncate(time.Second)
+		event.RecurrenceRule = rule
+
+		createdEvent, err := service.AddEvent(&event)
+		assert.NoError(t, err)
+
+		series, err := service.GetEventById(createdEvent.ID)
+		assert.NoError(t, err)
+		return series
+	}
+
+	expand := func(t *testing.T, series *models.Event) []models.Event {
+		occurrences, err := service.ExpandOccurrences([]models.Event{*series}, series.Date, series.Date.Add(services.RecurrenceHorizon))
+		assert.NoError(t, err)
+		return occurrences
+	}
+
+	t.Run("AddEvent_InvalidRule", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+
+		event := test_utils.GetValidEvent(association.ID)
+		event.RecurrenceRule = "FREQ=HOURLY;COUNT=3"
+
+		_, err := service.AddEvent(&event)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidRecurrenceRule)
+	})
+
+	t.Run("ExpandOccurrences_Count", func(t *testing.T) {
+		series := createWeeklySeries(t, "RRULE:FREQ=WEEKLY;COUNT=4")
+		assert.Equal(t, "FREQ=WEEKLY;COUNT=4", series.RecurrenceRule)
+
+		occurrences := expand(t, series)
+		assert.Len(t, occurrences, 4)
+		assert.Equal(t, series.ID, *occurrences[3].RecurrenceParentID)
+		assert.Equal(t, series.Date.AddDate(0, 0, 21).Unix(), occurrences[3].Date.Unix())
+	})
+
+	t.Run("DeleteOccurrence_AddsException", func(t *testing.T) {
+		series := createWeeklySeries(t, "FREQ=WEEKLY;COUNT=4")
+		second := series.Date.AddDate(0, 0, 7)
+
+		err := service.DeleteOccurrence(series, second)
+		assert.NoError(t, err)
+
+		series, err = service.GetEventById(series.ID)
+		assert.NoError(t, err)
+		assert.Len(t, expand(t, series), 3)
+
+		err = service.DeleteOccurrence(series, second)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidOccurrence)
+	})
+
+	t.Run("GetOrCreateOccurrence_PerOccurrenceParticipation", func(t *testing.T) {
+		series := createWeeklySeries(t, "FREQ=WEEKLY;COUNT=4")
+		user := test_utils.GetAuthenticatedUser()
+		err := database.CurrentDatabase.Create(user).Error
+		assert.NoError(t, err)
+
+		occurrence, err := service.GetOrCreateOccurrence(series.ID, series.Date.AddDate(0, 0, 14))
+		assert.NoError(t, err)
+		assert.NotEqual(t, series.ID, occurrence.ID)
+
+		again, err := service.GetOrCreateOccurrence(series.ID, series.Date.AddDate(0, 0, 14))
+		assert.NoError(t, err)
+		assert.Equal(t, occurrence.ID, again.ID)
+
+		_, err = service.ChangeUserEventAttend(true, occurrence.ID, user.ID)
+		assert.NoError(t, err)
+		assert.True(t, service.IsUserAttendingEvent(occurrence.ID, user.ID))
+		assert.False(t, service.IsUserAttendingEvent(series.ID, user.ID))
+
+		_, err = service.GetOrCreateOccurrence(series.ID, series.Date.Add(time.Hour))
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidOccurrence)
+	})
+
+	t.Run("GetUserEvents_OnlyParticipatedOccurrences", func(t *testing.T) {
+		series := createWeeklySeries(t, "FREQ=WEEKLY;COUNT=4")
+		user := test_utils.CreateUser()
+
+		occurrence, err := service.GetOrCreateOccurrence(series.ID, series.Date.AddDate(0, 0, 7))
+		assert.NoError(t, err)
+		test_utils.CreateParticipation(user.ID, occurrence.ID, enums.ParticipationConfirmed)
+		test_utils.CreateParticipation(user.ID, series.ID, enums.ParticipationConfirmed)
+
+		result, err := services.NewUserService().GetUserEvents(user.ID, utils.Pagination{})
+		assert.NoError(t, err)
+
+		events := result.Rows.([]models.Event)
+		assert.Len(t, events, 1)
+		assert.Equal(t, occurrence.ID, events[0].ID)
+	})
+
+	t.Run("UpdateFollowingOccurrences_SplitsSeries", func(t *testing.T) {
+		series := createWeeklySeries(t, "FREQ=WEEKLY;COUNT=4")
+		third := series.Date.AddDate(0, 0, 14)
+
+		changes := *series
+		changes.Date = third.Add(time.Hour)
+		changes.Name = "Nouvel horaire"
+
+		newSeries, err := service.UpdateFollowingOccurrences(series, third, &changes)
+		assert.NoError(t, err)
+		assert.Equal(t, "FREQ=WEEKLY;COUNT=2", newSeries.RecurrenceRule)
+
+		series, err = service.GetEventById(series.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "FREQ=WEEKLY;COUNT=2", series.RecurrenceRule)
+		assert.Len(t, expand(t, series), 2)
+	})
+
+	t.Run("UpdateFollowingOccurrences_ChangesRule", func(t *testing.T) {
+		series := createWeeklySeries(t, "FREQ=WEEKLY;COUNT=4")
+		third := series.Date.AddDate(0, 0, 14)
+
+		changes := *series
+		changes.Date = third
+		changes.RecurrenceRule = "FREQ=DAILY;COUNT=3"
+
+		newSeries, err := service.UpdateFollowingOccurrences(series, third, &changes)
+		assert.NoError(t, err)
+		assert.Equal(t, "FREQ=DAILY;COUNT=3", newSeries.RecurrenceRule)
+		assert.Len(t, expand(t, newSeries), 3)
+	})
+}
@@ -0,0 +1,141 @@
+package services_test
+
+import (
+	"backend/database"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventSchedule(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	service := services.NewEventService()
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+
+	createEvent := func(t *testing.T, associationID string, location string, from time.Time, duration time.Duration) *models.Event {
+		event := test_utils.GetValidEvent(associationID)
+		event.Location = location
+		event.Date = from
+		end := from.Add(duration)
+		event.EndDate = &end
+		_, err := service.AddEvent(&event)
+		assert.NoError(t, err)
+		return &event
+	}
+
+	t.Run("EndsAt_DefaultsToDefaultDuration", func(t *testing.T) {
+		event := models.Event{Date: start}
+		assert.Equal(t, start.Add(models.DefaultEventDuration), event.EndsAt())
+
+		end := start.Add(26 * time.Hour)
+		event.EndDate = &end
+		assert.Equal(t, 26*time.Hour, event.Duration())
+	})
+
+	t.Run("AddEvent_EndBeforeStart", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := test_utils.GetValidEvent(association.ID)
+		end := event.Date.Add(-time.Hour)
+		event.EndDate = &end
+
+		_, err := service.AddEvent(&event)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidEventSchedule)
+	})
+
+	t.Run("AddEvent_InvalidTimeZone", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := test_utils.GetValidEvent(association.ID)
+		event.TimeZone = "Europe/Nowhere"
+
+		_, err := service.AddEvent(&event)
+		assert.Error(t, err)
+	})
+
+	t.Run("Recurrence_FollowsEventTimeZone", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		newYork, err := time.LoadLocation("America/New_York")
+		assert.NoError(t, err)
+
+		// 19h à New York, avant le passage à l'heure d'hiver du 2 novembre 2025
+		series := test_utils.GetValidEvent(association.ID)
+		series.Date = time.Date(2025, 10, 20, 19, 0, 0, 0, newYork).UTC()
+		series.TimeZone = "America/New_York"
+		series.RecurrenceRule = "FREQ=WEEKLY;COUNT=4"
+		_, err = service.AddEvent(&series)
+		assert.NoError(t, err)
+
+		occurrences, err := service.ExpandOccurrences([]models.Event{series}, series.Date.Add(-time.Minute), series.Date.AddDate(0, 1, 0))
+		assert.NoError(t, err)
+		assert.Len(t, occurrences, 4)
+		for _, occurrence := range occurrences {
+			assert.Equal(t, 19, occurrence.Date.In(newYork).Hour())
+		}
+	})
+
+	t.Run("FindAttendanceConflicts", func(t *testing.T) {
+		user, association := test_utils.CreateUserAndAssociation()
+		attended := createEvent(t, association.ID, "Salle A", start, 3*time.Hour)
+		_, err := service.ChangeUserEventAttend(true, attended.ID, user.ID)
+		assert.NoError(t, err)
+
+		overlapping := createEvent(t, association.ID, "Salle B", start.Add(2*time.Hour), time.Hour)
+		participation, err := service.ChangeUserEventAttend(true, overlapping.ID, user.ID)
+		assert.NoError(t, err)
+		assert.Len(t, participation.ScheduleConflicts, 1)
+		assert.Equal(t, attended.ID, participation.ScheduleConflicts[0].ID)
+
+		later := createEvent(t, association.ID, "Salle B", start.Add(3*time.Hour), time.Hour)
+		conflicts, err := service.FindAttendanceConflicts(user.ID, later)
+		assert.NoError(t, err)
+		// Des créneaux qui se suivent ne se chevauchent pas
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("FindLocationConflicts", func(t *testing.T) {
+		assert.NoError(t, test_utils.SetupTestDB())
+		_, association := test_utils.CreateUserAndAssociation()
+
+		booked := createEvent(t, association.ID, "Gymnase", start, 2*time.Hour)
+		createEvent(t, association.ID, "Stade", start, 2*time.Hour)
+
+		event := createEvent(t, association.ID, " gymnase ", start.Add(time.Hour), 2*time.Hour)
+		conflicts, err := service.FindLocationConflicts(event)
+		assert.NoError(t, err)
+		assert.Len(t, conflicts, 1)
+		assert.Equal(t, booked.ID, conflicts[0].ID)
+
+		assert.NoError(t, service.CancelEvent(booked, ""))
+		conflicts, err = service.FindLocationConflicts(event)
+		assert.NoError(t, err)
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("FindLocationConflicts_RecurringSeries", func(t *testing.T) {
+		assert.NoError(t, test_utils.SetupTestDB())
+		_, association := test_utils.CreateUserAndAssociation()
+
+		series := test_utils.GetValidEvent(association.ID)
+		series.Location = "Piscine"
+		series.Date = start
+		series.RecurrenceRule = "FREQ=WEEKLY;COUNT=4"
+		_, err := service.AddEvent(&series)
+		assert.NoError(t, err)
+
+		event := createEvent(t, association.ID, "Piscine", start.AddDate(0, 0, 14).Add(30*time.Minute), time.Hour)
+		conflicts, err := service.FindLocationConflicts(event)
+		assert.NoError(t, err)
+		assert.Len(t, conflicts, 1)
+		assert.True(t, start.AddDate(0, 0, 14).Equal(conflicts[0].Date))
+
+		var stored models.Event
+		assert.NoError(t, database.CurrentDatabase.First(&stored, "id = ?", event.ID).Error)
+		assert.NotNil(t, stored.EndDate)
+	})
+}
@@ -0,0 +1,120 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"backend/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventSearch(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	eventService := services.NewEventService()
+	admin := test_utils.GetAdminUser()
+
+	createEvent := func(associationID string, name string, date time.Time) *models.Event {
+		return test_utils.CreateEvent(associationID, func(event *models.Event) {
+			event.Name = name
+			event.Date = date
+			event.Visibility = enums.PublicVisibility
+		})
+	}
+
+	search := func(t *testing.T, viewer *models.User, filters services.EventSearch) []string {
+		result, err := eventService.SearchEvents(viewer, filters, utils.Pagination{Limit: 50})
+		assert.NoError(t, err)
+		var names []string
+		for _, event := range result.Rows.([]models.Event) {
+			names = append(names, event.Name)
+		}
+		return names
+	}
+
+	register := func(eventID string) *models.User {
+		user := test_utils.CreateUser()
+		test_utils.CreateParticipation(user.ID, eventID, enums.ParticipationPending)
+		return user
+	}
+
+	t.Run("FiltersByCategoryAssociationAndDates", func(t *testing.T) {
+		assert.NoError(t, test_utils.SetupTestDB())
+		_, association := test_utils.CreateUserAndAssociation()
+		_, other := test_utils.CreateUserAndAssociation()
+		category := models.Category{Name: "Sport"}
+		assert.NoError(t, database.CurrentDatabase.Create(&category).Error)
+
+		tomorrow := time.Now().Add(24 * time.Hour)
+		match := createEvent(association.ID, "Tournoi", tomorrow)
+		assert.NoError(t, database.CurrentDatabase.Model(match).Update("category_id", category.ID).Error)
+		createEvent(association.ID, "Réunion", tomorrow)
+		createEvent(other.ID, "Concert", tomorrow.AddDate(0, 0, 10))
+		createEvent(association.ID, "Passé", time.Now().AddDate(0, 0, -10))
+
+		assert.ElementsMatch(t, []string{"Tournoi", "Réunion", "Concert"}, search(t, nil, services.EventSearch{}))
+		assert.Equal(t, []string{"Tournoi"}, search(t, nil, services.EventSearch{CategoryID: category.ID}))
+		assert.ElementsMatch(t, []string{"Tournoi", "Réunion"}, search(t, nil, services.EventSearch{AssociationID: association.ID}))
+
+		to := tomorrow.AddDate(0, 0, 1)
+		assert.ElementsMatch(t, []string{"Tournoi", "Réunion"}, search(t, nil, services.EventSearch{To: &to}))
+		from := time.Now().AddDate(0, 0, -20)
+		assert.Contains(t, search(t, nil, services.EventSearch{From: &from}), "Passé")
+	})
+
+	t.Run("FiltersByLocationAndFreeSeats", func(t *testing.T) {
+		assert.NoError(t, test_utils.SetupTestDB())
+		_, association := test_utils.CreateUserAndAssociation()
+		tomorrow := time.Now().Add(24 * time.Hour)
+
+		full := createEvent(association.ID, "Complet", tomorrow)
+		capacity := 1
+		assert.NoError(t, database.CurrentDatabase.Model(full).Update("capacity", capacity).Error)
+		register(full.ID)
+
+		open := createEvent(association.ID, "Ouvert", tomorrow)
+		assert.NoError(t, database.CurrentDatabase.Model(open).Update("location", "Salle des fêtes de Lyon").Error)
+
+		assert.Equal(t, []string{"Ouvert"}, search(t, nil, services.EventSearch{HasFreeSeats: true}))
+		assert.Equal(t, []string{"Ouvert"}, search(t, nil, services.EventSearch{Location: "lyon"}))
+	})
+
+	t.Run("AttendingAndPopularity", func(t *testing.T) {
+		assert.NoError(t, test_utils.SetupTestDB())
+		_, association := test_utils.CreateUserAndAssociation()
+
+		first := createEvent(association.ID, "Premier", time.Now().Add(24*time.Hour))
+		second := createEvent(association.ID, "Second", time.Now().Add(48*time.Hour))
+		attendee := register(second.ID)
+		register(second.ID)
+		register(first.ID)
+
+		assert.Equal(t, []string{"Second"}, search(t, attendee, services.EventSearch{Attending: true}))
+		assert.Equal(t, []string{"Premier", "Second"}, search(t, admin, services.EventSearch{Sort: services.EventSortDate}))
+		assert.Equal(t, []string{"Second", "Premier"}, search(t, admin, services.EventSearch{Sort: services.EventSortDateDesc}))
+		assert.Equal(t, []string{"Second", "Premier"}, search(t, admin, services.EventSearch{Sort: services.EventSortPopularity}))
+	})
+
+	t.Run("ExpandsRecurringSeries", func(t *testing.T) {
+		assert.NoError(t, test_utils.SetupTestDB())
+		_, association := test_utils.CreateUserAndAssociation()
+
+		series := test_utils.GetValidEvent(association.ID)
+		series.Name = "Hebdo"
+		series.Visibility = enums.PublicVisibility
+		series.RecurrenceRule = "FREQ=WEEKLY;COUNT=3"
+		_, err := eventService.AddEvent(&series)
+		assert.NoError(t, err)
+
+		assert.Len(t, search(t, nil, services.EventSearch{}), 3)
+
+		to := series.Date.AddDate(0, 0, 8)
+		assert.Len(t, search(t, nil, services.EventSearch{To: &to}), 2)
+	})
+}
@@ -128,7 +128,7 @@ func TestEventService(t *testing.T) {
 		}
 		search := "Test Event"
 
-		result, err := service.GetEvents(pagination, &search)
+		result, err := service.GetEvents(test_utils.GetAdminUser(), pagination, &search)
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, 3, len(result.Rows.([]models.Event)))
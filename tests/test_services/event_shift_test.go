@@ -0,0 +1,116 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventShift(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	shiftService := services.NewEventShiftService()
+
+	createEvent := func() *models.Event {
+		_, association := test_utils.CreateUserAndAssociation()
+		return test_utils.CreateEvent(association.ID, nil)
+	}
+
+	createShift := func(t *testing.T, event *models.Event, startsAt time.Time, hours int, capacity int) *models.EventShift {
+		shift := &models.EventShift{
+			Name:     "Bar",
+			StartsAt: startsAt,
+			EndsAt:   startsAt.Add(time.Duration(hours) * time.Hour),
+			Capacity: capacity,
+		}
+		assert.NoError(t, shiftService.CreateShift(event, shift))
+		return shift
+	}
+
+	t.Run("CreateShift_EndsBeforeStart", func(t *testing.T) {
+		event := createEvent()
+		shift := &models.EventShift{Name: "Installation", StartsAt: event.Date, EndsAt: event.Date.Add(-time.Hour), Capacity: 2}
+		assert.Error(t, shiftService.CreateShift(event, shift))
+	})
+
+	t.Run("SignUp_CapacityAndRole", func(t *testing.T) {
+		event := createEvent()
+		shift := createShift(t, event, event.Date, 2, 1)
+		first, second := test_utils.CreateUser(), test_utils.CreateUser()
+
+		_, err := shiftService.SignUp(shift, first)
+		assert.NoError(t, err)
+		_, err = shiftService.SignUp(shift, first)
+		assert.ErrorIs(t, err, coreErrors.ErrAlreadySignedUp)
+		_, err = shiftService.SignUp(shift, second)
+		assert.ErrorIs(t, err, coreErrors.ErrShiftFull)
+
+		restricted := createShift(t, event, event.Date.Add(4*time.Hour), 2, 5)
+		assert.NoError(t, database.CurrentDatabase.Model(restricted).Update("required_role", enums.AssociationLeaderRole).Error)
+		restricted.RequiredRole = enums.AssociationLeaderRole
+		_, err = shiftService.SignUp(restricted, second)
+		assert.ErrorIs(t, err, coreErrors.ErrShiftRoleRequired)
+
+		shifts, err := shiftService.GetShifts(event)
+		assert.NoError(t, err)
+		assert.Len(t, shifts, 2)
+		assert.Equal(t, int64(1), shifts[0].SignedUp)
+	})
+
+	t.Run("SignUp_PreventsOverlapsAcrossEvents", func(t *testing.T) {
+		event := createEvent()
+		other := createEvent()
+		setup := createShift(t, event, event.Date.Add(-2*time.Hour), 2, 5)
+		bar := createShift(t, event, event.Date, 2, 5)
+		overlapping := createShift(t, other, event.Date.Add(time.Hour), 2, 5)
+		volunteer := test_utils.CreateUser()
+
+		_, err := shiftService.SignUp(setup, volunteer)
+		assert.NoError(t, err)
+		// Des créneaux qui se suivent ne se chevauchent pas
+		_, err = shiftService.SignUp(bar, volunteer)
+		assert.NoError(t, err)
+		_, err = shiftService.SignUp(overlapping, volunteer)
+		assert.ErrorIs(t, err, coreErrors.ErrShiftOverlap)
+
+		bar.StartsAt = bar.StartsAt.Add(-time.Hour)
+		assert.ErrorIs(t, shiftService.UpdateShift(bar, bar), coreErrors.ErrShiftOverlap)
+	})
+
+	t.Run("CompleteSignup_AwardsPointsOnce", func(t *testing.T) {
+		category := models.Category{Name: "Solidarité"}
+		assert.NoError(t, database.CurrentDatabase.Create(&category).Error)
+		event := createEvent()
+		assert.NoError(t, database.CurrentDatabase.Model(event).Update("category_id", category.ID).Error)
+		shift := createShift(t, event, event.Date, 2, 5)
+		volunteer := test_utils.CreateUser()
+
+		_, err := shiftService.SignUp(shift, volunteer)
+		assert.NoError(t, err)
+
+		signup, err := shiftService.CompleteSignup(event, shift, volunteer.ID, nil, time.Now())
+		assert.NoError(t, err)
+		assert.NotNil(t, signup.CompletedAt)
+
+		_, err = shiftService.CompleteSignup(event, shift, volunteer.ID, nil, time.Now())
+		assert.ErrorIs(t, err, coreErrors.ErrShiftAlreadyCompleted)
+		assert.ErrorIs(t, shiftService.Withdraw(shift, volunteer.ID), coreErrors.ErrShiftAlreadyCompleted)
+
+		var stored models.User
+		assert.NoError(t, database.CurrentDatabase.First(&stored, "id = ?", volunteer.ID).Error)
+		assert.Equal(t, volunteer.PointsOpen+category.Note, stored.PointsOpen)
+
+		roster, err := shiftService.GetRoster(event)
+		assert.NoError(t, err)
+		assert.Len(t, roster[0].Signups, 1)
+		assert.Equal(t, volunteer.ID, roster[0].Signups[0].User.ID)
+	})
+}
@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventStatus(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	service := services.NewEventService()
+
+	createEvent := func(t *testing.T) (*models.User, *models.Event) {
+		user, association := test_utils.CreateUserAndAssociation()
+		event := test_utils.GetValidEvent(association.ID)
+		_, err := service.AddEvent(&event)
+		assert.NoError(t, err)
+		return user, &event
+	}
+
+	t.Run("NewEvent_IsScheduled", func(t *testing.T) {
+		_, event := createEvent(t)
+		assert.Equal(t, enums.EventScheduled, event.Status)
+	})
+
+	t.Run("CancelEvent_KeepsEventAndParticipations", func(t *testing.T) {
+		user, event := createEvent(t)
+		_, err := service.ChangeUserEventAttend(true, event.ID, user.ID)
+		assert.NoError(t, err)
+
+		assert.NoError(t, service.CancelEvent(event, "Salle indisponible"))
+
+		stored, err := service.GetEventById(event.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, enums.EventCancelled, stored.Status)
+		assert.Equal(t, "Salle indisponible", stored.StatusReason)
+		assert.True(t, service.IsUserAttendingEvent(event.ID, user.ID))
+	})
+
+	t.Run("CancelledEvent_RefusesRegistrations", func(t *testing.T) {
+		_, event := createEvent(t)
+		assert.NoError(t, service.CancelEvent(event, ""))
+
+		other := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(other).Error)
+
+		_, err := service.ChangeUserEventAttend(true, event.ID, other.ID)
+		assert.ErrorIs(t, err, coreErrors.ErrEventCancelled)
+	})
+
+	t.Run("RescheduleEvent_MovesDate", func(t *testing.T) {
+		_, event := createEvent(t)
+		assert.NoError(t, service.CancelEvent(event, ""))
+
+		newDate := event.Date.Add(7 * 24 * time.Hour).Truncate(time.Second)
+		assert.NoError(t, service.RescheduleEvent(event, &newDate, "Reprise"))
+
+		stored, err := service.GetEventById(event.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, enums.EventScheduled, stored.Status)
+		assert.True(t, newDate.Equal(stored.Date))
+	})
+
+	t.Run("RescheduleEvent_WithoutDatePostpones", func(t *testing.T) {
+		_, event := createEvent(t)
+		date := event.Date
+
+		assert.NoError(t, service.RescheduleEvent(event, nil, "Météo"))
+
+		stored, err := service.GetEventById(event.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, enums.EventPostponed, stored.Status)
+		assert.True(t, date.Truncate(time.Second).Equal(stored.Date.Truncate(time.Second)))
+	})
+
+	t.Run("CancelSeries_CancelsUpcomingOccurrences", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		series := test_utils.GetValidEvent(association.ID)
+		series.RecurrenceRule = "FREQ=WEEKLY;COUNT=4"
+		_, err := service.AddEvent(&series)
+		assert.NoError(t, err)
+
+		occurrence, err := service.GetOrCreateOccurrence(series.ID, series.Date.AddDate(0, 0, 7))
+		assert.NoError(t, err)
+
+		assert.NoError(t, service.CancelEvent(&series, ""))
+
+		stored, err := service.GetEventById(occurrence.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, enums.EventCancelled, stored.Status)
+
+		newDate := series.Date.Add(time.Hour)
+		assert.Error(t, service.RescheduleEvent(&series, &newDate, ""))
+	})
+}
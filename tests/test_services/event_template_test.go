@@ -0,0 +1,117 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventTemplate(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	templateService := services.NewEventTemplateService()
+	formService := services.NewEventFormService()
+	shiftService := services.NewEventShiftService()
+	eventService := services.NewEventService()
+
+	// createSourceEvent crée un événement de deux heures avec une question et un créneau d'installation
+	createSourceEvent := func(t *testing.T) *models.Event {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := test_utils.GetValidEvent(association.ID)
+		event.Name = "Soirée jeux"
+		capacity := 30
+		event.Capacity = &capacity
+		endDate := event.Date.Add(2 * time.Hour)
+		event.EndDate = &endDate
+		assert.NoError(t, database.CurrentDatabase.Create(&event).Error)
+
+		_, err := formService.SetQuestions(&event, []models.EventQuestion{
+			{Label: "Jeu préféré", Type: enums.TextQuestion, Required: true},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, shiftService.CreateShift(&event, &models.EventShift{
+			Name:     "Installation",
+			StartsAt: event.Date.Add(-time.Hour),
+			EndsAt:   event.Date,
+			Capacity: 3,
+		}))
+		return &event
+	}
+
+	assertCopied := func(t *testing.T, created *models.Event, date time.Time) {
+		assert.Equal(t, enums.DraftPublication, created.PublicationStatus)
+		assert.True(t, created.Date.Equal(date))
+		assert.NotNil(t, created.EndDate)
+		assert.Equal(t, 2*time.Hour, created.EndDate.Sub(created.Date))
+		assert.Equal(t, 30, *created.Capacity)
+
+		var questions []models.EventQuestion
+		assert.NoError(t, database.CurrentDatabase.Where("event_id = ?", created.ID).Find(&questions).Error)
+		assert.Len(t, questions, 1)
+		assert.Equal(t, "Jeu préféré", questions[0].Label)
+		assert.True(t, questions[0].Required)
+
+		shifts, err := shiftService.GetShifts(created)
+		assert.NoError(t, err)
+		assert.Len(t, shifts, 1)
+		assert.True(t, shifts[0].StartsAt.Equal(date.Add(-time.Hour)))
+		assert.True(t, shifts[0].EndsAt.Equal(date))
+	}
+
+	t.Run("SaveAsTemplate_RequiresName", func(t *testing.T) {
+		event := createSourceEvent(t)
+		_, err := templateService.SaveAsTemplate(event, "  ")
+		assert.Error(t, err)
+	})
+
+	t.Run("CreateEventFromTemplate", func(t *testing.T) {
+		event := createSourceEvent(t)
+		template, err := templateService.SaveAsTemplate(event, "Soirée jeux du semestre")
+		assert.NoError(t, err)
+
+		templates, err := templateService.GetTemplates(event.AssociationID)
+		assert.NoError(t, err)
+		assert.Len(t, templates, 1)
+		assert.Len(t, templates[0].Questions, 1)
+		assert.Len(t, templates[0].Shifts, 1)
+		assert.Equal(t, -60, templates[0].Shifts[0].Offset)
+
+		date := event.Date.AddDate(0, 6, 0)
+		created, err := templateService.CreateEventFromTemplate(&templates[0], date, "Soirée jeux de printemps")
+		assert.NoError(t, err)
+		assert.Equal(t, "Soirée jeux de printemps", created.Name)
+		assert.Equal(t, event.AssociationID, created.AssociationID)
+		assertCopied(t, created, date)
+
+		// Le modèle survit à l'événement d'origine
+		assert.NoError(t, eventService.DeleteEvent(event.ID))
+		_, err = templateService.GetTemplate(template.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("DuplicateEvent", func(t *testing.T) {
+		event := createSourceEvent(t)
+		attendee := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(attendee).Error)
+		participation := test_utils.GetValidParticipation(attendee.ID, event.ID)
+		assert.NoError(t, database.CurrentDatabase.Create(&participation).Error)
+
+		date := event.Date.AddDate(0, 0, 7)
+		duplicate, err := templateService.DuplicateEvent(event, date, "")
+		assert.NoError(t, err)
+		assert.NotEqual(t, event.ID, duplicate.ID)
+		assert.Equal(t, event.Name, duplicate.Name)
+		assertCopied(t, duplicate, date)
+
+		var participations int64
+		database.CurrentDatabase.Model(&models.Participation{}).Where("event_id = ?", duplicate.ID).Count(&participations)
+		assert.Zero(t, participations)
+	})
+}
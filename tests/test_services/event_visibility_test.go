@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"backend/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventVisibility(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	visibilityService := services.NewEventVisibilityService()
+	eventService := services.NewEventService()
+
+	createEvent := func(associationID string, visibility enums.Visibility) *models.Event {
+		return test_utils.CreateEvent(associationID, func(event *models.Event) {
+			event.Visibility = visibility
+		})
+	}
+
+	canView := func(t *testing.T, viewer *models.User, event *models.Event) bool {
+		visible, err := visibilityService.CanViewEvent(viewer, event)
+		assert.NoError(t, err)
+		return visible
+	}
+
+	t.Run("DefaultsToMembers", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := test_utils.GetValidEvent(association.ID)
+
+		_, err := eventService.AddEvent(&event)
+		assert.NoError(t, err)
+		assert.Equal(t, enums.MembersVisibility, event.Visibility)
+	})
+
+	t.Run("Public_VisibleWithoutToken", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := createEvent(association.ID, enums.PublicVisibility)
+
+		assert.True(t, canView(t, nil, event))
+	})
+
+	t.Run("Members_RestrictedToAcceptedMembers", func(t *testing.T) {
+		owner, association := test_utils.CreateUserAndAssociation()
+		event := createEvent(association.ID, enums.MembersVisibility)
+
+		accepted := test_utils.CreateMember(association.ID, enums.Accepted)
+		pending := test_utils.CreateMember(association.ID, enums.Pending)
+		outsider := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(outsider).Error)
+
+		assert.False(t, canView(t, nil, event))
+		assert.True(t, canView(t, accepted, event))
+		assert.False(t, canView(t, pending, event))
+		assert.False(t, canView(t, outsider, event))
+		assert.True(t, canView(t, owner, event))
+		assert.True(t, canView(t, test_utils.GetAdminUser(), event))
+	})
+
+	t.Run("InviteOnly_RestrictedToGuests", func(t *testing.T) {
+		owner, association := test_utils.CreateUserAndAssociation()
+		event := createEvent(association.ID, enums.InviteOnlyVisibility)
+
+		member := test_utils.CreateMember(association.ID, enums.Accepted)
+		guest := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(guest).Error)
+
+		assert.False(t, canView(t, guest, event))
+
+		invitations, err := visibilityService.InviteUsers(event, owner, []string{guest.ID, guest.ID, "unknown"})
+		assert.NoError(t, err)
+		assert.Len(t, invitations, 1)
+
+		assert.True(t, canView(t, guest, event))
+		assert.False(t, canView(t, member, event))
+		assert.True(t, canView(t, owner, event))
+
+		assert.NoError(t, visibilityService.RevokeInvitation(event, guest.ID))
+		assert.False(t, canView(t, guest, event))
+	})
+
+	t.Run("InviteOnly_InvitationCoversOccurrences", func(t *testing.T) {
+		owner, association := test_utils.CreateUserAndAssociation()
+		series := test_utils.GetValidEvent(association.ID)
+		series.Visibility = enums.InviteOnlyVisibility
+		series.RecurrenceRule = "FREQ=WEEKLY;COUNT=4"
+		_, err := eventService.AddEvent(&series)
+		assert.NoError(t, err)
+
+		guest := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(guest).Error)
+		_, err = visibilityService.InviteUsers(&series, owner, []string{guest.ID})
+		assert.NoError(t, err)
+
+		occurrence, err := eventService.GetOrCreateOccurrence(series.ID, series.Date.AddDate(0, 0, 7))
+		assert.NoError(t, err)
+		assert.Equal(t, enums.InviteOnlyVisibility, occurrence.Visibility)
+		assert.True(t, canView(t, guest, occurrence))
+	})
+
+	t.Run("GetEvents_FiltersHiddenEvents", func(t *testing.T) {
+		assert.NoError(t, test_utils.SetupTestDB())
+
+		_, association := test_utils.CreateUserAndAssociation()
+		public := createEvent(association.ID, enums.PublicVisibility)
+		createEvent(association.ID, enums.MembersVisibility)
+		createEvent(association.ID, enums.InviteOnlyVisibility)
+
+		result, err := eventService.GetEvents(nil, utils.Pagination{Page: 1, Limit: 10}, nil)
+		assert.NoError(t, err)
+		events := result.Rows.([]models.Event)
+		assert.Len(t, events, 1)
+		assert.Equal(t, public.ID, events[0].ID)
+
+		member := test_utils.CreateMember(association.ID, enums.Accepted)
+		result, err = eventService.GetEvents(member, utils.Pagination{Page: 1, Limit: 10}, nil)
+		assert.NoError(t, err)
+		assert.Len(t, result.Rows.([]models.Event), 2)
+
+		result, err = eventService.GetEvents(test_utils.GetAdminUser(), utils.Pagination{Page: 1, Limit: 10}, nil)
+		assert.NoError(t, err)
+		assert.Len(t, result.Rows.([]models.Event), 3)
+	})
+}
@@ -0,0 +1,145 @@
+package services_test
+
+import (
+	"backend/config"
+	"backend/database"
+	"backend/enums"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"backend/utils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderboardService(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	leaderboardService := services.NewLeaderboardService()
+
+	t.Run("PeriodStart", func(t *testing.T) {
+		location := utils.CalendarLocation()
+		date := func(year int, month time.Month, day int) time.Time {
+			return time.Date(year, month, day, 0, 0, 0, 0, location)
+		}
+		wednesday := time.Date(2026, time.October, 21, 15, 30, 0, 0, location)
+		sunday := time.Date(2026, time.October, 25, 23, 0, 0, 0, location)
+
+		assert.True(t, date(2026, time.October, 19).Equal(leaderboardService.PeriodStart(enums.WeekLeaderboard, wednesday)))
+		assert.True(t, date(2026, time.October, 19).Equal(leaderboardService.PeriodStart(enums.WeekLeaderboard, sunday)))
+		assert.True(t, date(2026, time.October, 1).Equal(leaderboardService.PeriodStart(enums.MonthLeaderboard, wednesday)))
+		assert.True(t, date(2026, time.September, 1).Equal(leaderboardService.PeriodStart(enums.SemesterLeaderboard, wednesday)))
+		assert.True(t, date(2026, time.September, 1).Equal(leaderboardService.PeriodStart(enums.SemesterLeaderboard, date(2027, time.January, 20))))
+		assert.True(t, date(2027, time.February, 1).Equal(leaderboardService.PeriodStart(enums.SemesterLeaderboard, date(2027, time.February, 3))))
+	})
+
+	t.Run("GetLeaderboard_InvalidScope", func(t *testing.T) {
+		_, err := leaderboardService.GetLeaderboard("year", services.LeaderboardScope{}, time.Now(), utils.Pagination{})
+		assert.Error(t, err)
+		_, err = leaderboardService.GetLeaderboard(enums.WeekLeaderboard, services.LeaderboardScope{AssociationID: "a", CategoryID: "c"}, time.Now(), utils.Pagination{})
+		assert.Error(t, err)
+	})
+
+	t.Run("AssociationLeaderboard", func(t *testing.T) {
+		if config.RedisClient == nil {
+			if err := config.InitRedis(); err != nil {
+				t.Skip("Redis indisponible : ", err)
+			}
+		}
+
+		checkInService := services.NewCheckInService()
+		userService := services.NewUserService()
+		_, association := test_utils.CreateUserAndAssociation()
+		category := models.Category{Name: "Solidarité"}
+		assert.NoError(t, database.CurrentDatabase.Create(&category).Error)
+		assert.NoError(t, database.CurrentDatabase.Model(&category).Update("note", 10).Error)
+
+		// Chaque participation confirmée rapporte 10 points dans le classement de l'association
+		attend := func(t *testing.T, user *models.User, times int) {
+			for i := 0; i < times; i++ {
+				event := test_utils.GetValidEvent(association.ID)
+				event.CategoryID = category.ID
+				assert.NoError(t, database.CurrentDatabase.Create(&event).Error)
+				participation := test_utils.GetValidParticipation(user.ID, event.ID)
+				assert.NoError(t, database.CurrentDatabase.Create(&participation).Error)
+				assert.NoError(t, database.CurrentDatabase.Preload("Event.Category").First(&participation, "id = ?", participation.ID).Error)
+				assert.NoError(t, checkInService.ConfirmParticipation(&participation, nil))
+			}
+		}
+
+		first := test_utils.GetAuthenticatedUser()
+		second := test_utils.GetAuthenticatedUser()
+		hidden := test_utils.GetAuthenticatedUser()
+		for _, user := range []*models.User{first, second, hidden} {
+			assert.NoError(t, database.CurrentDatabase.Create(user).Error)
+		}
+		attend(t, first, 2)
+		attend(t, second, 1)
+		attend(t, hidden, 3)
+		assert.NoError(t, userService.UpdatePrivacySettings(hidden.ID, services.PrivacySettings{HideFromLeaderboards: true}))
+
+		assertRanking := func(t *testing.T) {
+			for _, period := range enums.LeaderboardPeriods {
+				leaderboard, err := leaderboardService.GetLeaderboard(period, services.LeaderboardScope{AssociationID: association.ID}, time.Now(), utils.Pagination{})
+				assert.NoError(t, err)
+				entries := leaderboard.Rows.([]services.LeaderboardEntry)
+				if assert.Len(t, entries, 2) {
+					assert.Equal(t, first.ID, entries[0].UserID)
+					assert.Equal(t, 1, entries[0].Rank)
+					assert.Equal(t, int64(20), entries[0].Points)
+					assert.Equal(t, second.ID, entries[1].UserID)
+					assert.Equal(t, 2, entries[1].Rank)
+				}
+			}
+		}
+		assertRanking(t)
+
+		// La reconstruction depuis le registre donne le même classement
+		_, err := leaderboardService.Rebuild(time.Now())
+		assert.NoError(t, err)
+		assertRanking(t)
+	})
+
+	t.Run("Rebuild_ReplaysJournalledEntries", func(t *testing.T) {
+		if config.RedisClient == nil {
+			if err := config.InitRedis(); err != nil {
+				t.Skip("Redis indisponible : ", err)
+			}
+		}
+
+		ctx := context.Background()
+		_, association := test_utils.CreateUserAndAssociation()
+		scope := services.LeaderboardScope{AssociationID: association.ID}
+		counted := test_utils.CreateUser()
+		pending := test_utils.CreateUser()
+
+		entry, err := services.NewPointsService().Adjust(counted.ID, 10, "Bonus", nil)
+		assert.NoError(t, err)
+		assert.NoError(t, database.CurrentDatabase.Model(entry).Update("association_id", association.ID).Error)
+
+		// Pendant une reconstruction, les écritures sont consignées au lieu d'être reportées
+		assert.NoError(t, config.RedisClient.Set(ctx, "leaderboard-rebuild:lock", "other", time.Minute).Err())
+		_, err = leaderboardService.Rebuild(time.Now())
+		assert.Error(t, err)
+		leaderboardService.Record(&models.PointsEntry{ID: entry.ID, UserID: counted.ID, Amount: 10, AssociationID: &association.ID}, time.Now())
+		leaderboardService.Record(&models.PointsEntry{ID: "not-yet-committed", UserID: pending.ID, Amount: 7, AssociationID: &association.ID}, time.Now())
+		assert.NoError(t, config.RedisClient.Del(ctx, "leaderboard-rebuild:lock").Err())
+
+		// L'écriture déjà dans le registre n'est pas comptée deux fois ; l'autre est rejouée
+		_, err = leaderboardService.Rebuild(time.Now())
+		assert.NoError(t, err)
+		leaderboard, err := leaderboardService.GetLeaderboard(enums.AllTimeLeaderboard, scope, time.Now(), utils.Pagination{})
+		assert.NoError(t, err)
+		entries := leaderboard.Rows.([]services.LeaderboardEntry)
+		if assert.Len(t, entries, 2) {
+			assert.Equal(t, counted.ID, entries[0].UserID)
+			assert.Equal(t, int64(10), entries[0].Points)
+			assert.Equal(t, pending.ID, entries[1].UserID)
+			assert.Equal(t, int64(7), entries[1].Points)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParticipantExport(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	exportService := services.NewParticipantExportService()
+	formService := services.NewEventFormService()
+	eventService := services.NewEventService()
+
+	createEvent := func(associationID string, name string, date time.Time) *models.Event {
+		return test_utils.CreateEvent(associationID, func(event *models.Event) {
+			event.Name = name
+			event.Date = date
+		})
+	}
+
+	register := func(event *models.Event, status string) *models.User {
+		user := test_utils.CreateUser()
+		test_utils.CreateParticipation(user.ID, event.ID, status)
+		return user
+	}
+
+	t.Run("ParseExportOptions", func(t *testing.T) {
+		options, err := services.ParseExportOptions("", "", services.EventExportColumns)
+		assert.NoError(t, err)
+		assert.Equal(t, services.ExportFormatCSV, options.Format)
+		assert.Equal(t, services.EventExportColumns, options.Columns)
+
+		options, err = services.ParseExportOptions("XLSX", "name, email", services.EventExportColumns)
+		assert.NoError(t, err)
+		assert.Equal(t, services.ExportFormatXLSX, options.Format)
+		assert.Equal(t, []string{"name", "email"}, options.Columns)
+
+		_, err = services.ParseExportOptions("pdf", "", services.EventExportColumns)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidExportFormat)
+		_, err = services.ParseExportOptions("csv", "name,password", services.EventExportColumns)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidExportColumn)
+	})
+
+	t.Run("ExportEventParticipants_SelectedColumnsAndAnswers", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := createEvent(association.ID, "Gala", time.Now().Add(24*time.Hour))
+		questions, err := formService.SetQuestions(event, []models.EventQuestion{
+			{Label: "Régime alimentaire", Type: enums.TextQuestion},
+		})
+		assert.NoError(t, err)
+
+		user := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(user).Error)
+		_, err = eventService.ChangeUserEventAttendWithAnswers(true, event.ID, user.ID, []services.ParticipationAnswerInput{
+			{QuestionID: questions[0].ID, Value: "Végétarien"},
+		})
+		assert.NoError(t, err)
+		register(event, enums.ParticipationDeclined)
+
+		options, err := services.ParseExportOptions("csv", "email,checked_in_at,answers", nil)
+		assert.NoError(t, err)
+
+		var buffer bytes.Buffer
+		assert.NoError(t, exportService.ExportEventParticipants(event, options, &buffer))
+
+		records, err := csv.NewReader(&buffer).ReadAll()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"Email", "Pointé le", "Régime alimentaire"},
+			{user.Email, "", "Végétarien"},
+		}, records)
+	})
+
+	t.Run("ExportEventParticipants_EscapesFormulas", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		event := createEvent(association.ID, "Gala", time.Now().Add(24*time.Hour))
+		questions, err := formService.SetQuestions(event, []models.EventQuestion{
+			{Label: "Commentaire", Type: enums.TextQuestion},
+		})
+		assert.NoError(t, err)
+
+		user := test_utils.GetAuthenticatedUser()
+		user.Name = "=HYPERLINK(\"http://example.com\")"
+		assert.NoError(t, database.CurrentDatabase.Create(user).Error)
+		_, err = eventService.ChangeUserEventAttendWithAnswers(true, event.ID, user.ID, []services.ParticipationAnswerInput{
+			{QuestionID: questions[0].ID, Value: "@SUM(1+1)"},
+		})
+		assert.NoError(t, err)
+
+		options, err := services.ParseExportOptions("csv", "name,answers", nil)
+		assert.NoError(t, err)
+
+		var buffer bytes.Buffer
+		assert.NoError(t, exportService.ExportEventParticipants(event, options, &buffer))
+
+		records, err := csv.NewReader(&buffer).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, []string{"'=HYPERLINK(\"http://example.com\")", "'@SUM(1+1)"}, records[1])
+	})
+
+	t.Run("ExportAssociationParticipants_XLSXWithinRange", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		inRange := createEvent(association.ID, "Atelier", time.Now().Add(24*time.Hour))
+		outOfRange := createEvent(association.ID, "Assemblée", time.Now().AddDate(0, 2, 0))
+		attendee := register(inRange, enums.ParticipationConfirmed)
+		register(outOfRange, enums.ParticipationConfirmed)
+
+		options, err := services.ParseExportOptions("xlsx", "event,name,status", nil)
+		assert.NoError(t, err)
+
+		var buffer bytes.Buffer
+		err = exportService.ExportAssociationParticipants(association.ID, time.Now(), time.Now().AddDate(0, 1, 0), options, &buffer)
+		assert.NoError(t, err)
+
+		file, err := excelize.OpenReader(&buffer)
+		assert.NoError(t, err)
+		defer file.Close()
+		rows, err := file.GetRows("Participants")
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"Événement", "Nom", "Statut"},
+			{"Atelier", attendee.Name, enums.ParticipationConfirmed},
+		}, rows)
+	})
+}
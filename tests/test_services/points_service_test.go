@@ -0,0 +1,102 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"backend/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointsService(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	pointsService := services.NewPointsService()
+	checkInService := services.NewCheckInService()
+
+	t.Run("ConfirmParticipation_RecordsEntry", func(t *testing.T) {
+		leader, association := test_utils.CreateUserAndAssociation()
+		category := models.Category{Name: "Culture"}
+		assert.NoError(t, database.CurrentDatabase.Create(&category).Error)
+		event := test_utils.GetValidEvent(association.ID)
+		event.CategoryID = category.ID
+		assert.NoError(t, database.CurrentDatabase.Create(&event).Error)
+
+		attendee := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(attendee).Error)
+		participation := test_utils.GetValidParticipation(attendee.ID, event.ID)
+		assert.NoError(t, database.CurrentDatabase.Create(&participation).Error)
+		assert.NoError(t, database.CurrentDatabase.Preload("Event.Category").First(&participation, "id = ?", participation.ID).Error)
+
+		assert.NoError(t, checkInService.ConfirmParticipation(&participation, leader))
+		assert.ErrorIs(t, checkInService.ConfirmParticipation(&participation, leader), coreErrors.ErrParticipationAlreadyHandled)
+		assert.Equal(t, category.Note, test_utils.GetPointsBalance(attendee.ID))
+
+		history, err := pointsService.GetHistory(attendee.ID, utils.Pagination{})
+		assert.NoError(t, err)
+		entries := history.Rows.([]models.PointsEntry)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, enums.ParticipationPoints, entries[0].Source)
+		assert.Equal(t, participation.ID, *entries[0].Reference)
+		assert.Equal(t, leader.ID, *entries[0].ActorID)
+	})
+
+	t.Run("AdjustAndReverse", func(t *testing.T) {
+		admin := test_utils.GetAdminUser()
+		assert.NoError(t, database.CurrentDatabase.Create(admin).Error)
+		user := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(user).Error)
+
+		_, err := pointsService.Adjust(user.ID, 0, "Rien", admin)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidPointsAdjustment)
+		_, err = pointsService.Adjust(user.ID, 5, " ", admin)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidPointsAdjustment)
+
+		entry, err := pointsService.Adjust(user.ID, 15, "Aide à l'organisation du gala", admin)
+		assert.NoError(t, err)
+		assert.Equal(t, 15, test_utils.GetPointsBalance(user.ID))
+
+		reversal, err := pointsService.Reverse(entry.ID, "Saisie en double", admin)
+		assert.NoError(t, err)
+		assert.Equal(t, -15, reversal.Amount)
+		assert.Equal(t, entry.ID, *reversal.ReversalOfID)
+		assert.Zero(t, test_utils.GetPointsBalance(user.ID))
+
+		_, err = pointsService.Reverse(entry.ID, "", admin)
+		assert.ErrorIs(t, err, coreErrors.ErrPointsAlreadyReversed)
+		_, err = pointsService.Reverse(reversal.ID, "", admin)
+		assert.ErrorIs(t, err, coreErrors.ErrPointsAlreadyReversed)
+	})
+
+	t.Run("ReconcileBalances", func(t *testing.T) {
+		legacy := test_utils.GetAuthenticatedUser()
+		legacy.PointsOpen = 40
+		assert.NoError(t, database.CurrentDatabase.Create(legacy).Error)
+
+		drifted := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(drifted).Error)
+		_, err := pointsService.Adjust(drifted.ID, 10, "Bonus", nil)
+		assert.NoError(t, err)
+		assert.NoError(t, database.CurrentDatabase.Model(drifted).Update("points_open", 99).Error)
+
+		_, err = pointsService.ReconcileBalances()
+		assert.NoError(t, err)
+		assert.Equal(t, 40, test_utils.GetPointsBalance(legacy.ID))
+		assert.Equal(t, 10, test_utils.GetPointsBalance(drifted.ID))
+
+		// Le solde repris du compteur figure dans le registre, une seconde réconciliation ne change rien
+		var opening int64
+		database.CurrentDatabase.Model(&models.PointsEntry{}).Where("user_id = ? AND amount = ?", legacy.ID, 40).Count(&opening)
+		assert.Equal(t, int64(1), opening)
+
+		corrected, err := pointsService.ReconcileBalances()
+		assert.NoError(t, err)
+		assert.Zero(t, corrected)
+	})
+}
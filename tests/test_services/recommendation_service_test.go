@@ -0,0 +1,102 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"backend/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecommendationService(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	service := services.NewRecommendationService()
+
+	createEvent := func(associationID string, categoryID string, date time.Time) *models.Event {
+		return test_utils.CreateEvent(associationID, func(event *models.Event) {
+			event.CategoryID = categoryID
+			event.Date = date
+			event.Visibility = enums.PublicVisibility
+		})
+	}
+
+	recommend := func(t *testing.T, user *models.User) []services.Recommendation {
+		result, err := service.GetRecommendations(user, time.Now(), utils.Pagination{Limit: 50})
+		assert.NoError(t, err)
+		return result.Rows.([]services.Recommendation)
+	}
+
+	t.Run("RanksByPastCategoriesAndExplains", func(t *testing.T) {
+		assert.NoError(t, test_utils.SetupTestDB())
+		_, association := test_utils.CreateUserAndAssociation()
+		sport := models.Category{Name: "Sport"}
+		assert.NoError(t, database.CurrentDatabase.Create(&sport).Error)
+		music := models.Category{Name: "Musique"}
+		assert.NoError(t, database.CurrentDatabase.Create(&music).Error)
+
+		user := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(user).Error)
+		for i := 1; i <= 3; i++ {
+			past := createEvent(association.ID, sport.ID, time.Now().AddDate(0, 0, -7*i))
+			test_utils.CreateParticipation(user.ID, past.ID, enums.ParticipationConfirmed)
+		}
+
+		concert := createEvent(association.ID, music.ID, time.Now().Add(24*time.Hour))
+		match := createEvent(association.ID, sport.ID, time.Now().AddDate(0, 0, 20))
+
+		recommendations := recommend(t, user)
+		assert.Len(t, recommendations, 2)
+		assert.Equal(t, match.ID, recommendations[0].Event.ID)
+		assert.Contains(t, recommendations[0].Reasons, "Parce que vous avez participé à 3 événements « Sport »")
+		assert.Equal(t, concert.ID, recommendations[1].Event.ID)
+		assert.Greater(t, recommendations[0].Score, recommendations[1].Score)
+	})
+
+	t.Run("ExcludesJoinedAndFullEvents", func(t *testing.T) {
+		assert.NoError(t, test_utils.SetupTestDB())
+		_, association := test_utils.CreateUserAndAssociation()
+
+		user := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(user).Error)
+		other := test_utils.GetAuthenticatedUser()
+		assert.NoError(t, database.CurrentDatabase.Create(other).Error)
+
+		joined := createEvent(association.ID, "", time.Now().Add(24*time.Hour))
+		test_utils.CreateParticipation(user.ID, joined.ID, enums.ParticipationConfirmed)
+
+		full := createEvent(association.ID, "", time.Now().Add(48*time.Hour))
+		assert.NoError(t, database.CurrentDatabase.Model(full).Update("capacity", 1).Error)
+		test_utils.CreateParticipation(other.ID, full.ID, enums.ParticipationConfirmed)
+
+		open := createEvent(association.ID, "", time.Now().Add(72*time.Hour))
+
+		recommendations := recommend(t, user)
+		assert.Len(t, recommendations, 1)
+		assert.Equal(t, open.ID, recommendations[0].Event.ID)
+	})
+
+	t.Run("FavoursEventsJoinedByFellowMembers", func(t *testing.T) {
+		assert.NoError(t, test_utils.SetupTestDB())
+		_, association := test_utils.CreateUserAndAssociation()
+
+		user := test_utils.CreateMember(association.ID, enums.Accepted)
+		friend := test_utils.CreateMember(association.ID, enums.Accepted)
+
+		soon := createEvent(association.ID, "", time.Now().Add(24*time.Hour))
+		popular := createEvent(association.ID, "", time.Now().AddDate(0, 0, 10))
+		test_utils.CreateParticipation(friend.ID, popular.ID, enums.ParticipationConfirmed)
+
+		recommendations := recommend(t, user)
+		assert.Len(t, recommendations, 2)
+		assert.Equal(t, popular.ID, recommendations[0].Event.ID)
+		assert.Contains(t, recommendations[0].Reasons, "1 membre de vos associations participe")
+		assert.Equal(t, soon.ID, recommendations[1].Event.ID)
+	})
+}
@@ -0,0 +1,174 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewardService(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	rewardService := services.NewRewardService()
+	pointsService := services.NewPointsService()
+
+	// createUserWithPoints crée un utilisateur crédité de points par un ajustement manuel
+	createUserWithPoints := func(t *testing.T, points int) *models.User {
+		user := test_utils.CreateUser()
+		_, err := pointsService.Adjust(user.ID, points, "Crédit de test", nil)
+		assert.NoError(t, err)
+		return user
+	}
+
+	stock := func(t *testing.T, rewardID string) int {
+		reward, err := rewardService.GetReward(rewardID)
+		assert.NoError(t, err)
+		return *reward.Stock
+	}
+
+	newReward := func(t *testing.T, cost int, items int) *models.Reward {
+		_, association := test_utils.CreateUserAndAssociation()
+		reward := &models.Reward{Name: "Tote bag", Cost: cost, Stock: &items, AssociationID: &association.ID}
+		assert.NoError(t, rewardService.CreateReward(reward))
+		return reward
+	}
+
+	t.Run("CreateReward_Validation", func(t *testing.T) {
+		assert.Error(t, rewardService.CreateReward(&models.Reward{Name: "Gratuit", Cost: 0}))
+	})
+
+	t.Run("Redeem_DebitsPointsAndStock", func(t *testing.T) {
+		reward := newReward(t, 30, 1)
+		user := createUserWithPoints(t, 50)
+
+		redemption, err := rewardService.Redeem(reward.ID, user)
+		assert.NoError(t, err)
+		assert.Equal(t, enums.PendingRedemption, redemption.Status)
+		assert.Len(t, redemption.PickupCode, 8)
+		assert.Equal(t, 20, test_utils.GetPointsBalance(user.ID))
+		assert.Equal(t, 0, stock(t, reward.ID))
+
+		// Plus d'exemplaire disponible
+		other := createUserWithPoints(t, 50)
+		_, err = rewardService.Redeem(reward.ID, other)
+		assert.ErrorIs(t, err, coreErrors.ErrRewardOutOfStock)
+
+		// Le débit n'est pas annulable comme un mouvement ordinaire
+		var debit models.PointsEntry
+		assert.NoError(t, database.CurrentDatabase.First(&debit, "reference = ? AND source = ?", redemption.ID, enums.RedemptionPoints).Error)
+		assert.Equal(t, -30, debit.Amount)
+		_, err = pointsService.Reverse(debit.ID, "", nil)
+		assert.ErrorIs(t, err, coreErrors.ErrPointsNotReversible)
+	})
+
+	t.Run("UpdateReward_KeepsStockUnlessSent", func(t *testing.T) {
+		reward := newReward(t, 30, 5)
+		user := createUserWithPoints(t, 50)
+
+		// La demande est faite après la lecture de la récompense par le responsable
+		_, err := rewardService.Redeem(reward.ID, user)
+		assert.NoError(t, err)
+
+		name := "Gourde"
+		updated, err := rewardService.UpdateReward(reward.ID, services.RewardChanges{Name: &name})
+		assert.NoError(t, err)
+		assert.Equal(t, "Gourde", updated.Name)
+		assert.Equal(t, 4, stock(t, reward.ID))
+
+		items := 10
+		_, err = rewardService.UpdateReward(reward.ID, services.RewardChanges{Stock: &items})
+		assert.NoError(t, err)
+		assert.Equal(t, 10, stock(t, reward.ID))
+
+		updated, err = rewardService.UpdateReward(reward.ID, services.RewardChanges{UnlimitedStock: true})
+		assert.NoError(t, err)
+		assert.Nil(t, updated.Stock)
+
+		cost := 0
+		_, err = rewardService.UpdateReward(reward.ID, services.RewardChanges{Cost: &cost})
+		assert.Error(t, err)
+	})
+
+	t.Run("Redeem_InsufficientPoints", func(t *testing.T) {
+		reward := newReward(t, 30, 5)
+		user := createUserWithPoints(t, 10)
+
+		_, err := rewardService.Redeem(reward.ID, user)
+		assert.ErrorIs(t, err, coreErrors.ErrInsufficientPoints)
+		assert.Equal(t, 10, test_utils.GetPointsBalance(user.ID))
+		assert.Equal(t, 5, stock(t, reward.ID))
+	})
+
+	t.Run("Fulfil_ByPickupCode", func(t *testing.T) {
+		reward := newReward(t, 10, 5)
+		user := createUserWithPoints(t, 10)
+		redemption, err := rewardService.Redeem(reward.ID, user)
+		assert.NoError(t, err)
+
+		_, err = rewardService.GetRedemptionByCode("INCONNU1")
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidPickupCode)
+
+		found, err := rewardService.GetRedemptionByCode(redemption.PickupCode)
+		assert.NoError(t, err)
+		assert.NoError(t, rewardService.Fulfil(found, nil, time.Now()))
+		assert.Equal(t, enums.FulfilledRedemption, found.Status)
+
+		// Une demande remise ne peut plus être annulée
+		assert.ErrorIs(t, rewardService.Cancel(found, user, time.Now()), coreErrors.ErrRedemptionAlreadyHandled)
+		assert.Equal(t, 0, test_utils.GetPointsBalance(user.ID))
+	})
+
+	t.Run("RejectAndCancel_Refund", func(t *testing.T) {
+		reward := newReward(t, 20, 2)
+		user := createUserWithPoints(t, 40)
+
+		first, err := rewardService.Redeem(reward.ID, user)
+		assert.NoError(t, err)
+		second, err := rewardService.Redeem(reward.ID, user)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, test_utils.GetPointsBalance(user.ID))
+		assert.Equal(t, 0, stock(t, reward.ID))
+
+		first, err = rewardService.GetRedemption(first.ID)
+		assert.NoError(t, err)
+		assert.NoError(t, rewardService.Reject(first, "Rupture chez le fournisseur", nil, time.Now()))
+		assert.Equal(t, enums.RejectedRedemption, first.Status)
+		assert.Equal(t, 20, test_utils.GetPointsBalance(user.ID))
+		assert.Equal(t, 1, stock(t, reward.ID))
+
+		assert.NoError(t, rewardService.Cancel(second, user, time.Now()))
+		assert.Equal(t, 40, test_utils.GetPointsBalance(user.ID))
+		assert.Equal(t, 2, stock(t, reward.ID))
+
+		// Le remboursement n'a lieu qu'une fois
+		assert.ErrorIs(t, rewardService.Cancel(second, user, time.Now()), coreErrors.ErrRedemptionAlreadyHandled)
+		assert.Equal(t, 40, test_utils.GetPointsBalance(user.ID))
+	})
+
+	t.Run("DeleteReward_KeepsRedeemedRewards", func(t *testing.T) {
+		reward := newReward(t, 10, 5)
+		user := createUserWithPoints(t, 10)
+		_, err := rewardService.Redeem(reward.ID, user)
+		assert.NoError(t, err)
+
+		assert.NoError(t, rewardService.DeleteReward(reward))
+		withdrawn, err := rewardService.GetReward(reward.ID)
+		assert.NoError(t, err)
+		assert.False(t, withdrawn.IsActive)
+		_, err = rewardService.Redeem(reward.ID, user)
+		assert.ErrorIs(t, err, coreErrors.ErrRewardUnavailable)
+
+		unused := newReward(t, 10, 5)
+		assert.NoError(t, rewardService.DeleteReward(unused))
+		_, err = rewardService.GetReward(unused.ID)
+		assert.ErrorIs(t, err, coreErrors.ErrNotFound)
+	})
+}
@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"backend/database"
+	"backend/enums"
+	coreErrors "backend/errors"
+	"backend/models"
+	"backend/services"
+	"backend/tests/test_utils"
+	"backend/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVenueService(t *testing.T) {
+	err := test_utils.SetupTestDB()
+	assert.NoError(t, err)
+
+	venueService := services.NewVenueService()
+	eventService := services.NewEventService()
+
+	createVenue := func(t *testing.T, associationID string, name string, latitude, longitude float64) *models.Venue {
+		venue := models.Venue{Name: name, City: "Paris", Latitude: latitude, Longitude: longitude, AssociationID: associationID}
+		assert.NoError(t, venueService.CreateVenue(&venue))
+		return &venue
+	}
+
+	createEvent := func(t *testing.T, associationID string, venue *models.Venue) *models.Event {
+		event := test_utils.GetValidEvent(associationID)
+		event.Visibility = enums.PublicVisibility
+		event.VenueID = &venue.ID
+		_, err := eventService.AddEvent(&event)
+		assert.NoError(t, err)
+		return &event
+	}
+
+	t.Run("CreateVenue_InvalidCoordinates", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		venue := models.Venue{Name: "Nulle part", Latitude: 91, AssociationID: association.ID}
+		assert.Error(t, venueService.CreateVenue(&venue))
+	})
+
+	t.Run("AddEvent_TakesVenueName", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		venue := createVenue(t, association.ID, "Gymnase Japy", 48.8566, 2.3799)
+
+		event := createEvent(t, association.ID, venue)
+		assert.Equal(t, "Gymnase Japy", event.Location)
+
+		stored, err := eventService.GetEventById(event.ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, stored.Venue)
+		assert.Equal(t, venue.ID, stored.Venue.ID)
+	})
+
+	t.Run("AddEvent_VenueOfAnotherAssociation", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		_, other := test_utils.CreateUserAndAssociation()
+		venue := createVenue(t, other.ID, "Stade", 48.84, 2.25)
+
+		event := test_utils.GetValidEvent(association.ID)
+		event.VenueID = &venue.ID
+		_, err := eventService.AddEvent(&event)
+		assert.ErrorIs(t, err, coreErrors.ErrInvalidVenue)
+	})
+
+	t.Run("UpdateVenue_RenamesEventsLocation", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		venue := createVenue(t, association.ID, "Salle A", 48.85, 2.35)
+		event := createEvent(t, association.ID, venue)
+
+		venue.Name = "Salle B"
+		assert.NoError(t, venueService.UpdateVenue(venue))
+
+		stored, err := eventService.GetEventById(event.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Salle B", stored.Location)
+	})
+
+	t.Run("DeleteVenue_KeepsEvents", func(t *testing.T) {
+		_, association := test_utils.CreateUserAndAssociation()
+		venue := createVenue(t, association.ID, "Piscine", 48.85, 2.35)
+		event := createEvent(t, association.ID, venue)
+
+		assert.NoError(t, venueService.DeleteVenue(venue))
+
+		var stored models.Event
+		assert.NoError(t, database.CurrentDatabase.First(&stored, "id = ?", event.ID).Error)
+		assert.Nil(t, stored.VenueID)
+		assert.Equal(t, "Piscine", stored.Location)
+	})
+
+	t.Run("GetNearbyEvents_OrderedByDistance", func(t *testing.T) {
+		assert.NoError(t, test_utils.SetupTestDB())
+		_, association := test_utils.CreateUserAndAssociation()
+
+		// Depuis Notre-Dame de Paris : Bastille (~2 km), Versailles (~17 km), Lyon (~390 km)
+		bastille := createEvent(t, association.ID, createVenue(t, association.ID, "Bastille", 48.8532, 2.3692))
+		versailles := createEvent(t, association.ID, createVenue(t, association.ID, "Versailles", 48.8049, 2.1204))
+		createEvent(t, association.ID, createVenue(t, association.ID, "Lyon", 45.7640, 4.8357))
+
+		pagination, err := eventService.GetNearbyEvents(nil, 48.8530, 2.3499, 30, utils.Pagination{})
+		assert.NoError(t, err)
+
+		events := pagination.Rows.([]services.NearbyEvent)
+		assert.Len(t, events, 2)
+		assert.Equal(t, bastille.ID, events[0].ID)
+		assert.Equal(t, versailles.ID, events[1].ID)
+		assert.InDelta(t, 1.4, events[0].Distance, 0.5)
+		assert.InDelta(t, 17, events[1].Distance, 2)
+	})
+
+	t.Run("GetNearbyEvents_ExcludesPastCancelledAndHidden", func(t *testing.T) {
+		assert.NoError(t, test_utils.SetupTestDB())
+		_, association := test_utils.CreateUserAndAssociation()
+		venue := createVenue(t, association.ID, "Bastille", 48.8532, 2.3692)
+
+		past := test_utils.GetValidEvent(association.ID)
+		past.Visibility = enums.PublicVisibility
+		past.VenueID = &venue.ID
+		past.Date = time.Now().Add(-72 * time.Hour)
+		_, err := eventService.AddEvent(&past)
+		assert.NoError(t, err)
+
+		cancelled := createEvent(t, association.ID, venue)
+		assert.NoError(t, eventService.CancelEvent(cancelled, ""))
+
+		membersOnly := test_utils.GetValidEvent(association.ID)
+		membersOnly.Visibility = enums.MembersVisibility
+		membersOnly.VenueID = &venue.ID
+		_, err = eventService.AddEvent(&membersOnly)
+		assert.NoError(t, err)
+
+		pagination, err := eventService.GetNearbyEvents(nil, 48.8530, 2.3499, 10, utils.Pagination{})
+		assert.NoError(t, err)
+		assert.Empty(t, pagination.Rows)
+
+		pagination, err = eventService.GetNearbyEvents(test_utils.GetAdminUser(), 48.8530, 2.3499, 10, utils.Pagination{})
+		assert.NoError(t, err)
+		events := pagination.Rows.([]services.NearbyEvent)
+		assert.Len(t, events, 1)
+		assert.Equal(t, membersOnly.ID, events[0].ID)
+	})
+}
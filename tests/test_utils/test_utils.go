@@ -36,7 +36,7 @@ func SetupTestDB() error {
 }
 
 func CleanTestDB() error {
-	tables := []string{"participations", "events", "memberships", "associations", "users"}
+	tables := []string{"reward_redemptions", "rewards", "user_badges", "badges", "points_entries", "event_templates", "shift_signups", "event_shifts", "event_comments", "event_co_hosts", "event_photos", "event_feedbacks", "event_invitations", "participation_answers", "event_questions", "participations", "event_recurrence_exceptions", "events", "venues", "categories", "memberships", "associations", "users"}
 	for _, table := range tables {
 		if err := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE", table)).Error; err != nil {
 			return fmt.Errorf("échec suppression table %s: %v", table, err)
@@ -128,3 +128,58 @@ func GetValidParticipation(userID string, eventID string) models.Participation {
 		UpdatedAt:   time.Now(),
 	}
 }
+
+// CreateUser enregistre un utilisateur sans rôle particulier
+func CreateUser() *models.User {
+	user := GetAuthenticatedUser()
+	if err := db.Create(user).Error; err != nil {
+		panic(fmt.Sprintf("Échec création utilisateur: %v", err))
+	}
+	return user
+}
+
+// CreateMember enregistre un utilisateur membre de l'association, avec le statut d'adhésion donné
+func CreateMember(associationID string, status enums.Status) *models.User {
+	user := CreateUser()
+	membership := &models.Membership{
+		UserID:        user.ID,
+		AssociationID: associationID,
+		Status:        status,
+		JoinedAt:      time.Now(),
+	}
+	if err := db.Create(membership).Error; err != nil {
+		panic(fmt.Sprintf("Échec création membership: %v", err))
+	}
+	return user
+}
+
+// CreateEvent enregistre un événement de l'association, après que configure, s'il est renseigné, l'a modifié
+func CreateEvent(associationID string, configure func(event *models.Event)) *models.Event {
+	event := GetValidEvent(associationID)
+	if configure != nil {
+		configure(&event)
+	}
+	if err := db.Create(&event).Error; err != nil {
+		panic(fmt.Sprintf("Échec création événement: %v", err))
+	}
+	return &event
+}
+
+// CreateParticipation enregistre la participation de l'utilisateur à l'événement avec le statut donné
+func CreateParticipation(userID string, eventID string, status string) *models.Participation {
+	participation := GetValidParticipation(userID, eventID)
+	participation.Status = status
+	if err := db.Create(&participation).Error; err != nil {
+		panic(fmt.Sprintf("Échec création participation: %v", err))
+	}
+	return &participation
+}
+
+// GetPointsBalance renvoie le solde de points enregistré pour l'utilisateur
+func GetPointsBalance(userID string) int {
+	var user models.User
+	if err := db.Select("points_open").First(&user, "id = ?", userID).Error; err != nil {
+		panic(fmt.Sprintf("Échec lecture utilisateur: %v", err))
+	}
+	return user.PointsOpen
+}
@@ -14,3 +14,9 @@ func GenerateRandomDate(min, max time.Time) time.Time {
 func GenerateRandomNote() int {
 	return rand.Intn(5) + 1
 }
+
+// StartOfDay renvoie minuit du jour de la date donnée, dans son fuseau horaire
+func StartOfDay(date time.Time) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+}
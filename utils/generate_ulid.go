@@ -1,6 +1,8 @@
 package utils
 
 import (
+	cryptorand "crypto/rand"
+	"encoding/hex"
 	"math/rand"
 	"strings"
 	"time"
@@ -24,3 +26,27 @@ func GenerateAssociationCode() string {
 
 	return builder.String()
 }
+
+// GenerateSecureToken génère un jeton aléatoire non devinable, utilisable dans une URL
+func GenerateSecureToken() string {
+	bytes := make([]byte, 32)
+	if _, err := cryptorand.Read(bytes); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(bytes)
+}
+
+// pickupCodeCharset exclut les caractères qui se confondent à la lecture (0/O, 1/I/L)
+const pickupCodeCharset = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// GeneratePickupCode génère le code de retrait d'une récompense, à présenter aux responsables
+func GeneratePickupCode() string {
+	bytes := make([]byte, 8)
+	if _, err := cryptorand.Read(bytes); err != nil {
+		panic(err)
+	}
+	for i, b := range bytes {
+		bytes[i] = pickupCodeCharset[int(b)%len(pickupCodeCharset)]
+	}
+	return string(bytes)
+}
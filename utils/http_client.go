@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenHost est renvoyée lorsqu'une requête sortante vise une adresse interne
+var ErrForbiddenHost = errors.New("host resolves to a non-public address")
+
+// maxRedirects borne le nombre de redirections suivies par les clients HTTP sortants
+const maxRedirects = 5
+
+// NewPublicHTTPClient crée un client HTTP pour récupérer des URL fournies par les utilisateurs. Les adresses
+// de bouclage, privées et link-local sont refusées après résolution DNS, y compris derrière une redirection,
+// afin que le serveur ne puisse pas être utilisé pour atteindre son réseau interne.
+func NewPublicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenHost, host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// Pas de proxy : la connexion doit être établie par le dialer qui contrôle les adresses
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: timeout,
+		},
+		CheckRedirect: func(request *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return errors.New("too many redirects")
+			}
+			return CheckPublicURL(request.Context(), request.URL)
+		},
+	}
+}
+
+// CheckPublicURL vérifie que l'URL est en http ou https et que son hôte ne résout que vers des adresses publiques
+func CheckPublicURL(ctx context.Context, target *url.URL) error {
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %s", ErrForbiddenHost, target.Scheme)
+	}
+
+	host := target.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublicIP(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenHost, host)
+		}
+		return nil
+	}
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, address := range addresses {
+		if !IsPublicIP(address.IP) {
+			return fmt.Errorf("%w: %s", ErrForbiddenHost, host)
+		}
+	}
+	return nil
+}
+
+// sharedAddressSpace est la plage 100.64.0.0/10 des réseaux d'opérateurs (RFC 6598)
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicIP indique si l'adresse est routable sur Internet
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip))
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+)
+
+const (
+	icalLocalFormat = "20060102T150405"
+	icalUTCFormat   = "20060102T150405Z"
+	icalDateFormat  = "20060102"
+	icalUIDDomain   = "challenge-s4"
+)
+
+// CalendarLocation renvoie le fuseau horaire des flux iCalendar (CALENDAR_TIMEZONE, Europe/Paris par défaut)
+func CalendarLocation() *time.Location {
+	name := os.Getenv("CALENDAR_TIMEZONE")
+	if name == "" {
+		name = "Europe/Paris"
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// ICalUID construit un UID stable à partir de l'identifiant d'un événement
+func ICalUID(id string) string {
+	return fmt.Sprintf("%s@%s", id, icalUIDDomain)
+}
+
+// SetICalDateTime écrit une propriété date-heure (DTSTART, DTEND, EXDATE, RECURRENCE-ID...)
+// en heure locale du fuseau, référencé par son TZID
+func SetICalDateTime(component *ics.ComponentBase, property ics.ComponentProperty, date time.Time, location *time.Location) {
+	component.SetProperty(property, date.In(location).Format(icalLocalFormat), ics.WithTZID(location.String()))
+}
+
+// AddICalDateTime ajoute une propriété date-heure pouvant apparaître plusieurs fois (EXDATE)
+func AddICalDateTime(component *ics.ComponentBase, property ics.ComponentProperty, date time.Time, location *time.Location) {
+	component.AddProperty(property, date.In(location).Format(icalLocalFormat), ics.WithTZID(location.String()))
+}
+
+// ParseICalDateTimes lit les valeurs date-heure d'une propriété (DTSTART, EXDATE, RECURRENCE-ID...).
+// Les heures flottantes et les TZID inconnus (fuseaux Windows notamment) sont interprétés dans fallback.
+func ParseICalDateTimes(property *ics.IANAProperty, fallback *time.Location) ([]time.Time, error) {
+	location := fallback
+	if tzid, ok := property.ICalParameters[string(ics.ParameterTzid)]; ok && len(tzid) == 1 {
+		if tzLocation, err := time.LoadLocation(tzid[0]); err == nil {
+			location = tzLocation
+		}
+	}
+
+	var dates []time.Time
+	for _, value := range strings.Split(property.Value, ",") {
+		value = strings.TrimSpace(value)
+
+		var date time.Time
+		var err error
+		switch {
+		case strings.HasSuffix(value, "Z"):
+			date, err = time.Parse(icalUTCFormat, value)
+		case len(value) == len(icalDateFormat):
+			date, err = time.ParseInLocation(icalDateFormat, value, location)
+		default:
+			date, err = time.ParseInLocation(icalLocalFormat, value, location)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid iCalendar date %q: %w", value, err)
+		}
+		dates = append(dates, date)
+	}
+
+	return dates, nil
+}
+
+// AddVTimezone ajoute au calendrier la définition VTIMEZONE du fuseau, avec une observance
+// pour chaque changement d'heure survenant entre from et to
+func AddVTimezone(calendar *ics.Calendar, location *time.Location, from, to time.Time) {
+	timezone := calendar.AddTimezone(location.String())
+
+	_, offset := from.In(location).Zone()
+	addTimezoneObservance(timezone, from.In(location), offset)
+
+	for day := from; day.Before(to); day = day.Add(24 * time.Hour) {
+		next := day.Add(24 * time.Hour)
+		_, dayOffset := day.In(location).Zone()
+		_, nextOffset := next.In(location).Zone()
+		if dayOffset == nextOffset {
+			continue
+		}
+
+		// Recherche dichotomique de l'instant exact du changement d'heure
+		low, high := day, next
+		for high.Sub(low) > time.Second {
+			middle := low.Add(high.Sub(low) / 2)
+			if _, middleOffset := middle.In(location).Zone(); middleOffset == dayOffset {
+				low = middle
+			} else {
+				high = middle
+			}
+		}
+
+		addTimezoneObservance(timezone, high.In(location), dayOffset)
+	}
+}
+
+func addTimezoneObservance(timezone *ics.VTimezone, transition time.Time, offsetFrom int) {
+	name, offsetTo := transition.Zone()
+
+	observance := ics.ComponentBase{}
+	// DTSTART d'une observance s'exprime dans l'heure locale en vigueur avant la transition
+	observance.SetProperty(ics.ComponentPropertyDtStart, transition.UTC().Add(time.Duration(offsetFrom)*time.Second).Format(icalLocalFormat))
+	observance.SetProperty(ics.ComponentProperty(ics.PropertyTzoffsetfrom), formatICalOffset(offsetFrom))
+	observance.SetProperty(ics.ComponentProperty(ics.PropertyTzoffsetto), formatICalOffset(offsetTo))
+	observance.SetProperty(ics.ComponentProperty(ics.PropertyTzname), name)
+
+	if transition.IsDST() {
+		timezone.Components = append(timezone.Components, &ics.Daylight{ComponentBase: observance})
+	} else {
+		timezone.Components = append(timezone.Components, &ics.Standard{ComponentBase: observance})
+	}
+}
+
+func formatICalOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, offset/3600, (offset%3600)/60)
+}
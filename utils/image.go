@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	// Décodeurs des formats acceptés par IsImage
+	_ "image/gif"
+	_ "image/png"
+)
+
+const thumbnailQuality = 80
+
+// ImageDimensions renvoie la largeur et la hauteur de l'image sans la décoder entièrement
+func ImageDimensions(data []byte) (int, int, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	return config.Width, config.Height, nil
+}
+
+// GenerateThumbnail réduit l'image pour que son plus grand côté mesure au plus maxSide pixels et l'encode en JPEG.
+// Chaque pixel de la miniature est la moyenne des pixels de l'image d'origine qu'il recouvre.
+func GenerateThumbnail(data []byte, maxSide int) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	thumbWidth, thumbHeight := width, height
+	if width > maxSide || height > maxSide {
+		if width >= height {
+			thumbWidth, thumbHeight = maxSide, max(1, height*maxSide/width)
+		} else {
+			thumbWidth, thumbHeight = max(1, width*maxSide/height), maxSide
+		}
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, thumbWidth, thumbHeight))
+	for y := 0; y < thumbHeight; y++ {
+		y0 := bounds.Min.Y + y*height/thumbHeight
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*height/thumbHeight)
+		for x := 0; x < thumbWidth; x++ {
+			x0 := bounds.Min.X + x*width/thumbWidth
+			x1 := max(x0+1, bounds.Min.X+(x+1)*width/thumbWidth)
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					count++
+				}
+			}
+			// Le JPEG n'a pas de transparence : les zones transparentes sont rendues sur fond blanc
+			transparency := 0xffff - a/count
+			thumbnail.Set(x, y, color.RGBA64{
+				R: uint16(r/count + transparency),
+				G: uint16(g/count + transparency),
+				B: uint16(b/count + transparency),
+				A: 0xffff,
+			})
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, thumbnail, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
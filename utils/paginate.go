@@ -64,3 +64,22 @@ func PaginationFromContext(ctx echo.Context) Pagination {
 
 	return *pagination
 }
+
+// PaginateSlice applique la pagination à une liste déjà chargée en mémoire,
+// par exemple lorsque les occurrences d'événements récurrents sont calculées à la lecture
+func PaginateSlice[T any](rows []T, pagination *Pagination) []T {
+	total := len(rows)
+	pagination.Total = int64(total)
+	pagination.Pages = int(math.Ceil(float64(total) / float64(pagination.GetLimit())))
+
+	start := pagination.GetOffset()
+	if start > total {
+		start = total
+	}
+	end := start + pagination.GetLimit()
+	if end > total {
+		end = total
+	}
+
+	return rows[start:end]
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// ParseRecurrenceRule analyse une règle RRULE (RFC 5545), avec ou sans le préfixe "RRULE:",
+// et l'ancre sur la date de début de la série
+func ParseRecurrenceRule(rule string, dtstart time.Time) (*rrule.RRule, error) {
+	option, err := rrule.StrToROptionInLocation(strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:"), dtstart.Location())
+	if err != nil {
+		return nil, err
+	}
+
+	// Les fréquences infra-journalières généreraient des milliers d'occurrences
+	switch option.Freq {
+	case rrule.DAILY, rrule.WEEKLY, rrule.MONTHLY, rrule.YEARLY:
+	default:
+		return nil, fmt.Errorf("unsupported recurrence frequency: %v", option.Freq)
+	}
+
+	option.Dtstart = dtstart
+	return rrule.NewRRule(*option)
+}
+
+// NormalizeRecurrenceRule renvoie la règle sous sa forme canonique, sans DTSTART ni préfixe
+func NormalizeRecurrenceRule(rule string, dtstart time.Time) (string, error) {
+	r, err := ParseRecurrenceRule(rule, dtstart)
+	if err != nil {
+		return "", err
+	}
+	return r.OrigOptions.RRuleString(), nil
+}
+
+// RecurrenceOccurrences renvoie les occurrences de la série comprises entre from et to (inclus),
+// en retirant les dates d'exception (EXDATE)
+func RecurrenceOccurrences(rule string, dtstart time.Time, exdates []time.Time, from, to time.Time) ([]time.Time, error) {
+	r, err := ParseRecurrenceRule(rule, dtstart)
+	if err != nil {
+		return nil, err
+	}
+
+	set := rrule.Set{}
+	set.RRule(r)
+	for _, exdate := range exdates {
+		set.ExDate(exdate.In(dtstart.Location()))
+	}
+
+	return set.Between(from, to, true), nil
+}
+
+// IsRecurrenceOccurrence indique si la date correspond exactement à une occurrence de la série
+func IsRecurrenceOccurrence(rule string, dtstart time.Time, exdates []time.Time, date time.Time) (bool, error) {
+	occurrences, err := RecurrenceOccurrences(rule, dtstart, exdates, date, date)
+	if err != nil {
+		return false, err
+	}
+	return len(occurrences) == 1, nil
+}
+
+// SplitRecurrenceRule coupe une série à la date donnée : la première règle s'arrête juste avant
+// splitAt, la seconde reprend à splitAt avec le nombre d'occurrences restantes si COUNT est utilisé
+func SplitRecurrenceRule(rule string, dtstart, splitAt time.Time) (before string, after string, err error) {
+	r, err := ParseRecurrenceRule(rule, dtstart)
+	if err != nil {
+		return "", "", err
+	}
+
+	beforeOption := r.OrigOptions
+	afterOption := r.OrigOptions
+	beforeOption.Dtstart = time.Time{}
+	afterOption.Dtstart = time.Time{}
+
+	if r.OrigOptions.Count > 0 {
+		elapsed := len(r.Between(dtstart, splitAt, true)) - 1
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		beforeOption.Count = elapsed
+		afterOption.Count = r.OrigOptions.Count - elapsed
+	} else {
+		beforeOption.Until = splitAt.Add(-time.Second).UTC()
+	}
+
+	return beforeOption.RRuleString(), afterOption.RRuleString(), nil
+}